@@ -2,27 +2,61 @@ package main
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/shengyanli1982/lockfree/deque"
+	"github.com/shengyanli1982/lockfree/hashmap"
 	shd "github.com/shengyanli1982/lockfree/internal/shared"
+	"github.com/shengyanli1982/lockfree/priorityqueue"
 	"github.com/shengyanli1982/lockfree/queue"
 	"github.com/shengyanli1982/lockfree/ringbuffer"
+	"github.com/shengyanli1982/lockfree/skiplist"
 	"github.com/shengyanli1982/lockfree/stack"
+	"github.com/shengyanli1982/lockfree/workstealing"
 	memalign "github.com/vearne/mem-align"
 )
 
+// printCacheLines 打印结构体每个字段的偏移量、大小以及所在的缓存行，填充字段只打印它占用的字节范围
+// printCacheLines prints the offset, the size and the cache line of every field of a struct, padding fields only print the range of bytes they occupy
+func printCacheLines(name string, v interface{}) {
+	t := reflect.TypeOf(v)
+	fmt.Printf("%s cache lines (%d bytes per line, %d bytes total):\n\n", name, shd.CacheLineSize, t.Size())
+	fmt.Printf("%-8s %-12s %-8s %-6s %s\n", "LINE", "FIELDNAME", "OFFSET", "SIZE", "FIELDTYPE")
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		first := f.Offset / shd.CacheLineSize
+		last := (f.Offset + f.Type.Size() - 1) / shd.CacheLineSize
+		line := fmt.Sprintf("%d", first)
+		if last != first {
+			line = fmt.Sprintf("%d-%d", first, last)
+		}
+		fmt.Printf("%-8s %-12s %-8d %-6d %s\n", line, f.Name, f.Offset, f.Type.Size(), f.Type)
+	}
+}
+
 func main() {
 	fmt.Printf("Node alignment:\n\n")
 	memalign.PrintStructAlignment(shd.Node{})
-	fmt.Printf("\n =========================== \n\n")
-
-	fmt.Printf("Queue alignment:\n\n")
-	memalign.PrintStructAlignment(queue.LockFreeQueue{})
-	fmt.Printf("\n =========================== \n\n")
-
-	fmt.Printf("Stack alignment:\n\n")
-	memalign.PrintStructAlignment(stack.LockFreeStack{})
-	fmt.Printf("\n =========================== \n\n")
 
-	fmt.Printf("RingBuffer alignment:\n\n")
-	memalign.PrintStructAlignment(ringbuffer.LockFreeRingBuffer{})
+	containers := []struct {
+		name  string
+		value interface{}
+	}{
+		{"Queue", queue.LockFreeQueue{}},
+		{"MPSCQueue", queue.LockFreeMPSCQueue{}},
+		{"SPMCQueue", queue.LockFreeSPMCQueue{}},
+		{"SegmentedQueue", queue.LockFreeSegmentedQueue{}},
+		{"Stack", stack.LockFreeStack{}},
+		{"RingBuffer", ringbuffer.LockFreeRingBuffer{}},
+		{"SPSCRingBuffer", ringbuffer.LockFreeSPSCRingBuffer{}},
+		{"Deque", deque.LockFreeDeque{}},
+		{"WorkStealingDeque", workstealing.LockFreeWorkStealingDeque{}},
+		{"PriorityQueue", priorityqueue.LockFreePriorityQueue{}},
+		{"SkipList", skiplist.LockFreeSkipList{}},
+		{"HashMap", hashmap.LockFreeHashMap{}},
+	}
+	for _, c := range containers {
+		fmt.Printf("\n =========================== \n\n")
+		printCacheLines(c.name, c.value)
+	}
 }
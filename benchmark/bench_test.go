@@ -1,12 +1,21 @@
 package benchmark
 
 import (
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"unsafe"
 
+	"github.com/shengyanli1982/lockfree/deque"
+	"github.com/shengyanli1982/lockfree/hashmap"
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+	"github.com/shengyanli1982/lockfree/priorityqueue"
 	"github.com/shengyanli1982/lockfree/queue"
 	"github.com/shengyanli1982/lockfree/ringbuffer"
+	"github.com/shengyanli1982/lockfree/skiplist"
 	"github.com/shengyanli1982/lockfree/stack"
+	"github.com/shengyanli1982/lockfree/workstealing"
 )
 
 func BenchmarkStdChannel(b *testing.B) {
@@ -73,6 +82,219 @@ func BenchmarkLockFreeQueueParallel(b *testing.B) {
 	})
 }
 
+func BenchmarkLockFreeQueueAllocs(b *testing.B) {
+	// 每个入队的值都需要一个新节点
+	// Every pushed value needs a new node
+	q := queue.New()
+	v := new(int)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(v)
+		q.Pop()
+	}
+}
+
+func BenchmarkLockFreeQueueStatsDisabledParallel(b *testing.B) {
+	// 没有 WithStats 时，每次计数器更新只是一次 nil 检查
+	// Without WithStats every counter update is a single nil check
+	q := queue.NewOf[int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+			q.Pop()
+		}
+	})
+}
+
+func BenchmarkLockFreeQueueStatsEnabledParallel(b *testing.B) {
+	// 使用 WithStats 时计数器是分片的，不同 goroutine 的更新很少共享同一个缓存行
+	// With WithStats the counters are sharded, so the updates of different goroutines rarely share a cache line
+	q := queue.NewOf[int](queue.WithStats())
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+			q.Pop()
+		}
+	})
+}
+
+func BenchmarkLockFreeSegmentedQueue(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	q := queue.NewSegmented()
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.Push(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if _, ok := q.TryPop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeSegmentedQueueAllocs(b *testing.B) {
+	// 值直接保存在段中，每 1024 次入队才分配一个段，段用完之后会被回收
+	// Values are stored inline in the segments, a segment is only allocated once every 1024 pushes and is recycled once it is used up
+	q := queue.NewSegmented()
+	v := new(int)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(v)
+		q.Pop()
+	}
+}
+
+func BenchmarkLockFreeSegmentedQueueParallel(b *testing.B) {
+	q := queue.NewSegmented()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		v := new(int)
+		for pb.Next() {
+			q.Push(v)
+			q.Pop()
+		}
+	})
+}
+
+func BenchmarkLockFreeQueueManyProducersParallel(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	q := queue.New()
+	b.ResetTimer()
+
+	// 单个消费者弹出所有并行生产者推入的值
+	// A single consumer pops every value pushed by the parallel producers
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if q.Pop() != nil {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+		}
+	})
+	wg.Wait()
+}
+
+func BenchmarkLockFreeMPSCQueueParallel(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	q := queue.NewMPSC()
+	b.ResetTimer()
+
+	// 单个消费者弹出所有并行生产者推入的值
+	// A single consumer pops every value pushed by the parallel producers
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if q.Pop() != nil {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Push(1)
+		}
+	})
+	wg.Wait()
+}
+
+func BenchmarkLockFreeQueueManyConsumersParallel(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	q := queue.New()
+	b.ResetTimer()
+
+	// 单个生产者推入被并行消费者弹出的值
+	// A single producer pushes the values popped by the parallel consumers
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.Push(1)
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for q.Pop() == nil {
+				runtime.Gosched()
+			}
+		}
+	})
+	wg.Wait()
+}
+
+func BenchmarkLockFreeSPMCQueueParallel(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	q := queue.NewSPMC()
+	b.ResetTimer()
+
+	// 单个生产者推入被并行消费者弹出的值
+	// A single producer pushes the values popped by the parallel consumers
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.Push(1)
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for q.Pop() == nil {
+				runtime.Gosched()
+			}
+		}
+	})
+	wg.Wait()
+}
+
+func BenchmarkLockFreeQueueBatchParallel(b *testing.B) {
+	q := queue.New()
+	values := []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		dst := make([]interface{}, len(values))
+		for pb.Next() {
+			q.PushBatch(values)
+			q.PopBatch(dst)
+		}
+	})
+}
+
 func BenchmarkLockFreeStack(b *testing.B) {
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -105,6 +327,20 @@ func BenchmarkLockFreeStackParallel(b *testing.B) {
 	})
 }
 
+func BenchmarkLockFreeStackBatchParallel(b *testing.B) {
+	q := stack.New()
+	values := []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		dst := make([]interface{}, len(values))
+		for pb.Next() {
+			q.PushBatch(values)
+			q.PopBatch(dst)
+		}
+	})
+}
+
 func BenchmarkLockFreeRingBuffer(b *testing.B) {
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -136,3 +372,637 @@ func BenchmarkLockFreeRingBufferParallel(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkLockFreeRingBufferAllocs(b *testing.B) {
+	// 指针形状的值直接保存在槽中，因此 Push 和 Pop 不会分配内存
+	// Pointer-shaped values are stored inline in the slots, so Push and Pop do not allocate
+	r := ringbuffer.New(1024)
+	v := new(int)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(v)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeRingBufferOfAllocs(b *testing.B) {
+	r := ringbuffer.NewOf[int](1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeRingBufferAllocsParallel(b *testing.B) {
+	r := ringbuffer.New(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		v := new(int)
+		for pb.Next() {
+			r.Push(v)
+			r.Pop()
+		}
+	})
+}
+
+func BenchmarkLockFreeRingBufferNew(b *testing.B) {
+	// 槽随环一起分配，创建时不会为每个槽分配一个节点
+	// The slots are allocated with the ring, creating it does not allocate a node for every slot
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ringbuffer.NewOf[int](1024)
+	}
+}
+
+func BenchmarkLockFreeRingBufferModIndex(b *testing.B) {
+	// 1000 不是 2 的幂，通过取模和除法定位槽
+	// 1000 is not a power of two, the slots are found with modulo and division
+	r := ringbuffer.NewOf[int](1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeRingBufferMaskIndex(b *testing.B) {
+	// 容量向上取整为 1024，通过掩码和移位定位槽
+	// The capacity is rounded up to 1024, the slots are found with a mask and a shift
+	r := ringbuffer.NewOf[int](1000, ringbuffer.WithPowerOfTwo())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeRingBufferPipeline(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	r := ringbuffer.New(1024)
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if _, ok := r.Pop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeSPSCRingBuffer(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	r := ringbuffer.NewSPSC(b.N)
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			r.Push(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			r.Pop()
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeSPSCRingBufferPipeline(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	r := ringbuffer.NewSPSC(1024)
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if _, ok := r.Pop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeRingBufferOfPipeline(b *testing.B) {
+	// 与 BenchmarkLockFreeSPSCRingBufferOfPipeline 使用相同的元素类型、容量和生产者消费者，只有缓冲区的实现不同
+	// Uses the same element type, capacity, producer and consumer as BenchmarkLockFreeSPSCRingBufferOfPipeline, only the implementation of the buffer differs
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	r := ringbuffer.NewOf[int](1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if _, ok := r.Pop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeSPSCRingBufferOfPipeline(b *testing.B) {
+	// 单生产者单消费者缓冲区不需要 CAS，也不需要更新槽位的序号，双方只在缓存的位置显示已满或为空时才读取对方的位置
+	// The single-producer single-consumer buffer needs no CAS and no slot sequence updates, each side only loads the position of the other side when the cached position shows the buffer as full or empty
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	r := ringbuffer.NewSPSCOf[int](1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; {
+			if _, ok := r.Pop(); ok {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeRingBufferOfPushPop(b *testing.B) {
+	// 没有竞争时一次推入和弹出的开销，每次操作都要 CAS 头部或尾部位置并更新槽位的序号
+	// The cost of a push and a pop without contention, every operation has to CAS the head or tail position and update the sequence of the slot
+	r := ringbuffer.NewOf[int](1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeSPSCRingBufferOfPushPop(b *testing.B) {
+	// 没有竞争时一次推入和弹出的开销，每次操作只有一次原子写入
+	// The cost of a push and a pop without contention, every operation is a single atomic store
+	r := ringbuffer.NewSPSCOf[int](1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+		r.Pop()
+	}
+}
+
+func BenchmarkLockFreeRingBufferBatchParallel(b *testing.B) {
+	r := ringbuffer.New(1024)
+	values := []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		dst := make([]interface{}, len(values))
+		for pb.Next() {
+			r.PushBatch(values)
+			r.PopBatch(dst)
+		}
+	})
+}
+
+func BenchmarkLockFreeDeque(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	q := deque.New()
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.PushBack(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.PopFront()
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreeDequeParallel(b *testing.B) {
+	q := deque.New()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.PushBack(1)
+			q.PopFront()
+		}
+	})
+}
+
+func BenchmarkLockFreeWorkStealing(b *testing.B) {
+	q := workstealing.New(0)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+}
+
+func BenchmarkLockFreeWorkStealingAllocs(b *testing.B) {
+	// 值直接保存在环形数组中，推入和弹出不分配内存
+	// Values are stored directly in the circular array, pushing and popping do not allocate
+	q := workstealing.NewOf[int](0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+}
+
+func BenchmarkLockFreeWorkStealingStealAllocs(b *testing.B) {
+	// 窃取同样不分配内存
+	// Stealing does not allocate either
+	q := workstealing.NewOf[int](0)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		q.Steal()
+	}
+}
+
+func BenchmarkLockFreeWorkStealingParallel(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	done := int32(0)
+	q := workstealing.New(0)
+	b.ResetTimer()
+
+	// 所有者持续推入，其他 goroutine 进行窃取
+	// The owner keeps pushing while the other goroutines steal
+	go func() {
+		defer wg.Done()
+		for i := 0; atomic.LoadInt32(&done) == 0; i++ {
+			q.Push(i)
+			if q.Length() > 1024 {
+				q.Pop()
+			}
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Steal()
+		}
+	})
+	atomic.StoreInt32(&done, 1)
+	wg.Wait()
+}
+
+func BenchmarkLockFreePriorityQueue(b *testing.B) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	q := priorityqueue.New()
+	b.ResetTimer()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.Push(i, int64(i&1023))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < b.N; i++ {
+			q.PopMin()
+		}
+	}()
+	wg.Wait()
+}
+
+func BenchmarkLockFreePriorityQueueAllocs(b *testing.B) {
+	// 没有节点池时，每个节点分配节点本身和它的链接两次
+	// Without a node pool every node allocates twice, the node itself and its links
+	q := priorityqueue.NewOf[int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(i, int64(i&1023))
+		q.PopMin()
+	}
+}
+
+func BenchmarkLockFreePriorityQueueWithPoolAllocs(b *testing.B) {
+	// 删除标记保存在节点的链接中，CAS 操作不分配内存，节点池预热之后推入和弹出都不分配内存
+	// The deletion marks live in the links of the node and CAS operations do not allocate, so once the pool is warm pushing and popping do not allocate
+	q := priorityqueue.NewWithPoolOf[int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Push(i, int64(i&1023))
+		q.PopMin()
+	}
+}
+
+func BenchmarkLockFreePriorityQueueParallel(b *testing.B) {
+	q := priorityqueue.New()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := int64(0)
+		for pb.Next() {
+			q.Push(1, i&1023)
+			q.PopMin()
+			i++
+		}
+	})
+}
+
+func BenchmarkStdSyncMapLoadParallel(b *testing.B) {
+	m := sync.Map{}
+	b.ReportAllocs()
+	for i := 0; i < 1024; i++ {
+		m.Store(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Load(i & 1023)
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeHashMapLoadParallel(b *testing.B) {
+	m := hashmap.NewOf[int, int]()
+	b.ReportAllocs()
+	for i := 0; i < 1024; i++ {
+		m.Store(i, i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Load(i & 1023)
+			i++
+		}
+	})
+}
+
+func BenchmarkStdSyncMapStoreParallel(b *testing.B) {
+	m := sync.Map{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Store(i&1023, i)
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeHashMapStoreParallel(b *testing.B) {
+	m := hashmap.NewOf[int, int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Store(i&1023, i)
+			i++
+		}
+	})
+}
+
+func BenchmarkStdSyncMapMixedParallel(b *testing.B) {
+	m := sync.Map{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			switch i & 7 {
+			case 0:
+				m.Store(i&1023, i)
+			case 1:
+				m.Delete(i & 1023)
+			default:
+				m.Load(i & 1023)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeHashMapMixedParallel(b *testing.B) {
+	m := hashmap.NewOf[int, int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			switch i & 7 {
+			case 0:
+				m.Store(i&1023, i)
+			case 1:
+				m.Delete(i & 1023)
+			default:
+				m.Load(i & 1023)
+			}
+			i++
+		}
+	})
+}
+
+func compareInt(a, b interface{}) int {
+	return a.(int) - b.(int)
+}
+
+func BenchmarkLockFreeSkipListInsert(b *testing.B) {
+	s := skiplist.New(compareInt)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Insert(i)
+	}
+}
+
+func BenchmarkLockFreeSkipListWithPoolAllocs(b *testing.B) {
+	// 删除标记保存在节点的链接中，CAS 操作不分配内存，节点池预热之后插入和删除都不分配内存
+	// The deletion marks live in the links of the node and CAS operations do not allocate, so once the pool is warm inserting and deleting do not allocate
+	s := skiplist.NewWithPoolOf(func(a, b int) int { return a - b })
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Insert(i & 1023)
+		s.Delete(i & 1023)
+	}
+}
+
+func BenchmarkLockFreeSkipListAscend(b *testing.B) {
+	s := skiplist.NewOf(func(a, b int) int { return a - b })
+	for i := 0; i < 1024; i++ {
+		s.Insert(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Ascend(func(int) bool { return true })
+	}
+}
+
+func BenchmarkLockFreeSkipListDescend(b *testing.B) {
+	// 降序遍历从后往前访问每一层上两个节点之间的节点，开销与升序遍历处于同一数量级
+	// The descending walk visits the nodes between two nodes of the level above backwards, so it costs the same order as the ascending walk
+	s := skiplist.NewOf(func(a, b int) int { return a - b })
+	for i := 0; i < 1024; i++ {
+		s.Insert(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Descend(func(int) bool { return true })
+	}
+}
+
+func BenchmarkLockFreeSkipListContainsParallel(b *testing.B) {
+	s := skiplist.New(compareInt)
+	for i := 0; i < 1024; i++ {
+		s.Insert(i)
+	}
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Contains(i & 1023)
+			i++
+		}
+	})
+}
+
+func BenchmarkLockFreeSkipListMixedParallel(b *testing.B) {
+	s := skiplist.New(compareInt)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			switch i & 7 {
+			case 0:
+				s.Insert(i & 1023)
+			case 1:
+				s.Delete(i & 1023)
+			default:
+				s.Contains(i & 1023)
+			}
+			i++
+		}
+	})
+}
+
+// benchmarkProducersConsumers 让一半的 worker 只调用 push，另一半只调用 pop，
+// 生产者只修改尾部，消费者只修改头部，它们之间的竞争主要来自头尾字段是否共享缓存行。
+// 分别在默认构建和 -tags lockfree_nopad 构建下运行，就能比较有填充和没有填充的容器。
+// benchmarkProducersConsumers lets half of the workers only call push and the other half only call pop,
+// producers only modify the tail and consumers only modify the head, so the contention between them mostly depends on whether the head and tail fields share a cache line.
+// Run it once with the default build and once with -tags lockfree_nopad to compare the padded and the unpadded containers.
+func benchmarkProducersConsumers(b *testing.B, push func(int), pop func()) {
+	worker := int64(0)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		if atomic.AddInt64(&worker, 1)%2 == 0 {
+			for i := 0; pb.Next(); i++ {
+				push(i)
+			}
+			return
+		}
+		for pb.Next() {
+			pop()
+		}
+	})
+
+	// 报告填充的大小，方便区分两次运行的结果
+	// Report the size of the padding to tell the results of the two runs apart
+	b.ReportMetric(float64(unsafe.Sizeof(shd.CacheLinePad{})), "pad-bytes")
+}
+
+func BenchmarkPaddingLockFreeQueueParallel(b *testing.B) {
+	q := queue.NewOf[int]()
+	benchmarkProducersConsumers(b, func(v int) { q.Push(v) }, func() { q.Pop() })
+}
+
+func BenchmarkPaddingLockFreeSegmentedQueueParallel(b *testing.B) {
+	q := queue.NewSegmentedOf[int]()
+	benchmarkProducersConsumers(b, func(v int) { q.Push(v) }, func() { q.Pop() })
+}
+
+func BenchmarkPaddingLockFreeRingBufferParallel(b *testing.B) {
+	// 缓冲区满时 Push 直接失败，空时 Pop 直接失败，两者都不会阻塞
+	// Push fails right away when the buffer is full and Pop fails right away when it is empty, neither of them blocks
+	r := ringbuffer.NewOf[int](1024)
+	benchmarkProducersConsumers(b, func(v int) { r.Push(v) }, func() { r.Pop() })
+}
+
+func BenchmarkPaddingLockFreeDequeParallel(b *testing.B) {
+	d := deque.NewOf[int]()
+	benchmarkProducersConsumers(b, func(v int) { d.PushBack(v) }, func() { d.PopFront() })
+}
@@ -0,0 +1,527 @@
+package deque
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+const (
+	// stable 表示双端队列的链表是一致的，可以直接进行推入和弹出
+	// stable means the list of the deque is consistent, pushes and pops can proceed directly
+	stable int32 = iota
+
+	// rpush 表示刚刚在右端推入了一个节点，它左侧节点的 right 指针可能还没有指向它
+	// rpush means a node has just been pushed at the right end, the right pointer of its left node may not point to it yet
+	rpush
+
+	// lpush 表示刚刚在左端推入了一个节点，它右侧节点的 left 指针可能还没有指向它
+	// lpush means a node has just been pushed at the left end, the left pointer of its right node may not point to it yet
+	lpush
+)
+
+// anchorOf 是双端队列的锚点，记录两端的节点以及链表的状态。
+// 锚点是不可变的，每次修改都会创建一个新的锚点，并通过一次 CAS 操作替换，因此两端的节点和状态总是一起被修改。
+// anchorOf is the anchor of the deque, it records the nodes at both ends and the state of the list.
+// An anchor is immutable, every change creates a new anchor and replaces it with a single CAS operation, so the nodes at both ends and the state are always changed together.
+type anchorOf[T any] struct {
+	// left 是最左侧的节点，双端队列为空时为 nil
+	// left is the leftmost node, it is nil when the deque is empty
+	left *nodeOf[T]
+
+	// right 是最右侧的节点，双端队列为空时为 nil
+	// right is the rightmost node, it is nil when the deque is empty
+	right *nodeOf[T]
+
+	// status 是链表的状态，取值为 stable、rpush 或 lpush
+	// status is the state of the list, it is one of stable, rpush or lpush
+	status int32
+}
+
+// LockFreeDequeOf 是一个泛型无锁双端队列结构体，T 为双端队列中元素的类型。
+// 它基于 Michael 的锚点算法：两端的推入和弹出都只需要对锚点进行一次 CAS 操作，推入之后再修复相邻节点的指针。
+// LockFreeDequeOf is a generic lock-free deque struct, T is the type of the elements in the deque.
+// It is based on Michael's anchor algorithm: pushes and pops at both ends only need a single CAS operation on the anchor, and the pointer of the neighbouring node is fixed up after a push.
+type LockFreeDequeOf[T any] struct {
+	// length 是双端队列的长度
+	// length is the length of the deque
+	length int64
+
+	// _ 用于把长度与锚点指针分隔到不同的缓存行
+	// _ separates the length from the anchor pointer onto different cache lines
+	_ shd.CacheLinePad
+
+	// anchor 是指向当前锚点的指针
+	// anchor is a pointer to the current anchor
+	anchor unsafe.Pointer
+
+	// _ 用于把两端共同争用的锚点指针与之后只读的字段分隔到不同的缓存行
+	// _ separates the anchor pointer, contended by both ends, from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// pool 是一个节点池，用于存储和获取节点
+	// pool is a node pool used to store and retrieve nodes
+	pool *nodePoolOf[T]
+
+	// reclaimer 是节点回收器，仅在使用节点池时启用，保证节点在没有 goroutine 引用后才会被复用
+	// reclaimer is the node reclaimer, only enabled with a node pool, it ensures nodes are reused only after no goroutine references them
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// NewOf 函数用于创建一个新的泛型 LockFreeDequeOf 双端队列，可以通过选项修改双端队列的行为
+// The NewOf function is used to create a new generic LockFreeDequeOf deque, the behavior of the deque can be modified with options
+func NewOf[T any](opts ...Option) *LockFreeDequeOf[T] {
+	// 调用 newLFD 函数创建一个新的 LockFreeDequeOf 双端队列，是否使用节点池由选项决定
+	// Call the newLFD function to create a new LockFreeDequeOf deque, whether a node pool is used is decided by the options
+	return newLFD[T](opts)
+}
+
+// NewWithPoolOf 函数用于创建一个新的泛型 LockFreeDequeOf 双端队列，该双端队列使用一个节点池，可以通过选项修改双端队列的行为。
+// 它是 NewOf 加上 WithPool 选项的简写
+// The NewWithPoolOf function is used to create a new generic LockFreeDequeOf deque, this deque uses a node pool, the behavior of the deque can be modified with options.
+// It is a shortcut for NewOf with the WithPool option
+func NewWithPoolOf[T any](opts ...Option) *LockFreeDequeOf[T] {
+	return newLFD[T](append([]Option{WithPool()}, opts...))
+}
+
+// newLFD 函数用于创建一个新的 LockFreeDequeOf 双端队列，参数为所有的选项
+// The newLFD function is used to create a new LockFreeDequeOf deque, the parameter is all the options
+func newLFD[T any](opts []Option) *LockFreeDequeOf[T] {
+	// 应用所有的选项，如果启用了节点池，那么创建节点池
+	// Apply all the options, if the node pool is enabled, then create the node pool
+	conf := newConfig(opts)
+	var pool *nodePoolOf[T]
+	if conf.pool {
+		pool = newNodePoolOf[T]()
+	}
+
+	// 创建一个新的 LockFreeDequeOf 双端队列，锚点为空并且是稳定的
+	// Create a new LockFreeDequeOf deque, the anchor is empty and stable
+	d := &LockFreeDequeOf[T]{
+		pool:   pool,
+		anchor: unsafe.Pointer(&anchorOf[T]{}),
+	}
+
+	// 如果使用节点池，那么创建节点回收器，宽限期结束后节点会被放回节点池
+	// If a node pool is used, then create the node reclaimer, nodes are put back into the node pool once their grace period is over
+	if pool != nil {
+		d.reclaimer = shd.NewReclaimer(
+			func(p unsafe.Pointer) *unsafe.Pointer { return &(*nodeOf[T])(p).link },
+			func(p unsafe.Pointer) { pool.put((*nodeOf[T])(p)) },
+		)
+	}
+
+	// 设置退避策略，如果启用了统计，那么创建统计计数器，节点池也使用同一组计数器
+	// Set the backoff strategy, if statistics are enabled, then create the statistics counters, the node pool uses the same counters
+	d.backoff = conf.backoff
+	if conf.stats {
+		d.stats = shd.NewCounters()
+		if pool != nil {
+			pool.stats = d.stats
+		}
+	}
+
+	// 返回新创建的双端队列
+	// Return the newly created deque
+	return d
+}
+
+// loadAnchor 方法用于加载当前的锚点
+// The loadAnchor method is used to load the current anchor
+func (d *LockFreeDequeOf[T]) loadAnchor() *anchorOf[T] {
+	return (*anchorOf[T])(atomic.LoadPointer(&d.anchor))
+}
+
+// casAnchor 方法用于比较并交换锚点，失败时记录一次 CAS 失败。attempt 不为 nil 时，调用者会重试，按退避策略等待之后再返回
+// The casAnchor method is used to compare and swap the anchor, a failure is counted as a CAS failure. When attempt is not nil the caller retries, so it waits according to the backoff strategy before returning
+func (d *LockFreeDequeOf[T]) casAnchor(old, new *anchorOf[T], attempt *int) bool {
+	if atomic.CompareAndSwapPointer(&d.anchor, unsafe.Pointer(old), unsafe.Pointer(new)) {
+		return true
+	}
+	d.stats.Inc(shd.CounterCASFailures)
+	if attempt != nil {
+		d.backoff.Wait(attempt)
+	}
+	return false
+}
+
+// newNode 方法用于创建一个保存 value 的新节点，如果使用节点池，那么从节点池中获取
+// The newNode method is used to create a new node holding value, the node is taken from the node pool if one is used
+func (d *LockFreeDequeOf[T]) newNode(value T) *nodeOf[T] {
+	var node *nodeOf[T]
+	if d.pool != nil {
+		node = d.pool.get()
+	} else {
+		node = &nodeOf[T]{}
+	}
+	node.value = value
+	return node
+}
+
+// release 方法用于释放被移除的节点。如果使用节点池，那么退休节点，否则交给 GC 回收，其他 goroutine 可能仍在读取它
+// The release method is used to release a removed node. If a node pool is used, the node is retired, otherwise it is left to the GC, other goroutines may still be reading it
+func (d *LockFreeDequeOf[T]) release(node *nodeOf[T]) {
+	if d.pool != nil {
+		d.reclaimer.Retire(unsafe.Pointer(node))
+	}
+}
+
+// PushBack 方法用于向双端队列的尾部 (右端) 添加一个元素
+// The PushBack method is used to add an element to the back (right end) of the deque
+func (d *LockFreeDequeOf[T]) PushBack(value T) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if d.reclaimer != nil {
+		guard := d.reclaimer.Enter()
+		defer d.reclaimer.Exit(guard)
+	}
+
+	node := d.newNode(value)
+	attempt := 0
+	for {
+		anchor := d.loadAnchor()
+
+		if anchor.right == nil {
+			// 双端队列为空，新节点同时成为两端的节点
+			// The deque is empty, the new node becomes the node at both ends
+			if d.casAnchor(anchor, &anchorOf[T]{left: node, right: node}, &attempt) {
+				break
+			}
+		} else if anchor.status == stable {
+			// 链表是一致的，把新节点挂在最右侧节点的右边，然后用一次 CAS 操作让它成为最右侧的节点
+			// The list is consistent, hang the new node to the right of the rightmost node, then make it the rightmost node with a single CAS operation
+			atomic.StorePointer(&node.left, unsafe.Pointer(anchor.right))
+			next := &anchorOf[T]{left: anchor.left, right: node, status: rpush}
+			if d.casAnchor(anchor, next, &attempt) {
+				// 修复原最右侧节点的 right 指针
+				// Fix the right pointer of the previous rightmost node
+				d.stabilizeRight(next)
+				break
+			}
+		} else {
+			// 上一次推入还没有完成修复，先帮助它完成
+			// The previous push has not been fixed up yet, help it to finish first
+			d.stabilize(anchor)
+		}
+	}
+
+	// 增加双端队列的长度
+	// Increase the length of the deque
+	atomic.AddInt64(&d.length, 1)
+	d.stats.Inc(shd.CounterPushes)
+}
+
+// PushFront 方法用于向双端队列的头部 (左端) 添加一个元素
+// The PushFront method is used to add an element to the front (left end) of the deque
+func (d *LockFreeDequeOf[T]) PushFront(value T) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if d.reclaimer != nil {
+		guard := d.reclaimer.Enter()
+		defer d.reclaimer.Exit(guard)
+	}
+
+	node := d.newNode(value)
+	attempt := 0
+	for {
+		anchor := d.loadAnchor()
+
+		if anchor.left == nil {
+			// 双端队列为空，新节点同时成为两端的节点
+			// The deque is empty, the new node becomes the node at both ends
+			if d.casAnchor(anchor, &anchorOf[T]{left: node, right: node}, &attempt) {
+				break
+			}
+		} else if anchor.status == stable {
+			// 链表是一致的，把新节点挂在最左侧节点的左边，然后用一次 CAS 操作让它成为最左侧的节点
+			// The list is consistent, hang the new node to the left of the leftmost node, then make it the leftmost node with a single CAS operation
+			atomic.StorePointer(&node.right, unsafe.Pointer(anchor.left))
+			next := &anchorOf[T]{left: node, right: anchor.right, status: lpush}
+			if d.casAnchor(anchor, next, &attempt) {
+				// 修复原最左侧节点的 left 指针
+				// Fix the left pointer of the previous leftmost node
+				d.stabilizeLeft(next)
+				break
+			}
+		} else {
+			// 上一次推入还没有完成修复，先帮助它完成
+			// The previous push has not been fixed up yet, help it to finish first
+			d.stabilize(anchor)
+		}
+	}
+
+	// 增加双端队列的长度
+	// Increase the length of the deque
+	atomic.AddInt64(&d.length, 1)
+	d.stats.Inc(shd.CounterPushes)
+}
+
+// PopBack 方法用于从双端队列的尾部 (右端) 移除并返回一个元素，如果双端队列为空，返回 T 的零值和 false
+// The PopBack method is used to remove and return an element from the back (right end) of the deque, returns the zero value of T and false if the deque is empty
+func (d *LockFreeDequeOf[T]) PopBack() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if d.reclaimer != nil {
+		guard := d.reclaimer.Enter()
+		defer d.reclaimer.Exit(guard)
+	}
+
+	var node, prev *nodeOf[T]
+	attempt := 0
+	for {
+		anchor := d.loadAnchor()
+
+		if anchor.right == nil {
+			// 双端队列为空，返回 T 的零值和 false
+			// The deque is empty, return the zero value of T and false
+			d.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+
+		if anchor.right == anchor.left {
+			// 只有一个节点，移除之后双端队列为空
+			// There is only one node, the deque is empty after removing it
+			if d.casAnchor(anchor, &anchorOf[T]{}, &attempt) {
+				node, prev = anchor.right, nil
+				break
+			}
+		} else if anchor.status == stable {
+			// 链表是一致的，最右侧节点的左侧节点成为新的最右侧节点
+			// The list is consistent, the left node of the rightmost node becomes the new rightmost node
+			prev = (*nodeOf[T])(atomic.LoadPointer(&anchor.right.left))
+			if d.casAnchor(anchor, &anchorOf[T]{left: anchor.left, right: prev}, &attempt) {
+				node = anchor.right
+				break
+			}
+		} else {
+			// 上一次推入还没有完成修复，先帮助它完成
+			// The previous push has not been fixed up yet, help it to finish first
+			d.stabilize(anchor)
+		}
+	}
+
+	// 新的最右侧节点的 right 指针仍然指向被移除的节点，把它改为 nil，让被移除的节点和它的值可以被 GC 回收。
+	// 使用 CAS 操作而不是直接写入，避免覆盖之后的推入已经修复好的指针
+	// The right pointer of the new rightmost node still points to the removed node, set it to nil so the removed node and its value can be collected by the GC.
+	// A CAS operation is used instead of a plain store, so a pointer already fixed up by a later push is not overwritten
+	if prev != nil {
+		atomic.CompareAndSwapPointer(&prev.right, unsafe.Pointer(node), nil)
+	}
+
+	// 减少双端队列的长度，读取被移除节点的值并清空它，然后释放节点。只有 CAS 成功的 goroutine 会读取这个值
+	// Decrease the length of the deque, read the value of the removed node and clear it, then release the node. Only the goroutine whose CAS succeeded reads the value
+	atomic.AddInt64(&d.length, -1)
+	d.stats.Inc(shd.CounterPops)
+	value := node.value
+	var zero T
+	node.value = zero
+	d.release(node)
+	return value, true
+}
+
+// PopFront 方法用于从双端队列的头部 (左端) 移除并返回一个元素，如果双端队列为空，返回 T 的零值和 false
+// The PopFront method is used to remove and return an element from the front (left end) of the deque, returns the zero value of T and false if the deque is empty
+func (d *LockFreeDequeOf[T]) PopFront() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if d.reclaimer != nil {
+		guard := d.reclaimer.Enter()
+		defer d.reclaimer.Exit(guard)
+	}
+
+	var node, next *nodeOf[T]
+	attempt := 0
+	for {
+		anchor := d.loadAnchor()
+
+		if anchor.left == nil {
+			// 双端队列为空，返回 T 的零值和 false
+			// The deque is empty, return the zero value of T and false
+			d.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+
+		if anchor.left == anchor.right {
+			// 只有一个节点，移除之后双端队列为空
+			// There is only one node, the deque is empty after removing it
+			if d.casAnchor(anchor, &anchorOf[T]{}, &attempt) {
+				node, next = anchor.left, nil
+				break
+			}
+		} else if anchor.status == stable {
+			// 链表是一致的，最左侧节点的右侧节点成为新的最左侧节点
+			// The list is consistent, the right node of the leftmost node becomes the new leftmost node
+			next = (*nodeOf[T])(atomic.LoadPointer(&anchor.left.right))
+			if d.casAnchor(anchor, &anchorOf[T]{left: next, right: anchor.right}, &attempt) {
+				node = anchor.left
+				break
+			}
+		} else {
+			// 上一次推入还没有完成修复，先帮助它完成
+			// The previous push has not been fixed up yet, help it to finish first
+			d.stabilize(anchor)
+		}
+	}
+
+	// 新的最左侧节点的 left 指针仍然指向被移除的节点，把它改为 nil，让被移除的节点和它的值可以被 GC 回收。
+	// 使用 CAS 操作而不是直接写入，避免覆盖之后的推入已经修复好的指针
+	// The left pointer of the new leftmost node still points to the removed node, set it to nil so the removed node and its value can be collected by the GC.
+	// A CAS operation is used instead of a plain store, so a pointer already fixed up by a later push is not overwritten
+	if next != nil {
+		atomic.CompareAndSwapPointer(&next.left, unsafe.Pointer(node), nil)
+	}
+
+	// 减少双端队列的长度，读取被移除节点的值并清空它，然后释放节点。只有 CAS 成功的 goroutine 会读取这个值
+	// Decrease the length of the deque, read the value of the removed node and clear it, then release the node. Only the goroutine whose CAS succeeded reads the value
+	atomic.AddInt64(&d.length, -1)
+	d.stats.Inc(shd.CounterPops)
+	value := node.value
+	var zero T
+	node.value = zero
+	d.release(node)
+	return value, true
+}
+
+// stabilize 方法用于根据锚点的状态修复刚刚推入的节点与相邻节点之间的指针
+// The stabilize method is used to fix the pointer between the node just pushed and its neighbouring node according to the state of the anchor
+func (d *LockFreeDequeOf[T]) stabilize(anchor *anchorOf[T]) {
+	if anchor.status == rpush {
+		d.stabilizeRight(anchor)
+	} else {
+		d.stabilizeLeft(anchor)
+	}
+}
+
+// stabilizeRight 方法用于让最右侧节点的左侧节点的 right 指针指向最右侧节点，然后把锚点标记为稳定
+// The stabilizeRight method is used to make the right pointer of the left node of the rightmost node point to the rightmost node, then mark the anchor as stable
+func (d *LockFreeDequeOf[T]) stabilizeRight(anchor *anchorOf[T]) {
+	// 锚点处于 rpush 状态时不会有节点被弹出，所以只要锚点没有变化，prev 就仍在链表中
+	// No node is popped while the anchor is in the rpush state, so prev is still in the list as long as the anchor has not changed
+	prev := (*nodeOf[T])(atomic.LoadPointer(&anchor.right.left))
+	if d.loadAnchor() != anchor {
+		return
+	}
+
+	// 如果 prev 的 right 指针还没有指向最右侧节点，那么修复它，失败说明其他 goroutine 已经完成了修复
+	// If the right pointer of prev does not point to the rightmost node yet, then fix it, a failure means another goroutine has already fixed it
+	prevNext := atomic.LoadPointer(&prev.right)
+	if prevNext != unsafe.Pointer(anchor.right) {
+		if d.loadAnchor() != anchor {
+			return
+		}
+		if !atomic.CompareAndSwapPointer(&prev.right, prevNext, unsafe.Pointer(anchor.right)) {
+			return
+		}
+	}
+
+	// 把锚点标记为稳定
+	// Mark the anchor as stable
+	d.casAnchor(anchor, &anchorOf[T]{left: anchor.left, right: anchor.right}, nil)
+}
+
+// stabilizeLeft 方法用于让最左侧节点的右侧节点的 left 指针指向最左侧节点，然后把锚点标记为稳定
+// The stabilizeLeft method is used to make the left pointer of the right node of the leftmost node point to the leftmost node, then mark the anchor as stable
+func (d *LockFreeDequeOf[T]) stabilizeLeft(anchor *anchorOf[T]) {
+	// 锚点处于 lpush 状态时不会有节点被弹出，所以只要锚点没有变化，next 就仍在链表中
+	// No node is popped while the anchor is in the lpush state, so next is still in the list as long as the anchor has not changed
+	next := (*nodeOf[T])(atomic.LoadPointer(&anchor.left.right))
+	if d.loadAnchor() != anchor {
+		return
+	}
+
+	// 如果 next 的 left 指针还没有指向最左侧节点，那么修复它，失败说明其他 goroutine 已经完成了修复
+	// If the left pointer of next does not point to the leftmost node yet, then fix it, a failure means another goroutine has already fixed it
+	nextPrev := atomic.LoadPointer(&next.left)
+	if nextPrev != unsafe.Pointer(anchor.left) {
+		if d.loadAnchor() != anchor {
+			return
+		}
+		if !atomic.CompareAndSwapPointer(&next.left, nextPrev, unsafe.Pointer(anchor.left)) {
+			return
+		}
+	}
+
+	// 把锚点标记为稳定
+	// Mark the anchor as stable
+	d.casAnchor(anchor, &anchorOf[T]{left: anchor.left, right: anchor.right}, nil)
+}
+
+// Length 方法用于获取双端队列的长度
+// The Length method is used to get the length of the deque
+func (d *LockFreeDequeOf[T]) Length() int64 {
+	return atomic.LoadInt64(&d.length)
+}
+
+// IsEmpty 方法用于判断双端队列是否为空
+// The IsEmpty method is used to determine whether the deque is empty
+func (d *LockFreeDequeOf[T]) IsEmpty() bool {
+	return d.Length() == 0
+}
+
+// Reset 方法用于重置双端队列，它会通过一次 CAS 操作原子地摘下所有元素并丢弃，可以与推入和弹出并发调用
+// The Reset method is used to reset the deque, it atomically detaches and discards all the elements with a single CAS operation, and can be called concurrently with pushes and pops
+func (d *LockFreeDequeOf[T]) Reset() {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if d.reclaimer != nil {
+		guard := d.reclaimer.Enter()
+		defer d.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+	for {
+		anchor := d.loadAnchor()
+
+		// 双端队列为空，直接返回
+		// The deque is empty, return directly
+		if anchor.left == nil {
+			return
+		}
+
+		// 只有稳定的链表才能从左到右完整地遍历，先帮助上一次推入完成修复
+		// Only a stable list can be walked completely from left to right, help the previous push to finish first
+		if anchor.status != stable {
+			d.stabilize(anchor)
+			continue
+		}
+
+		// 用空锚点替换当前锚点，成功后整个链表都被摘下
+		// Replace the current anchor with an empty anchor, the whole list is detached once it succeeds
+		if d.casAnchor(anchor, &anchorOf[T]{}, &attempt) {
+			// 从左到右遍历被摘下的节点，统计数量并释放它们
+			// Walk the detached nodes from left to right, count and release them
+			n := int64(0)
+			for node := anchor.left; ; {
+				next := (*nodeOf[T])(atomic.LoadPointer(&node.right))
+				d.release(node)
+				n++
+				if node == anchor.right {
+					break
+				}
+				node = next
+			}
+
+			// 一次性减少双端队列的长度
+			// Decrease the length of the deque at once
+			atomic.AddInt64(&d.length, -n)
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回双端队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the deque, the zero value is returned when the WithStats option is not enabled
+func (d *LockFreeDequeOf[T]) Stats() Stats {
+	return d.stats.Snapshot()
+}
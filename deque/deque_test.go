@@ -0,0 +1,437 @@
+package deque
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeDeque_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 1000000
+
+	// Create a new deque
+	d := New()
+
+	// Test pushing elements at the back and popping them from the front (FIFO)
+	for i := 0; i < count; i++ {
+		d.PushBack(i)
+	}
+	for i := 0; i < count; i++ {
+		v := d.PopFront()
+		assert.Equal(t, i, v, "Incorrect value in the deque. Expected %d, got %d", i, v)
+	}
+
+	// Test pushing elements at the back and popping them from the back (LIFO)
+	for i := 0; i < count; i++ {
+		d.PushBack(i)
+	}
+	for i := count - 1; i >= 0; i-- {
+		v := d.PopBack()
+		assert.Equal(t, i, v, "Incorrect value in the deque. Expected %d, got %d", i, v)
+	}
+
+	// Verify the deque length
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+}
+
+func TestLockFreeDeque_BothEnds(t *testing.T) {
+	d := New()
+
+	// Build the deque 4 3 2 1 0 5 6 7 8 9 by pushing at both ends
+	for i := 0; i < 5; i++ {
+		d.PushFront(i)
+	}
+	for i := 5; i < 10; i++ {
+		d.PushBack(i)
+	}
+	assert.Equal(t, int64(10), d.Length(), "Incorrect deque length. Expected 10, got %d", d.Length())
+
+	// Verify the elements from the front
+	for _, expected := range []int{4, 3, 2, 1, 0} {
+		v := d.PopFront()
+		assert.Equal(t, expected, v, "Incorrect value in the deque. Expected %d, got %d", expected, v)
+	}
+
+	// Verify the elements from the back
+	for _, expected := range []int{9, 8, 7, 6, 5} {
+		v := d.PopBack()
+		assert.Equal(t, expected, v, "Incorrect value in the deque. Expected %d, got %d", expected, v)
+	}
+
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+}
+
+func TestLockFreeDeque_Length(t *testing.T) {
+	d := New()
+
+	// Test the length of an empty deque
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+
+	// Test the length of a non-empty deque
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			d.PushFront(i)
+		} else {
+			d.PushBack(i)
+		}
+		assert.Equal(t, int64(i+1), d.Length(), "Incorrect deque length. Expected %d, got %d", i+1, d.Length())
+	}
+
+	// Test the length of a deque after popping elements
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			d.PopBack()
+		} else {
+			d.PopFront()
+		}
+		assert.Equal(t, int64(100-i-1), d.Length(), "Incorrect deque length. Expected %d, got %d", 100-i-1, d.Length())
+	}
+}
+
+func TestLockFreeDeque_EmptyPop(t *testing.T) {
+	d := New()
+
+	// Test popping elements from an empty deque
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, d.PopFront(), "Expected nil value from an empty deque")
+		assert.Nil(t, d.PopBack(), "Expected nil value from an empty deque")
+	}
+
+	// Test that nil values are ignored
+	d.PushFront(nil)
+	d.PushBack(nil)
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+}
+
+func TestLockFreeDeque_Parallel(t *testing.T) {
+	nums := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	d := New()
+
+	// Test pushing elements at both ends
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				d.PushFront(i)
+			} else {
+				d.PushBack(i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Verify the elements in the deque
+	wg = sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var v interface{}
+			if i%2 == 0 {
+				v = d.PopBack()
+			} else {
+				v = d.PopFront()
+			}
+			assert.Contains(t, nums, v, "Incorrect value in the deque. Got %v", v)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+}
+
+func TestLockFreeDeque_ParallelDevilMode(t *testing.T) {
+	d := New()
+
+	// Test pushing and popping at both ends at the same time
+	wg := sync.WaitGroup{}
+	for j := 0; j < 10000; j++ {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				switch i {
+				case 0:
+					d.PushFront(i)
+				case 1:
+					d.PushBack(i)
+				case 2:
+					if v := d.PopFront(); v != nil {
+						assert.True(t, v.(int) == 0 || v.(int) == 1, "Incorrect value in the deque. Got %d", v)
+					}
+				default:
+					if v := d.PopBack(); v != nil {
+						assert.True(t, v.(int) == 0 || v.(int) == 1, "Incorrect value in the deque. Got %d", v)
+					}
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestLockFreeDeque_WithPool_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 1000000
+
+	// Create a new deque
+	d := NewWithPool()
+
+	// Test pushing elements at the front and popping them from the back (FIFO)
+	for i := 0; i < count; i++ {
+		d.PushFront(i)
+	}
+	for i := 0; i < count; i++ {
+		v := d.PopBack()
+		assert.Equal(t, i, v, "Incorrect value in the deque. Expected %d, got %d", i, v)
+	}
+
+	// Verify the deque length
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+}
+
+func TestLockFreeDeque_WithPool_EmptyPop(t *testing.T) {
+	d := NewWithPool()
+
+	// Test popping elements from an empty deque
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, d.PopFront(), "Expected nil value from an empty deque")
+		assert.Nil(t, d.PopBack(), "Expected nil value from an empty deque")
+	}
+}
+
+func TestLockFreeDeque_WithPool_StressNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 8, 8, 50000
+	total := producers * perProducer
+
+	d := NewWithPool()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, half of them push at the front and the others at the back
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if p%2 == 0 {
+					d.PushFront(p*perProducer + i)
+				} else {
+					d.PushBack(p*perProducer + i)
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, half of them pop from the front and the others from the back
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				var v interface{}
+				if c%2 == 0 {
+					v = d.PopFront()
+				} else {
+					v = d.PopBack()
+				}
+				if v != nil {
+					atomic.AddInt32(&seen[v.(int)], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+}
+
+func TestLockFreeDequeOf_Standard(t *testing.T) {
+	d := NewOf[int]()
+
+	// Test that zero values are stored and not mistaken for an empty deque
+	d.PushBack(0)
+	d.PushFront(1)
+
+	v, ok := d.PopBack()
+	assert.True(t, ok, "Failed to pop value")
+	assert.Equal(t, 0, v, "Incorrect value in the deque. Expected 0, got %d", v)
+
+	v, ok = d.PopBack()
+	assert.True(t, ok, "Failed to pop value")
+	assert.Equal(t, 1, v, "Incorrect value in the deque. Expected 1, got %d", v)
+
+	// Test popping elements from an empty deque
+	v, ok = d.PopFront()
+	assert.False(t, ok, "Popped value from an empty deque")
+	assert.Equal(t, 0, v, "Expected zero value from an empty deque")
+}
+
+func TestLockFreeDeque_Reset(t *testing.T) {
+	d := NewWithPool()
+
+	for i := 0; i < 100; i++ {
+		d.PushFront(i)
+		d.PushBack(i)
+	}
+
+	// Test that reset removes every element
+	d.Reset()
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+	assert.Nil(t, d.PopFront(), "PopFront on a reset deque should return nil")
+	assert.Nil(t, d.PopBack(), "PopBack on a reset deque should return nil")
+
+	// Test that the deque can still be used after a reset
+	d.PushBack(1)
+	assert.Equal(t, 1, d.PopFront(), "Incorrect value in the deque. Expected 1")
+}
+
+func TestLockFreeDeque_ResetParallel(t *testing.T) {
+	d := NewWithPool()
+
+	wg := sync.WaitGroup{}
+
+	// Push, pop and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				switch {
+				case i == 0 && j%100 == 0:
+					d.Reset()
+				case i%4 == 0:
+					d.PopFront()
+				case i%4 == 1:
+					d.PushFront(j)
+				case i%4 == 2:
+					d.PopBack()
+				default:
+					d.PushBack(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	d.Reset()
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+	assert.Nil(t, d.PopFront(), "PopFront on a reset deque should return nil")
+}
+
+func TestLockFreeDeque_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	d := New()
+	d.PushBack(1)
+	d.PopFront()
+	assert.Equal(t, Stats{}, d.Stats(), "Statistics should be disabled by default")
+
+	// Count the operations at both ends of a deque with a node pool
+	d = NewWithPool(WithStats())
+	for i := 0; i < 10; i++ {
+		d.PushBack(i)
+		d.PushFront(i)
+	}
+	for i := 0; i < 10; i++ {
+		d.PopBack()
+		d.PopFront()
+	}
+	d.PopBack()
+	d.PopFront()
+
+	s := d.Stats()
+	assert.Equal(t, uint64(20), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(20), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(2), s.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+	assert.Equal(t, uint64(20), s.PoolGets, "Incorrect number of pool gets")
+	assert.LessOrEqual(t, s.PoolMisses, s.PoolGets, "Pool misses should not exceed pool gets")
+}
+
+func TestLockFreeDeque_WithPoolAndBackoff(t *testing.T) {
+	var calls atomic.Int64
+	d := New(WithPool(), WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop at both ends from many goroutines
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				if i%2 == 0 {
+					d.PushBack(j)
+					runtime.Gosched()
+					d.PopFront()
+				} else {
+					d.PushFront(j)
+					runtime.Gosched()
+					d.PopBack()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// New with WithPool takes the nodes from the node pool like NewWithPool
+	s := d.Stats()
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+	assert.Equal(t, uint64(80000), s.PoolGets, "The nodes should come from the node pool")
+	assert.LessOrEqual(t, uint64(calls.Load()), s.CASFailures, "Only CAS failures should back off")
+}
+
+// testPoppedValuesCollected pushes values with finalizers, pops all but one of them with pop and waits until the GC has collected the popped ones
+func testPoppedValuesCollected(t *testing.T, pop func(d *LockFreeDequeOf[*[1 << 10]byte])) {
+	d := NewOf[*[1 << 10]byte]()
+	freed := atomic.Int64{}
+	for i := 0; i < 100; i++ {
+		v := new([1 << 10]byte)
+		runtime.SetFinalizer(v, func(*[1 << 10]byte) { freed.Add(1) })
+		d.PushBack(v)
+	}
+	for i := 0; i < 99; i++ {
+		pop(d)
+	}
+	assert.Equal(t, int64(1), d.Length(), "Incorrect length")
+
+	// Finalizers run in the background, give the GC a few rounds to collect the popped values
+	for i := 0; i < 100 && freed.Load() < 99; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int64(99), freed.Load(), "The popped values should be collected while the deque is not empty")
+	runtime.KeepAlive(d)
+}
+
+func TestLockFreeDeque_PopBackReleasesValues(t *testing.T) {
+	testPoppedValuesCollected(t, func(d *LockFreeDequeOf[*[1 << 10]byte]) { d.PopBack() })
+}
+
+func TestLockFreeDeque_PopFrontReleasesValues(t *testing.T) {
+	testPoppedValuesCollected(t, func(d *LockFreeDequeOf[*[1 << 10]byte]) { d.PopFront() })
+}
@@ -0,0 +1,43 @@
+package deque
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是双端队列统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a deque, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// Deque 是一个接口，定义了双端队列的基本操作
+// Deque is an interface that defines basic operations of a deque
+type Deque = interface {
+	// PushFront 方法用于向双端队列的头部添加一个元素
+	// The PushFront method is used to add an element to the front of the deque
+	PushFront(value interface{})
+
+	// PushBack 方法用于向双端队列的尾部添加一个元素
+	// The PushBack method is used to add an element to the back of the deque
+	PushBack(value interface{})
+
+	// PopFront 方法用于从双端队列的头部移除并返回一个元素
+	// The PopFront method is used to remove and return an element from the front of the deque
+	PopFront() interface{}
+
+	// PopBack 方法用于从双端队列的尾部移除并返回一个元素
+	// The PopBack method is used to remove and return an element from the back of the deque
+	PopBack() interface{}
+
+	// Reset 方法用于重置/清空双端队列
+	// The Reset method is used to reset/clear the deque
+	Reset()
+
+	// Length 方法返回双端队列的长度（即双端队列中元素的数量）
+	// The Length method returns the length of the deque (i.e., the number of elements in the deque)
+	Length() int64
+
+	// IsEmpty 方法用于检查双端队列是否为空
+	// The IsEmpty method is used to check if the deque is empty
+	IsEmpty() bool
+}
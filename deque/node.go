@@ -0,0 +1,76 @@
+package deque
+
+import (
+	"sync"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// nodeOf 是双端队列中的双向链表节点，T 为节点中值的类型
+// nodeOf is a doubly linked list node of the deque, T is the type of the value in the node
+type nodeOf[T any] struct {
+	// value 是节点的值
+	// value is the value of the node
+	value T
+
+	// left 是指向左侧节点的指针
+	// left is a pointer to the left node
+	left unsafe.Pointer
+
+	// right 是指向右侧节点的指针
+	// right is a pointer to the right node
+	right unsafe.Pointer
+
+	// link 是节点回收器串联退休节点时使用的指针
+	// link is the pointer used by the node reclaimer to chain retired nodes
+	link unsafe.Pointer
+}
+
+// reset 方法用于重置节点的所有字段
+// The reset method is used to reset all the fields of the node
+func (n *nodeOf[T]) reset() {
+	var zero T
+	n.value = zero
+	n.left = nil
+	n.right = nil
+	n.link = nil
+}
+
+// nodePoolOf 是双端队列节点的节点池
+// nodePoolOf is the node pool of deque nodes
+type nodePoolOf[T any] struct {
+	// pool 是一个同步池，用于存储和获取节点
+	// pool is a sync pool, used to store and retrieve nodes
+	pool sync.Pool
+
+	// stats 是节点池的统计计数器，为 nil 时不统计，必须在使用节点池之前设置
+	// stats are the statistics counters of the node pool, nothing is counted when it is nil, they must be set before the node pool is used
+	stats *shd.Counters
+}
+
+// newNodePoolOf 函数用于创建一个新的节点池
+// The newNodePoolOf function is used to create a new node pool
+func newNodePoolOf[T any]() *nodePoolOf[T] {
+	np := &nodePoolOf[T]{}
+	np.pool.New = func() interface{} {
+		np.stats.Inc(shd.CounterPoolMisses)
+		return &nodeOf[T]{}
+	}
+	return np
+}
+
+// get 方法用于从节点池中获取一个节点
+// The get method is used to get a node from the node pool
+func (np *nodePoolOf[T]) get() *nodeOf[T] {
+	np.stats.Inc(shd.CounterPoolGets)
+	return np.pool.Get().(*nodeOf[T])
+}
+
+// put 方法用于重置一个节点并将其放回节点池
+// The put method is used to reset a node and put it back into the node pool
+func (np *nodePoolOf[T]) put(n *nodeOf[T]) {
+	n.reset()
+	np.stats.Inc(shd.CounterPoolPuts)
+	np.pool.Put(n)
+}
@@ -0,0 +1,97 @@
+package deque
+
+// LockFreeDeque 是一个无锁双端队列结构体，元素的类型为 interface{}
+// LockFreeDeque is a lock-free deque struct, the type of the elements is interface{}
+type LockFreeDeque LockFreeDequeOf[interface{}]
+
+// New 函数用于创建一个新的 LockFreeDeque 双端队列，可以通过选项修改双端队列的行为
+// The New function is used to create a new LockFreeDeque deque, the behavior of the deque can be modified with options
+func New(opts ...Option) *LockFreeDeque {
+	// 调用 newLFD 函数创建一个新的 LockFreeDeque 双端队列，是否使用节点池由选项决定
+	// Call the newLFD function to create a new LockFreeDeque deque, whether a node pool is used is decided by the options
+	return (*LockFreeDeque)(newLFD[interface{}](opts))
+}
+
+// NewWithPool 函数用于创建一个新的 LockFreeDeque 双端队列，该双端队列使用一个节点池，可以通过选项修改双端队列的行为。
+// 它是 New 加上 WithPool 选项的简写
+// The NewWithPool function is used to create a new LockFreeDeque deque, this deque uses a node pool, the behavior of the deque can be modified with options.
+// It is a shortcut for New with the WithPool option
+func NewWithPool(opts ...Option) *LockFreeDeque {
+	return (*LockFreeDeque)(NewWithPoolOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeDeque 转换为底层的 LockFreeDequeOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeDeque to the underlying LockFreeDequeOf[interface{}] without any extra cost
+func (d *LockFreeDeque) of() *LockFreeDequeOf[interface{}] {
+	return (*LockFreeDequeOf[interface{}])(d)
+}
+
+// PushFront 方法用于将一个值添加到 LockFreeDeque 双端队列的头部
+// The PushFront method is used to add a value to the front of the LockFreeDeque deque
+func (d *LockFreeDeque) PushFront(value interface{}) {
+	// 检查值是否为空, 如果为空则直接返回
+	// Check if the value is nil, if it is, return directly
+	if value == nil {
+		return
+	}
+
+	// 将值添加到底层的泛型双端队列中
+	// Add the value to the underlying generic deque
+	d.of().PushFront(value)
+}
+
+// PushBack 方法用于将一个值添加到 LockFreeDeque 双端队列的尾部
+// The PushBack method is used to add a value to the back of the LockFreeDeque deque
+func (d *LockFreeDeque) PushBack(value interface{}) {
+	// 检查值是否为空, 如果为空则直接返回
+	// Check if the value is nil, if it is, return directly
+	if value == nil {
+		return
+	}
+
+	// 将值添加到底层的泛型双端队列中
+	// Add the value to the underlying generic deque
+	d.of().PushBack(value)
+}
+
+// PopFront 方法用于从 LockFreeDeque 双端队列的头部移除并返回一个值，如果双端队列为空，返回 nil
+// The PopFront method is used to remove and return a value from the front of the LockFreeDeque deque, returns nil if the deque is empty
+func (d *LockFreeDeque) PopFront() interface{} {
+	// 从底层的泛型双端队列中弹出一个值，双端队列为空时该值为 nil
+	// Pop a value from the underlying generic deque, the value is nil when the deque is empty
+	value, _ := d.of().PopFront()
+	return value
+}
+
+// PopBack 方法用于从 LockFreeDeque 双端队列的尾部移除并返回一个值，如果双端队列为空，返回 nil
+// The PopBack method is used to remove and return a value from the back of the LockFreeDeque deque, returns nil if the deque is empty
+func (d *LockFreeDeque) PopBack() interface{} {
+	// 从底层的泛型双端队列中弹出一个值，双端队列为空时该值为 nil
+	// Pop a value from the underlying generic deque, the value is nil when the deque is empty
+	value, _ := d.of().PopBack()
+	return value
+}
+
+// Length 方法用于获取 LockFreeDeque 双端队列的长度
+// The Length method is used to get the length of the LockFreeDeque deque
+func (d *LockFreeDeque) Length() int64 {
+	return d.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeDeque 双端队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeDeque deque is empty
+func (d *LockFreeDeque) IsEmpty() bool {
+	return d.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeDeque 双端队列，可以与推入和弹出并发调用
+// The Reset method is used to reset the LockFreeDeque deque, it can be called concurrently with pushes and pops
+func (d *LockFreeDeque) Reset() {
+	d.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeDeque 双端队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeDeque deque, the zero value is returned when the WithStats option is not enabled
+func (d *LockFreeDeque) Stats() Stats {
+	return d.of().Stats()
+}
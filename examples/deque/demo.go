@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/deque"
+)
+
+func main() {
+	// 使用 deque.New 函数创建一个新的双端队列
+	// Create a new deque using the deque.New function
+	d := deque.New()
+
+	// 使用 for 循环向双端队列中推入 10 个元素，偶数推入头部，奇数推入尾部
+	// Use a for loop to push 10 elements into the deque, even numbers at the front and odd numbers at the back
+	for i := 0; i < 10; i++ {
+		if i%2 == 0 {
+			// 使用 PushFront 方法在头部推入元素
+			// Use the PushFront method to push an element at the front
+			d.PushFront(i)
+		} else {
+			// 使用 PushBack 方法在尾部推入元素
+			// Use the PushBack method to push an element at the back
+			d.PushBack(i)
+		}
+	}
+
+	// 使用 for 循环从双端队列的头部弹出 10 个元素
+	// Use a for loop to pop 10 elements from the front of the deque
+	for i := 0; i < 10; i++ {
+		// 使用 PopFront 方法尝试弹出元素
+		// Use the PopFront method to try to pop an element
+		if v := d.PopFront(); v != nil {
+			// 如果弹出成功，打印弹出的元素
+			// If the pop is successful, print the popped element
+			fmt.Printf(">> pop: %v\n", v)
+		} else {
+			// 如果弹出失败，打印失败信息
+			// If the pop fails, print the failure message
+			fmt.Printf(">> pop failed: %v\n", i)
+		}
+	}
+}
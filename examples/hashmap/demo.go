@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/hashmap"
+)
+
+func main() {
+	// 使用 hashmap.New 函数创建一个新的哈希表
+	// Create a new hash map using the hashmap.New function
+	m := hashmap.New()
+
+	// 使用 for 循环向哈希表中写入 5 个条目
+	// Use a for loop to store 5 entries into the hash map
+	for i := 0; i < 5; i++ {
+		// 使用 Store 方法写入条目
+		// Use the Store method to store an entry
+		m.Store(fmt.Sprintf("key-%d", i), i)
+	}
+
+	// 使用 LoadOrStore 方法读取已经存在的键
+	// Use the LoadOrStore method to read an existing key
+	if v, loaded := m.LoadOrStore("key-0", 100); loaded {
+		fmt.Printf(">> loaded: %v\n", v)
+	}
+
+	// 使用 CompareAndSwap 方法在值等于 1 时把它替换为 10
+	// Use the CompareAndSwap method to replace the value with 10 if it equals 1
+	if m.CompareAndSwap("key-1", 1, 10) {
+		fmt.Printf(">> swapped: key-1\n")
+	}
+
+	// 使用 Delete 方法删除一个键
+	// Use the Delete method to delete a key
+	m.Delete("key-2")
+
+	// 使用 for 循环读取所有的键
+	// Use a for loop to load all the keys
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		// 使用 Load 方法读取键对应的值
+		// Use the Load method to read the value of the key
+		if v, ok := m.Load(key); ok {
+			// 如果键存在，打印它的值
+			// If the key exists, print its value
+			fmt.Printf(">> load: %s = %v\n", key, v)
+		} else {
+			// 如果键不存在，打印提示信息
+			// If the key does not exist, print a message
+			fmt.Printf(">> load failed: %s\n", key)
+		}
+	}
+}
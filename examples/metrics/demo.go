@@ -0,0 +1,44 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/metrics"
+	"github.com/shengyanli1982/lockfree/queue"
+	"github.com/shengyanli1982/lockfree/ringbuffer"
+)
+
+func main() {
+	// 创建一个启用统计的队列和一个启用统计的环形缓冲区
+	// Create a queue and a ring buffer with statistics enabled
+	q := queue.New(queue.WithStats())
+	rb := ringbuffer.New(4, ringbuffer.WithStats())
+
+	// 使用 metrics.Register 函数按名称把它们注册到默认注册表中
+	// Use the metrics.Register function to register them by name in the default registry
+	if err := metrics.Register("orders", q); err != nil {
+		fmt.Printf(">> register failed: %v\n", err)
+	}
+	if err := metrics.Register("events", rb); err != nil {
+		fmt.Printf(">> register failed: %v\n", err)
+	}
+
+	// 使用容器，环形缓冲区满了之后的推入会被拒绝
+	// Use the containers, the pushes after the ring buffer is full are rejected
+	for i := 0; i < 6; i++ {
+		q.Push(i)
+		rb.Push(i)
+	}
+	q.Pop()
+
+	// 默认注册表以 "lockfree" 为名称通过 expvar 发布，导入 net/http 之后可以通过 /debug/vars 访问
+	// The default registry is published through expvar under the name "lockfree", it can be read at /debug/vars once net/http is imported
+	fmt.Printf(">> expvar: %s\n", expvar.Get("lockfree").String())
+
+	// 使用 Collect 方法逐个读取指标，Prometheus 适配器也是这样读取的
+	// Use the Collect method to read the metrics one by one, this is how a Prometheus adapter reads them as well
+	metrics.DefaultRegistry.Collect(func(m metrics.Metric) {
+		fmt.Printf(">> %s{name=%q} %v\n", m.Desc.Name, m.LabelValues[0], m.Value)
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/priorityqueue"
+)
+
+func main() {
+	// 使用 priorityqueue.New 函数创建一个新的优先队列
+	// Create a new priority queue using the priorityqueue.New function
+	q := priorityqueue.New()
+
+	// 使用 for 循环向优先队列中推入 10 个元素，优先级与推入顺序相反
+	// Use a for loop to push 10 elements into the priority queue, the priorities are the reverse of the push order
+	for i := 0; i < 10; i++ {
+		// 使用 Push 方法推入元素，优先级越小越先弹出
+		// Use the Push method to push an element, smaller priorities are popped first
+		q.Push(i, int64(10-i))
+	}
+
+	// 使用 for 循环从优先队列中弹出 10 个元素
+	// Use a for loop to pop 10 elements from the priority queue
+	for i := 0; i < 10; i++ {
+		// 使用 PopMin 方法尝试弹出优先级最小的元素
+		// Use the PopMin method to try to pop the element with the smallest priority
+		if v := q.PopMin(); v != nil {
+			// 如果弹出成功，打印弹出的元素
+			// If the pop is successful, print the popped element
+			fmt.Printf(">> pop: %v\n", v)
+		} else {
+			// 如果弹出失败，打印失败信息
+			// If the pop fails, print the failure message
+			fmt.Printf(">> pop failed: %v\n", i)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/skiplist"
+)
+
+func main() {
+	// 使用 skiplist.New 函数创建一个新的跳表，参数为键的比较函数
+	// Create a new skiplist using the skiplist.New function, the parameter is the comparator of the keys
+	s := skiplist.New(func(a, b interface{}) int {
+		return a.(int) - b.(int)
+	})
+
+	// 使用 for 循环向跳表中乱序插入 5 个键
+	// Use a for loop to insert 5 keys into the skiplist out of order
+	for _, key := range []int{30, 10, 50, 20, 40} {
+		// 使用 Insert 方法插入键
+		// Use the Insert method to insert a key
+		s.Insert(key)
+	}
+
+	// 使用 Delete 方法删除一个键
+	// Use the Delete method to delete a key
+	s.Delete(20)
+
+	// 使用 Contains 方法判断键是否存在
+	// Use the Contains method to determine whether a key exists
+	fmt.Printf(">> contains 20: %v\n", s.Contains(20))
+
+	// 使用 Seek 方法查找大于或等于 25 的最小的键
+	// Use the Seek method to find the smallest key greater than or equal to 25
+	fmt.Printf(">> seek 25: %v\n", s.Seek(25))
+
+	// 使用 Ascend 方法按升序遍历所有的键
+	// Use the Ascend method to walk all the keys in ascending order
+	s.Ascend(func(key interface{}) bool {
+		fmt.Printf(">> ascend: %v\n", key)
+		return true
+	})
+
+	// 使用 DescendFrom 方法按降序遍历小于或等于 40 的键
+	// Use the DescendFrom method to walk the keys less than or equal to 40 in descending order
+	s.DescendFrom(40, func(key interface{}) bool {
+		fmt.Printf(">> descend: %v\n", key)
+		return true
+	})
+}
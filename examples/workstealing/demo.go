@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shengyanli1982/lockfree/workstealing"
+)
+
+func main() {
+	// 使用 workstealing.New 函数创建一个新的工作窃取双端队列，初始容量为 16
+	// Create a new work-stealing deque with an initial capacity of 16 using the workstealing.New function
+	d := workstealing.New(16)
+
+	// 所有者使用 for 循环向队列中推入 10 个元素
+	// The owner uses a for loop to push 10 elements into the deque
+	for i := 0; i < 10; i++ {
+		// 使用 Push 方法推入元素
+		// Use the Push method to push an element
+		d.Push(i)
+	}
+
+	// 其他 goroutine 使用 Steal 方法从顶部窃取 5 个最旧的元素
+	// Other goroutines use the Steal method to steal the 5 oldest elements from the top
+	for i := 0; i < 5; i++ {
+		if v := d.Steal(); v != nil {
+			// 如果窃取成功，打印窃取的元素
+			// If the steal is successful, print the stolen element
+			fmt.Printf(">> steal: %v\n", v)
+		}
+	}
+
+	// 所有者使用 Pop 方法从底部弹出剩下的 5 个最新的元素
+	// The owner uses the Pop method to pop the remaining 5 newest elements from the bottom
+	for i := 0; i < 5; i++ {
+		if v := d.Pop(); v != nil {
+			// 如果弹出成功，打印弹出的元素
+			// If the pop is successful, print the popped element
+			fmt.Printf(">> pop: %v\n", v)
+		}
+	}
+}
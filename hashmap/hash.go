@@ -0,0 +1,207 @@
+package hashmap
+
+import (
+	"hash/maphash"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// hasherOf 是键的哈希函数，相等的键总是得到相同的哈希值
+// hasherOf is the hash function of keys, equal keys always get the same hash value
+type hasherOf[K any] func(key K) uint64
+
+// integer 是所有底层类型为整数的类型的集合
+// integer is the set of all types whose underlying type is an integer
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// newHasherOf 函数用于创建一个使用随机种子的哈希函数。哈希函数按照 K 的底层类型只选择一次：整数、浮点数、布尔值、字符串和指针直接按照内存中的表示哈希，
+// 不会把键转换为 interface{}；接口按照它的动态值哈希；其他可比较的类型（结构体、数组和复数）会被转换为 interface{}，通过反射按照与 == 相同的规则哈希
+// The newHasherOf function is used to create a hash function with a random seed. The hash function is chosen only once from the underlying type of K: integers, floats, bools, strings and pointers are hashed directly from their in-memory representation
+// without converting the key to interface{}, interfaces are hashed by their dynamic value, other comparable types (structs, arrays and complex numbers) are converted to interface{} and hashed through reflection following the same rules as ==
+func newHasherOf[K any]() hasherOf[K] {
+	seed := maphash.MakeSeed()
+	salt := maphash.String(seed, "")
+
+	switch reflect.TypeOf((*K)(nil)).Elem().Kind() {
+	case reflect.Int:
+		return hashIntegerOf[K, int](salt)
+	case reflect.Int8:
+		return hashIntegerOf[K, int8](salt)
+	case reflect.Int16:
+		return hashIntegerOf[K, int16](salt)
+	case reflect.Int32:
+		return hashIntegerOf[K, int32](salt)
+	case reflect.Int64:
+		return hashIntegerOf[K, int64](salt)
+	case reflect.Uint:
+		return hashIntegerOf[K, uint](salt)
+	case reflect.Uint8:
+		return hashIntegerOf[K, uint8](salt)
+	case reflect.Uint16:
+		return hashIntegerOf[K, uint16](salt)
+	case reflect.Uint32:
+		return hashIntegerOf[K, uint32](salt)
+	case reflect.Uint64:
+		return hashIntegerOf[K, uint64](salt)
+	case reflect.Uintptr, reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		// 指针、通道和 unsafe.Pointer 与 uintptr 的大小相同，按地址哈希
+		// Pointers, channels and unsafe.Pointer have the same size as uintptr and are hashed by address
+		return hashIntegerOf[K, uintptr](salt)
+	case reflect.Float32:
+		return func(key K) uint64 {
+			return hashFloat(salt, float64(*(*float32)(unsafe.Pointer(&key))))
+		}
+	case reflect.Float64:
+		return func(key K) uint64 {
+			return hashFloat(salt, *(*float64)(unsafe.Pointer(&key)))
+		}
+	case reflect.Bool:
+		return func(key K) uint64 {
+			if *(*bool)(unsafe.Pointer(&key)) {
+				return mix(salt ^ 1)
+			}
+			return mix(salt)
+		}
+	case reflect.String:
+		return func(key K) uint64 {
+			return maphash.String(seed, *(*string)(unsafe.Pointer(&key)))
+		}
+	default:
+		// 接口转换为 interface{} 不需要分配内存，结构体、数组和复数会被装箱
+		// Converting an interface to interface{} does not allocate, structs, arrays and complex numbers are boxed
+		return func(key K) uint64 {
+			return hashValue(seed, salt, key)
+		}
+	}
+}
+
+// hashIntegerOf 函数用于创建一个把底层类型为 I 的键 K 当作整数哈希的函数
+// The hashIntegerOf function is used to create a hash function that hashes keys of type K, whose underlying type is I, as integers
+func hashIntegerOf[K any, I integer](salt uint64) hasherOf[K] {
+	return func(key K) uint64 {
+		return mix(salt ^ uint64(*(*I)(unsafe.Pointer(&key))))
+	}
+}
+
+// hashValue 函数用于计算 key 的哈希值，根据 key 的动态类型选择哈希方式
+// The hashValue function is used to compute the hash value of key, the way it is hashed depends on the dynamic type of key
+func hashValue(seed maphash.Seed, salt uint64, key interface{}) uint64 {
+	switch k := key.(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return mix(salt ^ uint64(k))
+	case int8:
+		return mix(salt ^ uint64(k))
+	case int16:
+		return mix(salt ^ uint64(k))
+	case int32:
+		return mix(salt ^ uint64(k))
+	case int64:
+		return mix(salt ^ uint64(k))
+	case uint:
+		return mix(salt ^ uint64(k))
+	case uint8:
+		return mix(salt ^ uint64(k))
+	case uint16:
+		return mix(salt ^ uint64(k))
+	case uint32:
+		return mix(salt ^ uint64(k))
+	case uint64:
+		return mix(salt ^ k)
+	case uintptr:
+		return mix(salt ^ uint64(k))
+	case float32:
+		return hashFloat(salt, float64(k))
+	case float64:
+		return hashFloat(salt, k)
+	case bool:
+		if k {
+			return mix(salt ^ 1)
+		}
+		return mix(salt)
+	default:
+		// 其他类型通过反射逐个哈希它们的组成部分
+		// Other types are hashed part by part through reflection
+		return hashReflect(seed, salt, reflect.ValueOf(k))
+	}
+}
+
+// hashReflect 函数用于按照与 == 相同的规则计算 v 的哈希值：指针、通道和 unsafe.Pointer 按地址哈希，相等的浮点数得到相同的哈希值，
+// 结构体和数组逐个哈希它们的字段和元素，接口哈希它的动态值。不可比较的类型（切片、映射和函数）不能作为键，与 Go 的 map 一样会 panic
+// The hashReflect function is used to compute the hash value of v following the same rules as ==: pointers, channels and unsafe.Pointer are hashed by address, equal floats get the same hash value,
+// structs and arrays hash their fields and elements one by one, and interfaces hash their dynamic value. Incomparable types (slices, maps and functions) cannot be keys and panic like they do in a Go map
+func hashReflect(seed maphash.Seed, salt uint64, v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.Invalid:
+		// nil 接口
+		// A nil interface
+		return mix(salt)
+	case reflect.Bool:
+		if v.Bool() {
+			return mix(salt ^ 1)
+		}
+		return mix(salt)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mix(salt ^ uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mix(salt ^ v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hashFloat(salt, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return combine(hashFloat(salt, real(c)), hashFloat(salt, imag(c)))
+	case reflect.String:
+		return maphash.String(seed, v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return mix(salt ^ uint64(v.Pointer()))
+	case reflect.Interface:
+		return hashReflect(seed, salt, v.Elem())
+	case reflect.Array:
+		h := mix(salt)
+		for i := 0; i < v.Len(); i++ {
+			h = combine(h, hashReflect(seed, salt, v.Index(i)))
+		}
+		return h
+	case reflect.Struct:
+		h := mix(salt)
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			// == 会忽略空白字段
+			// == ignores blank fields
+			if t.Field(i).Name == "_" {
+				continue
+			}
+			h = combine(h, hashReflect(seed, salt, v.Field(i)))
+		}
+		return h
+	default:
+		panic("hashmap: hash of unhashable type " + v.Type().String())
+	}
+}
+
+// combine 函数用于把下一个组成部分的哈希值 x 合并到 h 中，组成部分的顺序会影响结果
+// The combine function is used to merge the hash value x of the next part into h, the order of the parts affects the result
+func combine(h, x uint64) uint64 {
+	return mix(h*0x9e3779b97f4a7c15 ^ x)
+}
+
+// hashFloat 函数用于计算浮点数的哈希值，+0 和 -0 相等，因此得到相同的哈希值
+// The hashFloat function is used to compute the hash value of a float, +0 and -0 are equal so they get the same hash value
+func hashFloat(salt uint64, f float64) uint64 {
+	if f == 0 {
+		return mix(salt)
+	}
+	return mix(salt ^ math.Float64bits(f))
+}
+
+// mix 函数使用 splitmix64 的最终混合步骤打散整数的所有位
+// The mix function scrambles all the bits of an integer with the finalizer of splitmix64
+func mix(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
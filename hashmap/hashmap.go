@@ -0,0 +1,73 @@
+package hashmap
+
+// LockFreeHashMapOf 是一个泛型无锁哈希表，K 为键的类型，V 为值的类型
+// LockFreeHashMapOf is a generic lock-free hash map, K is the type of the keys and V is the type of the values
+type LockFreeHashMapOf[K comparable, V any] splitOrderedMapOf[K, V]
+
+// NewOf 函数用于创建一个新的泛型 LockFreeHashMapOf 哈希表，可以通过选项修改哈希表的行为
+// The NewOf function is used to create a new generic LockFreeHashMapOf hash map, the behavior of the hash map can be modified with options
+func NewOf[K comparable, V any](opts ...Option) *LockFreeHashMapOf[K, V] {
+	// 创建一个新的分裂有序哈希表，键使用 == 比较
+	// Create a new split-ordered hash map, keys are compared with ==
+	return (*LockFreeHashMapOf[K, V])(newSplitOrderedMapOf[K, V](func(a, b K) bool { return a == b }, opts))
+}
+
+// of 方法用于将 LockFreeHashMapOf 转换为底层的分裂有序哈希表，不会产生额外的开销
+// The of method converts LockFreeHashMapOf to the underlying split-ordered hash map without any extra cost
+func (m *LockFreeHashMapOf[K, V]) of() *splitOrderedMapOf[K, V] {
+	return (*splitOrderedMapOf[K, V])(m)
+}
+
+// Load 方法用于读取键 key 对应的值，第二个返回值表示键是否存在
+// The Load method is used to read the value of key, the second return value reports whether the key exists
+func (m *LockFreeHashMapOf[K, V]) Load(key K) (V, bool) {
+	return m.of().Load(key)
+}
+
+// Store 方法用于写入键 key 的值
+// The Store method is used to write the value of key
+func (m *LockFreeHashMapOf[K, V]) Store(key K, value V) {
+	m.of().Store(key, value)
+}
+
+// LoadOrStore 方法用于在键存在时返回它的值，否则写入 value 并返回 value。第二个返回值表示键是否已经存在
+// The LoadOrStore method returns the existing value of key if present, otherwise it stores and returns value. The second return value reports whether the key existed
+func (m *LockFreeHashMapOf[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	return m.of().LoadOrStore(key, value)
+}
+
+// Delete 方法用于删除键 key
+// The Delete method is used to delete key
+func (m *LockFreeHashMapOf[K, V]) Delete(key K) {
+	m.of().Delete(key)
+}
+
+// CompareAndSwap 方法用于在键 key 的值等于 old 时把它替换为 new，返回是否替换成功。值的类型必须是可比较的，否则会 panic
+// The CompareAndSwap method is used to replace the value of key with new if it equals old, it returns whether the value was replaced. The type of the values must be comparable, otherwise it panics
+func (m *LockFreeHashMapOf[K, V]) CompareAndSwap(key K, old, new V) bool {
+	return m.of().CompareAndSwap(key, old, new)
+}
+
+// Range 方法用于遍历哈希表中的所有条目，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Range method is used to walk all the entries in the hash map, the walk stops when fn returns false. The walk is weakly consistent
+func (m *LockFreeHashMapOf[K, V]) Range(fn func(key K, value V) bool) {
+	m.of().Range(fn)
+}
+
+// Length 方法用于获取哈希表中条目的数量
+// The Length method is used to get the number of entries in the hash map
+func (m *LockFreeHashMapOf[K, V]) Length() int64 {
+	return m.of().Length()
+}
+
+// IsEmpty 方法用于判断哈希表是否为空
+// The IsEmpty method is used to determine whether the hash map is empty
+func (m *LockFreeHashMapOf[K, V]) IsEmpty() bool {
+	return m.of().IsEmpty()
+}
+
+// Stats 方法用于返回哈希表的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the hash map, the zero value is returned when the WithStats option is not enabled
+func (m *LockFreeHashMapOf[K, V]) Stats() Stats {
+	return m.of().Stats()
+}
@@ -0,0 +1,440 @@
+package hashmap
+
+import (
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeHashMap_Standard(t *testing.T) {
+	// Number of entries to test
+	count := 100000
+
+	// Create a new hash map
+	m := New()
+
+	// Test storing entries, the bucket array has to grow several times
+	for i := 0; i < count; i++ {
+		m.Store(i, i*2)
+	}
+	assert.Equal(t, int64(count), m.Length(), "Incorrect map length. Expected %d, got %d", count, m.Length())
+
+	// Verify the entries in the hash map
+	for i := 0; i < count; i++ {
+		v, ok := m.Load(i)
+		assert.True(t, ok, "Key %d should exist", i)
+		assert.Equal(t, i*2, v, "Incorrect value in the map. Expected %d, got %d", i*2, v)
+	}
+
+	// Test deleting every other entry
+	for i := 0; i < count; i += 2 {
+		m.Delete(i)
+	}
+	assert.Equal(t, int64(count/2), m.Length(), "Incorrect map length. Expected %d, got %d", count/2, m.Length())
+	for i := 0; i < count; i++ {
+		_, ok := m.Load(i)
+		assert.Equal(t, i%2 == 1, ok, "Incorrect existence of key %d", i)
+	}
+}
+
+func TestLockFreeHashMap_Overwrite(t *testing.T) {
+	m := New()
+
+	// Test that storing an existing key replaces its value
+	m.Store("a", 1)
+	m.Store("a", 2)
+	v, ok := m.Load("a")
+	assert.True(t, ok, "Key a should exist")
+	assert.Equal(t, 2, v, "Incorrect value in the map. Expected 2, got %d", v)
+	assert.Equal(t, int64(1), m.Length(), "Incorrect map length. Expected 1, got %d", m.Length())
+
+	// Test that a deleted key can be stored again
+	m.Delete("a")
+	_, ok = m.Load("a")
+	assert.False(t, ok, "Key a should not exist")
+	m.Store("a", 3)
+	v, _ = m.Load("a")
+	assert.Equal(t, 3, v, "Incorrect value in the map. Expected 3, got %d", v)
+}
+
+func TestLockFreeHashMap_MixedKeyTypes(t *testing.T) {
+	m := New()
+
+	type point struct{ x, y int }
+
+	// Test that keys of different types with the same printed value are distinct
+	m.Store(1, "int")
+	m.Store(int64(1), "int64")
+	m.Store("1", "string")
+	m.Store(1.0, "float64")
+	m.Store(point{1, 2}, "point")
+	m.Store(nil, "nil")
+	assert.Equal(t, int64(6), m.Length(), "Incorrect map length. Expected 6, got %d", m.Length())
+
+	for key, expected := range map[interface{}]string{1: "int", int64(1): "int64", "1": "string", 1.0: "float64", point{1, 2}: "point"} {
+		v, ok := m.Load(key)
+		assert.True(t, ok, "Key %v should exist", key)
+		assert.Equal(t, expected, v, "Incorrect value in the map. Expected %s, got %v", expected, v)
+	}
+	v, ok := m.Load(nil)
+	assert.True(t, ok, "Key nil should exist")
+	assert.Equal(t, "nil", v, "Incorrect value in the map. Expected nil, got %v", v)
+}
+
+func TestLockFreeHashMap_PointerKeys(t *testing.T) {
+	type box struct{ n int }
+	p, q := &box{1}, &box{1}
+
+	// Pointer keys are equal by address, changing the pointee must not move the entry
+	m := NewOf[*box, int]()
+	m.Store(p, 1)
+	m.Store(q, 2)
+	assert.Equal(t, int64(2), m.Length(), "Distinct pointers to equal values should be distinct keys")
+	p.n = 100
+	v, ok := m.Load(p)
+	assert.True(t, ok, "A pointer key should be found after its pointee changed")
+	assert.Equal(t, 1, v, "Incorrect value in the map. Expected 1, got %d", v)
+
+	// The same holds for pointers stored in an interface{} map and inside struct keys
+	w := New()
+	w.Store(p, 1)
+	w.Store(struct{ p *box }{q}, 2)
+	p.n, q.n = 200, 200
+	v1, ok := w.Load(p)
+	assert.True(t, ok, "A pointer key should be found after its pointee changed")
+	assert.Equal(t, 1, v1, "Incorrect value in the map. Expected 1, got %v", v1)
+	v1, ok = w.Load(struct{ p *box }{q})
+	assert.True(t, ok, "A struct key holding a pointer should be found after the pointee changed")
+	assert.Equal(t, 2, v1, "Incorrect value in the map. Expected 2, got %v", v1)
+}
+
+func TestLockFreeHashMap_FloatStructKeys(t *testing.T) {
+	type key struct {
+		f float64
+		a [2]float32
+	}
+	negZero := math.Copysign(0, -1)
+
+	// +0 and -0 are equal under ==, so keys holding them must find the same entry
+	m := NewOf[key, int]()
+	m.Store(key{f: 0, a: [2]float32{0, 1}}, 1)
+	v, ok := m.Load(key{f: negZero, a: [2]float32{float32(negZero), 1}})
+	assert.True(t, ok, "A key with -0 should find the entry stored with +0")
+	assert.Equal(t, 1, v, "Incorrect value in the map. Expected 1, got %d", v)
+
+	m.Store(key{f: negZero, a: [2]float32{0, 1}}, 2)
+	assert.Equal(t, int64(1), m.Length(), "Storing an equal key should overwrite the entry")
+
+	// Keys that differ in any field are distinct
+	m.Store(key{f: 0, a: [2]float32{1, 0}}, 3)
+	assert.Equal(t, int64(2), m.Length(), "Keys that differ in a field should be distinct")
+	m.Delete(key{f: negZero, a: [2]float32{0, 1}})
+	_, ok = m.Load(key{f: 0, a: [2]float32{0, 1}})
+	assert.False(t, ok, "A key with +0 should be deleted through an equal key with -0")
+
+	// Interface fields are compared by their dynamic value, a float 0 and an int 0 are distinct
+	type ikey struct{ i interface{} }
+	w := New()
+	w.Store(ikey{0.0}, 1)
+	w.Store(ikey{0}, 2)
+	assert.Equal(t, int64(2), w.Length(), "Keys with different dynamic types should be distinct")
+	v1, ok := w.Load(ikey{negZero})
+	assert.True(t, ok, "A key with -0 should find the entry stored with +0")
+	assert.Equal(t, 1, v1, "Incorrect value in the map. Expected 1, got %v", v1)
+}
+
+func TestLockFreeHashMap_LoadOrStore(t *testing.T) {
+	m := New()
+
+	// Test storing an absent key
+	v, loaded := m.LoadOrStore("a", 1)
+	assert.False(t, loaded, "Key a should not have existed")
+	assert.Equal(t, 1, v, "Incorrect value. Expected 1, got %v", v)
+
+	// Test loading an existing key
+	v, loaded = m.LoadOrStore("a", 2)
+	assert.True(t, loaded, "Key a should have existed")
+	assert.Equal(t, 1, v, "Incorrect value. Expected 1, got %v", v)
+}
+
+func TestLockFreeHashMap_CompareAndSwap(t *testing.T) {
+	m := New()
+
+	// Test swapping an absent key
+	assert.False(t, m.CompareAndSwap("a", 1, 2), "Swapping an absent key should fail")
+
+	// Test swapping with the wrong and the right old value
+	m.Store("a", 1)
+	assert.False(t, m.CompareAndSwap("a", 3, 2), "Swapping with a wrong old value should fail")
+	assert.True(t, m.CompareAndSwap("a", 1, 2), "Swapping with the right old value should succeed")
+	v, _ := m.Load("a")
+	assert.Equal(t, 2, v, "Incorrect value in the map. Expected 2, got %v", v)
+}
+
+func TestLockFreeHashMap_Range(t *testing.T) {
+	m := New()
+
+	for i := 0; i < 1000; i++ {
+		m.Store(i, i)
+	}
+
+	// Test that every entry is visited exactly once
+	seen := make(map[interface{}]int)
+	m.Range(func(key, value interface{}) bool {
+		assert.Equal(t, key, value, "Incorrect value for key %v", key)
+		seen[key]++
+		return true
+	})
+	assert.Equal(t, 1000, len(seen), "Incorrect number of visited keys. Expected 1000, got %d", len(seen))
+	for key, n := range seen {
+		assert.Equal(t, 1, n, "Key %v visited %d times", key, n)
+	}
+
+	// Test that the walk stops when fn returns false
+	visited := 0
+	m.Range(func(key, value interface{}) bool {
+		visited++
+		return visited < 10
+	})
+	assert.Equal(t, 10, visited, "Incorrect number of visited keys. Expected 10, got %d", visited)
+}
+
+func TestLockFreeHashMap_Parallel(t *testing.T) {
+	workers, perWorker := 8, 20000
+	m := New()
+
+	wg := sync.WaitGroup{}
+
+	// Each worker stores, loads and deletes its own range of keys while the bucket array grows
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				key := w*perWorker + i
+				m.Store(key, key)
+				v, ok := m.Load(key)
+				assert.True(t, ok, "Key %d should exist", key)
+				assert.Equal(t, key, v, "Incorrect value in the map. Expected %d, got %v", key, v)
+				if i%2 == 0 {
+					m.Delete(key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Verify the entries in the hash map
+	assert.Equal(t, int64(workers*perWorker/2), m.Length(), "Incorrect map length. Expected %d, got %d", workers*perWorker/2, m.Length())
+	for key := 0; key < workers*perWorker; key++ {
+		_, ok := m.Load(key)
+		assert.Equal(t, key%2 == 1, ok, "Incorrect existence of key %d", key)
+	}
+}
+
+func TestLockFreeHashMap_ParallelSameKeys(t *testing.T) {
+	keys := 64
+	m := New()
+
+	// stored counts how many LoadOrStore calls inserted each key, it must be exactly one
+	stored := make([]int32, keys)
+	// increments counts the successful CompareAndSwap increments of each key
+	increments := make([]int64, keys)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2000; i++ {
+				key := i % keys
+				if _, loaded := m.LoadOrStore(key, 0); !loaded {
+					atomic.AddInt32(&stored[key], 1)
+				}
+				for {
+					v, _ := m.Load(key)
+					if m.CompareAndSwap(key, v, v.(int)+1) {
+						atomic.AddInt64(&increments[key], 1)
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify that each key was inserted once and no increment was lost
+	for key := 0; key < keys; key++ {
+		assert.Equal(t, int32(1), stored[key], "Key %d inserted %d times", key, stored[key])
+		v, _ := m.Load(key)
+		assert.Equal(t, int(increments[key]), v, "Incorrect counter for key %d", key)
+	}
+}
+
+func TestLockFreeHashMap_ParallelStoreDelete(t *testing.T) {
+	m := New()
+
+	wg := sync.WaitGroup{}
+
+	// Store and delete the same small set of keys at the same time
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				key := strconv.Itoa(i % 16)
+				if w%2 == 0 {
+					m.Store(key, i)
+				} else {
+					m.Delete(key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// The length matches the content once everything has finished
+	n := int64(0)
+	m.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	assert.Equal(t, n, m.Length(), "Incorrect map length. Expected %d, got %d", n, m.Length())
+}
+
+func TestLockFreeHashMapOf_Standard(t *testing.T) {
+	m := NewOf[string, int]()
+
+	// Test that zero values are stored and not mistaken for missing keys
+	m.Store("zero", 0)
+	v, ok := m.Load("zero")
+	assert.True(t, ok, "Key zero should exist")
+	assert.Equal(t, 0, v, "Incorrect value in the map. Expected 0, got %d", v)
+
+	// Test loading a missing key
+	v, ok = m.Load("missing")
+	assert.False(t, ok, "Key missing should not exist")
+	assert.Equal(t, 0, v, "Expected zero value for a missing key")
+
+	// Test the rest of the surface
+	actual, loaded := m.LoadOrStore("zero", 1)
+	assert.True(t, loaded, "Key zero should have existed")
+	assert.Equal(t, 0, actual, "Incorrect value. Expected 0, got %d", actual)
+	assert.True(t, m.CompareAndSwap("zero", 0, 1), "Swapping with the right old value should succeed")
+	m.Delete("zero")
+	assert.True(t, m.IsEmpty(), "Map should be empty")
+}
+
+// testKeysOf stores every key in a new map and verifies each one loads its own value, then deletes them all
+func testKeysOf[K comparable](t *testing.T, keys []K) {
+	m := NewOf[K, int]()
+	for i, k := range keys {
+		m.Store(k, i)
+	}
+	assert.Equal(t, int64(len(keys)), m.Length(), "Incorrect map length. Expected %d, got %d", len(keys), m.Length())
+	for i, k := range keys {
+		v, ok := m.Load(k)
+		assert.True(t, ok, "Key %v should exist", k)
+		assert.Equal(t, i, v, "Incorrect value for key %v. Expected %d, got %d", k, i, v)
+	}
+	for _, k := range keys {
+		m.Delete(k)
+	}
+	assert.True(t, m.IsEmpty(), "Map should be empty")
+}
+
+type namedInt int16
+
+type namedString string
+
+func TestLockFreeHashMapOf_TypedKeys(t *testing.T) {
+	// Each key kind is hashed by its own function, named types share the function of their underlying type
+	testKeysOf(t, []int{-1, 0, 1, math.MaxInt, math.MinInt})
+	testKeysOf(t, []namedInt{-1, 0, 1, math.MaxInt16, math.MinInt16})
+	testKeysOf(t, []uint8{0, 1, math.MaxUint8})
+	testKeysOf(t, []uint64{0, 1, math.MaxUint64})
+	testKeysOf(t, []namedString{"", "a", "b"})
+	testKeysOf(t, []float32{-1, 1, float32(math.Inf(1))})
+	testKeysOf(t, []bool{false, true})
+	testKeysOf(t, []*int{new(int), new(int), nil})
+	testKeysOf(t, [][2]int{{0, 1}, {1, 0}})
+
+	// +0 and -0 are equal under ==, so they must find the same entry
+	m := NewOf[float64, int]()
+	m.Store(0, 1)
+	v, ok := m.Load(math.Copysign(0, -1))
+	assert.True(t, ok, "A key with -0 should find the entry stored with +0")
+	assert.Equal(t, 1, v, "Incorrect value in the map. Expected 1, got %d", v)
+}
+
+func TestLockFreeHashMapOf_LoadDoesNotAllocate(t *testing.T) {
+	m := NewOf[int, int]()
+	for i := 0; i < 1024; i++ {
+		m.Store(i, i)
+	}
+
+	// Loading a key of a basic type must not box it into an interface
+	i := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		m.Load(i & 1023)
+		i++
+	})
+	assert.Equal(t, float64(0), allocs, "Load should not allocate. Got %v allocs per run", allocs)
+}
+
+func TestLockFreeHashMap_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	m := New()
+	m.Store(1, 1)
+	assert.Equal(t, Stats{}, m.Stats(), "Statistics should be disabled by default")
+
+	// Only new keys and deleted keys are counted, overwrites are not
+	m = New(WithStats())
+	for i := 0; i < 100; i++ {
+		m.Store(i, i)
+	}
+	m.Store(0, 1)
+	m.LoadOrStore(1, 2)
+	for i := 0; i < 50; i++ {
+		m.Delete(i)
+	}
+	m.Delete(0)
+
+	s := m.Stats()
+	assert.Equal(t, uint64(100), s.Pushes, "Incorrect number of inserted keys")
+	assert.Equal(t, uint64(50), s.Pops, "Incorrect number of deleted keys")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+}
+
+func TestLockFreeHashMap_WithBackoff(t *testing.T) {
+	var calls atomic.Int64
+	m := NewOf[int, int](WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Store and delete overlapping keys from many goroutines, every CAS failure backs off once
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5000; j++ {
+				m.Store(j%64, i)
+				runtime.Gosched()
+				m.Delete(j % 64)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, m.IsEmpty(), "Hash map should be empty")
+	assert.Equal(t, m.Stats().CASFailures, uint64(calls.Load()), "Every CAS failure should back off once")
+}
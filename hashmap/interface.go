@@ -0,0 +1,47 @@
+package hashmap
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是哈希表统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a hash map, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// HashMap 是一个接口，定义了哈希表的基本操作，与 sync.Map 的方法一致
+// HashMap is an interface that defines basic operations of a hash map, its methods match those of sync.Map
+type HashMap = interface {
+	// Load 方法用于读取键对应的值，第二个返回值表示键是否存在
+	// The Load method is used to read the value of a key, the second return value reports whether the key exists
+	Load(key interface{}) (interface{}, bool)
+
+	// Store 方法用于写入键的值
+	// The Store method is used to write the value of a key
+	Store(key, value interface{})
+
+	// LoadOrStore 方法用于在键存在时返回它的值，否则写入并返回给定的值
+	// The LoadOrStore method returns the existing value of a key if present, otherwise it stores and returns the given value
+	LoadOrStore(key, value interface{}) (interface{}, bool)
+
+	// Delete 方法用于删除键
+	// The Delete method is used to delete a key
+	Delete(key interface{})
+
+	// CompareAndSwap 方法用于在键的值等于 old 时把它替换为 new
+	// The CompareAndSwap method is used to replace the value of a key with new if it equals old
+	CompareAndSwap(key, old, new interface{}) bool
+
+	// Range 方法用于遍历哈希表中的所有条目
+	// The Range method is used to walk all the entries in the hash map
+	Range(fn func(key, value interface{}) bool)
+
+	// Length 方法返回哈希表中条目的数量
+	// The Length method returns the number of entries in the hash map
+	Length() int64
+
+	// IsEmpty 方法用于检查哈希表是否为空
+	// The IsEmpty method is used to check if the hash map is empty
+	IsEmpty() bool
+}
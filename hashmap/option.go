@@ -0,0 +1,47 @@
+package hashmap
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// config 是哈希表的配置
+// config is the configuration of the hash map
+type config struct {
+	// stats 表示是否启用统计
+	// stats indicates whether statistics are enabled
+	stats bool
+
+	// backoff 是 CAS 失败之后的退避策略，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// Option 是一个函数类型，用于修改哈希表的配置
+// Option is a function type used to modify the configuration of the hash map
+type Option func(*config)
+
+// newConfig 函数用于创建一个新的配置，并应用所有的选项
+// The newConfig function is used to create a new configuration and apply all the options
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithStats 函数返回一个选项，启用后哈希表会统计新插入和删除的键以及 CAS 失败，可以通过 Stats 方法读取。
+// 计数器按 goroutine 分片，开销很小，没有启用时只需要一次 nil 判断
+// The WithStats function returns an option, when enabled the hash map counts newly inserted and deleted keys and CAS failures, which can be read with the Stats method.
+// The counters are sharded by goroutine and cheap to update, when not enabled they only cost a nil check
+func WithStats() Option {
+	return func(c *config) {
+		c.stats = true
+	}
+}
+
+// WithBackoff 函数返回一个选项，设置 CAS 失败之后的退避策略 b。竞争激烈时，退避可以减少无效的重试
+// The WithBackoff function returns an option that sets the backoff strategy b after a failed CAS. Under heavy contention backing off reduces wasted retries
+func WithBackoff(b Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
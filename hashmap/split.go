@@ -0,0 +1,432 @@
+package hashmap
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+const (
+	// defaultBuckets 是桶数组的初始长度
+	// defaultBuckets is the initial length of the bucket array
+	defaultBuckets = 16
+
+	// maxLoad 是每个桶平均保存的元素数量上限，超过之后桶数组的长度翻倍
+	// maxLoad is the limit of the average number of elements per bucket, the bucket array doubles its length once it is exceeded
+	maxLoad = 2
+)
+
+// entryOf 是链表节点中保存的条目，K 为键的类型，V 为值的类型
+// entryOf is the entry held by a list node, K is the type of the key and V is the type of the value
+type entryOf[K any, V any] struct {
+	// hash 是条目的分裂有序键，即按位反转后的哈希值，链表按它升序排列
+	// hash is the split-ordered key of the entry, i.e. the bit-reversed hash value, the list is sorted by it in ascending order
+	hash uint64
+
+	// key 是条目的键，哨兵条目没有键
+	// key is the key of the entry, sentinel entries have no key
+	key K
+
+	// value 是指向条目的值的指针，类型为 *V，nil 表示条目已经被逻辑删除
+	// value is a pointer to the value of the entry, the type is *V, nil means the entry has been logically deleted
+	value unsafe.Pointer
+
+	// sentinel 表示条目是某个桶的哨兵，哨兵永远不会被删除
+	// sentinel means the entry is the sentinel of a bucket, sentinels are never deleted
+	sentinel bool
+}
+
+// deleted 方法用于判断条目是否已经被逻辑删除
+// The deleted method is used to determine whether the entry has been logically deleted
+func (e *entryOf[K, V]) deleted() bool {
+	return !e.sentinel && atomic.LoadPointer(&e.value) == nil
+}
+
+// tableOf 是桶数组，每个桶指向链表中的一个哨兵节点，元素的类型为 *shd.NodeOf[*entryOf[K, V]]
+// tableOf is the bucket array, every bucket points to a sentinel node in the list, the type of the elements is *shd.NodeOf[*entryOf[K, V]]
+type tableOf struct {
+	buckets []unsafe.Pointer
+}
+
+// splitOrderedMapOf 是基于分裂有序链表 (Shalev & Shavit) 的无锁哈希表。
+// 所有条目保存在一个按分裂有序键排列的无锁链表中，桶数组只保存指向哨兵节点的捷径，因此扩容时不需要移动任何条目。
+// 删除条目时先把它的值置为 nil，再在它后面追加一个标记节点，最后把它从链表中摘除，所有的修改都是对节点指针的 CAS 操作。
+// splitOrderedMapOf is a lock-free hash map based on split-ordered lists (Shalev & Shavit).
+// All entries live in a single lock-free list sorted by their split-ordered keys, the bucket array only holds shortcuts to sentinel nodes, so growing never moves an entry.
+// An entry is deleted by setting its value to nil, appending a marker node after it and then unlinking it from the list, every change is a CAS operation on a node pointer.
+type splitOrderedMapOf[K any, V any] struct {
+	// count 是哈希表中条目的数量
+	// count is the number of entries in the hash map
+	count int64
+
+	// _ 用于把每次写入都会修改的条目数量与每次读取都会访问的字段分隔到不同的缓存行，避免伪共享拖慢读取
+	// _ separates the entry count, modified by every write, from the fields accessed by every read onto different cache lines, so false sharing does not slow down reads
+	_ shd.CacheLinePad
+
+	// table 是指向当前桶数组的指针
+	// table is a pointer to the current bucket array
+	table unsafe.Pointer
+
+	// head 是 0 号桶的哨兵节点，也是整个链表的头节点
+	// head is the sentinel node of bucket 0, it is also the head node of the whole list
+	head *shd.NodeOf[*entryOf[K, V]]
+
+	// hash 是键的哈希函数
+	// hash is the hash function of keys
+	hash hasherOf[K]
+
+	// equal 用于判断两个键是否相等
+	// equal is used to determine whether two keys are equal
+	equal func(a, b K) bool
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// newSplitOrderedMapOf 函数用于创建一个新的分裂有序哈希表，equal 用于判断两个键是否相等，opts 是所有的选项
+// The newSplitOrderedMapOf function is used to create a new split-ordered hash map, equal is used to determine whether two keys are equal, opts are all the options
+func newSplitOrderedMapOf[K any, V any](equal func(a, b K) bool, opts []Option) *splitOrderedMapOf[K, V] {
+	head := shd.NewNodeOf(&entryOf[K, V]{sentinel: true})
+	table := &tableOf{buckets: make([]unsafe.Pointer, defaultBuckets)}
+	table.buckets[0] = unsafe.Pointer(head)
+
+	m := &splitOrderedMapOf[K, V]{
+		table: unsafe.Pointer(table),
+		head:  head,
+		hash:  newHasherOf[K](),
+		equal: equal,
+	}
+
+	// 应用所有的选项，设置退避策略，如果启用了统计，那么创建统计计数器
+	// Apply all the options, set the backoff strategy, if statistics are enabled, then create the statistics counters
+	conf := newConfig(opts)
+	m.backoff = conf.backoff
+	if conf.stats {
+		m.stats = shd.NewCounters()
+	}
+	return m
+}
+
+// regularKey 函数用于计算普通条目的分裂有序键，最低位总是 1
+// The regularKey function is used to compute the split-ordered key of a regular entry, its lowest bit is always 1
+func regularKey(hash uint64) uint64 {
+	return bits.Reverse64(hash | 1<<63)
+}
+
+// sentinelKey 函数用于计算 idx 号桶的哨兵的分裂有序键，最低位总是 0
+// The sentinelKey function is used to compute the split-ordered key of the sentinel of bucket idx, its lowest bit is always 0
+func sentinelKey(idx uint64) uint64 {
+	return bits.Reverse64(idx)
+}
+
+// loadTable 方法用于加载当前的桶数组
+// The loadTable method is used to load the current bucket array
+func (m *splitOrderedMapOf[K, V]) loadTable() *tableOf {
+	return (*tableOf)(atomic.LoadPointer(&m.table))
+}
+
+// bucket 方法用于获取哈希值 hash 所在的桶的哨兵节点，桶还没有初始化时先初始化它
+// The bucket method is used to get the sentinel node of the bucket that hash falls in, the bucket is initialized first if it has not been yet
+func (m *splitOrderedMapOf[K, V]) bucket(hash uint64) *shd.NodeOf[*entryOf[K, V]] {
+	t := m.loadTable()
+	return m.bucketAt(t, hash&uint64(len(t.buckets)-1))
+}
+
+// bucketAt 方法用于获取 idx 号桶的哨兵节点，桶还没有初始化时先初始化它
+// The bucketAt method is used to get the sentinel node of bucket idx, the bucket is initialized first if it has not been yet
+func (m *splitOrderedMapOf[K, V]) bucketAt(t *tableOf, idx uint64) *shd.NodeOf[*entryOf[K, V]] {
+	if node := shd.LoadNodeOf[*entryOf[K, V]](&t.buckets[idx]); node != nil {
+		return node
+	}
+
+	// 从父桶开始把哨兵插入链表，父桶是去掉 idx 最高位得到的桶。哨兵已经存在时直接使用它，因此初始化可以被重复执行
+	// Insert the sentinel into the list starting from the parent bucket, the parent is the bucket obtained by clearing the highest bit of idx. An existing sentinel is used directly, so initialization can safely run more than once
+	parent := m.bucketAt(t, idx&^(1<<(bits.Len64(idx)-1)))
+	so := sentinelKey(idx)
+	var sentinel *shd.NodeOf[*entryOf[K, V]]
+	for {
+		pred, curr, found := m.find(parent, so, *new(K), true)
+		if found {
+			sentinel = curr
+			break
+		}
+		node := shd.NewNodeOf(&entryOf[K, V]{hash: so, sentinel: true})
+		node.Next = unsafe.Pointer(curr)
+		if shd.CompareAndSwapNode(&pred.Next, curr, node) {
+			sentinel = node
+			break
+		}
+	}
+
+	// 记录到桶数组中。即使桶数组已经被替换，新的桶数组之后也会重新找到同一个哨兵
+	// Record it in the bucket array. Even if the bucket array has been replaced, the new bucket array finds the same sentinel again later
+	atomic.StorePointer(&t.buckets[idx], unsafe.Pointer(sentinel))
+	return sentinel
+}
+
+// find 方法用于从 start 开始查找分裂有序键为 so 的条目，返回前驱节点、当前节点以及是否找到。
+// 没有找到时，新的节点应该插入在前驱节点和当前节点之间。查找过程中会帮助移除已经被逻辑删除的节点。
+// The find method is used to search for the entry with split-ordered key so starting from start, it returns the predecessor, the current node and whether it was found.
+// When it is not found, a new node belongs between the predecessor and the current node. Logically deleted nodes met during the search are helped out of the list.
+func (m *splitOrderedMapOf[K, V]) find(start *shd.NodeOf[*entryOf[K, V]], so uint64, key K, sentinel bool) (*shd.NodeOf[*entryOf[K, V]], *shd.NodeOf[*entryOf[K, V]], bool) {
+restart:
+	for {
+		pred := start
+		curr := shd.LoadNodeOf[*entryOf[K, V]](&pred.Next)
+		for {
+			if curr == nil {
+				return pred, nil, false
+			}
+			next := shd.LoadNodeOf[*entryOf[K, V]](&curr.Next)
+
+			// 前驱节点已经不再指向当前节点，重新开始查找
+			// The predecessor no longer points to the current node, restart the search
+			if curr != shd.LoadNodeOf[*entryOf[K, V]](&pred.Next) {
+				continue restart
+			}
+
+			// 当前节点是标记节点，说明前驱节点正在被删除，重新开始查找
+			// The current node is a marker node, so the predecessor is being deleted, restart the search
+			e := curr.Value
+			if e == nil {
+				continue restart
+			}
+
+			// 当前节点已经被逻辑删除，帮助把它从链表中移除，然后重新开始查找
+			// The current node has been logically deleted, help to remove it from the list, then restart the search
+			if e.deleted() {
+				m.helpDelete(pred, curr, next)
+				continue restart
+			}
+
+			if e.hash > so {
+				return pred, curr, false
+			}
+			if e.hash == so && e.sentinel == sentinel && (sentinel || m.equal(e.key, key)) {
+				return pred, curr, true
+			}
+			pred, curr = curr, next
+		}
+	}
+}
+
+// helpDelete 方法用于帮助把已经被逻辑删除的节点 node 从链表中移除，pred 是它的前驱节点，next 是它的后继节点。
+// 第一步在 node 后面追加一个标记节点，阻止其他 goroutine 在 node 后面插入；第二步让 pred 越过 node 和标记节点。
+// The helpDelete method is used to help remove the logically deleted node from the list, pred is its predecessor and next is its successor.
+// The first step appends a marker node after node, which stops other goroutines from inserting after it; the second step makes pred skip node and the marker node.
+func (m *splitOrderedMapOf[K, V]) helpDelete(pred, node, next *shd.NodeOf[*entryOf[K, V]]) {
+	if next != shd.LoadNodeOf[*entryOf[K, V]](&node.Next) || node != shd.LoadNodeOf[*entryOf[K, V]](&pred.Next) {
+		return
+	}
+	if next == nil || next.Value != nil {
+		marker := shd.NewNodeOf[*entryOf[K, V]](nil)
+		marker.Next = unsafe.Pointer(next)
+		shd.CompareAndSwapNode(&node.Next, next, marker)
+	} else {
+		shd.CompareAndSwapNode(&pred.Next, node, shd.LoadNodeOf[*entryOf[K, V]](&next.Next))
+	}
+}
+
+// grow 方法用于在平均每个桶的条目数量超过 maxLoad 时把桶数组的长度翻倍，新的桶会在第一次使用时初始化
+// The grow method is used to double the length of the bucket array once the average number of entries per bucket exceeds maxLoad, new buckets are initialized on first use
+func (m *splitOrderedMapOf[K, V]) grow(count int64) {
+	t := m.loadTable()
+	if count <= int64(len(t.buckets))*maxLoad {
+		return
+	}
+	next := &tableOf{buckets: make([]unsafe.Pointer, len(t.buckets)*2)}
+	for i := range t.buckets {
+		next.buckets[i] = atomic.LoadPointer(&t.buckets[i])
+	}
+	atomic.CompareAndSwapPointer(&m.table, unsafe.Pointer(t), unsafe.Pointer(next))
+}
+
+// Load 方法用于读取键 key 对应的值，第二个返回值表示键是否存在
+// The Load method is used to read the value of key, the second return value reports whether the key exists
+func (m *splitOrderedMapOf[K, V]) Load(key K) (V, bool) {
+	hash := m.hash(key)
+	so := regularKey(hash)
+
+	// 只读地遍历链表，不需要帮助移除被删除的节点，也不需要校验前驱节点
+	// Walk the list read-only, there is no need to help remove deleted nodes or to validate the predecessor
+	for node := m.bucket(hash); node != nil; node = shd.LoadNodeOf[*entryOf[K, V]](&node.Next) {
+		e := node.Value
+		if e == nil {
+			continue
+		}
+		if e.hash > so {
+			break
+		}
+		if e.hash == so && !e.sentinel && m.equal(e.key, key) {
+			if p := atomic.LoadPointer(&e.value); p != nil {
+				return *(*V)(p), true
+			}
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// store 方法用于写入键 key 的值，onlyIfAbsent 为 true 时不会覆盖已经存在的值。返回写入之前的值以及键是否已经存在
+// The store method is used to write the value of key, an existing value is not overwritten when onlyIfAbsent is true. It returns the value before the write and whether the key existed
+func (m *splitOrderedMapOf[K, V]) store(key K, value V, onlyIfAbsent bool) (V, bool) {
+	hash := m.hash(key)
+	so := regularKey(hash)
+	start := m.bucket(hash)
+	vp := unsafe.Pointer(&value)
+
+	attempt := 0
+	for {
+		pred, curr, found := m.find(start, so, key, false)
+
+		// 键已经存在，修改它的值。如果条目在此期间被删除，重新查找，find 会帮助移除它
+		// The key exists, change its value. If the entry is deleted meanwhile, search again and find helps to remove it
+		if found {
+			e := curr.Value
+			for {
+				p := atomic.LoadPointer(&e.value)
+				if p == nil {
+					break
+				}
+				if onlyIfAbsent || atomic.CompareAndSwapPointer(&e.value, p, vp) {
+					return *(*V)(p), true
+				}
+				m.stats.Inc(shd.CounterCASFailures)
+				m.backoff.Wait(&attempt)
+			}
+			continue
+		}
+
+		// 键不存在，在前驱节点和当前节点之间插入一个新节点
+		// The key does not exist, insert a new node between the predecessor and the current node
+		node := shd.NewNodeOf(&entryOf[K, V]{hash: so, key: key, value: vp})
+		node.Next = unsafe.Pointer(curr)
+		if shd.CompareAndSwapNode(&pred.Next, curr, node) {
+			m.grow(atomic.AddInt64(&m.count, 1))
+			m.stats.Inc(shd.CounterPushes)
+			var zero V
+			return zero, false
+		}
+
+		// 前驱节点已经改变，记录一次 CAS 失败，退避之后重新查找
+		// The predecessor has changed, count a CAS failure, back off and search again
+		m.stats.Inc(shd.CounterCASFailures)
+		m.backoff.Wait(&attempt)
+	}
+}
+
+// Store 方法用于写入键 key 的值
+// The Store method is used to write the value of key
+func (m *splitOrderedMapOf[K, V]) Store(key K, value V) {
+	m.store(key, value, false)
+}
+
+// LoadOrStore 方法用于在键存在时返回它的值，否则写入 value 并返回 value。第二个返回值表示键是否已经存在
+// The LoadOrStore method returns the existing value of key if present, otherwise it stores and returns value. The second return value reports whether the key existed
+func (m *splitOrderedMapOf[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	if actual, loaded := m.store(key, value, true); loaded {
+		return actual, true
+	}
+	return value, false
+}
+
+// Delete 方法用于删除键 key
+// The Delete method is used to delete key
+func (m *splitOrderedMapOf[K, V]) Delete(key K) {
+	hash := m.hash(key)
+	so := regularKey(hash)
+	start := m.bucket(hash)
+
+	_, node, found := m.find(start, so, key, false)
+	if !found {
+		return
+	}
+
+	// 把值置为 nil 逻辑删除条目，只有成功的 goroutine 减少条目数量
+	// Logically delete the entry by setting its value to nil, only the goroutine that succeeds decreases the number of entries
+	e := node.Value
+	attempt := 0
+	for {
+		p := atomic.LoadPointer(&e.value)
+		if p == nil {
+			return
+		}
+		if atomic.CompareAndSwapPointer(&e.value, p, nil) {
+			break
+		}
+		m.stats.Inc(shd.CounterCASFailures)
+		m.backoff.Wait(&attempt)
+	}
+	atomic.AddInt64(&m.count, -1)
+	m.stats.Inc(shd.CounterPops)
+
+	// 再查找一次，find 会帮助把节点从链表中移除
+	// Search once more, find helps to remove the node from the list
+	m.find(start, so, key, false)
+}
+
+// CompareAndSwap 方法用于在键 key 的值等于 old 时把它替换为 new，返回是否替换成功。值的类型必须是可比较的，否则会 panic
+// The CompareAndSwap method is used to replace the value of key with new if it equals old, it returns whether the value was replaced. The type of the values must be comparable, otherwise it panics
+func (m *splitOrderedMapOf[K, V]) CompareAndSwap(key K, old, new V) bool {
+	hash := m.hash(key)
+	_, node, found := m.find(m.bucket(hash), regularKey(hash), key, false)
+	if !found {
+		return false
+	}
+
+	e := node.Value
+	np := unsafe.Pointer(&new)
+	attempt := 0
+	for {
+		p := atomic.LoadPointer(&e.value)
+		if p == nil || interface{}(*(*V)(p)) != interface{}(old) {
+			return false
+		}
+		if atomic.CompareAndSwapPointer(&e.value, p, np) {
+			return true
+		}
+		m.stats.Inc(shd.CounterCASFailures)
+		m.backoff.Wait(&attempt)
+	}
+}
+
+// Range 方法用于遍历哈希表中的所有条目，fn 返回 false 时停止遍历。遍历是弱一致的，不保证看到遍历期间的修改
+// The Range method is used to walk all the entries in the hash map, the walk stops when fn returns false. The walk is weakly consistent and may not observe changes made during it
+func (m *splitOrderedMapOf[K, V]) Range(fn func(key K, value V) bool) {
+	for node := shd.LoadNodeOf[*entryOf[K, V]](&m.head.Next); node != nil; node = shd.LoadNodeOf[*entryOf[K, V]](&node.Next) {
+		// 跳过标记节点和哨兵节点
+		// Skip marker nodes and sentinel nodes
+		e := node.Value
+		if e == nil || e.sentinel {
+			continue
+		}
+		if p := atomic.LoadPointer(&e.value); p != nil && !fn(e.key, *(*V)(p)) {
+			return
+		}
+	}
+}
+
+// Length 方法用于获取哈希表中条目的数量
+// The Length method is used to get the number of entries in the hash map
+func (m *splitOrderedMapOf[K, V]) Length() int64 {
+	return atomic.LoadInt64(&m.count)
+}
+
+// IsEmpty 方法用于判断哈希表是否为空
+// The IsEmpty method is used to determine whether the hash map is empty
+func (m *splitOrderedMapOf[K, V]) IsEmpty() bool {
+	return m.Length() == 0
+}
+
+// Stats 方法用于返回哈希表的统计信息快照，没有启用 WithStats 选项时返回零值。新插入的键计入 Pushes，删除的键计入 Pops，覆盖已有的值不计入
+// The Stats method is used to return a snapshot of the statistics of the hash map, the zero value is returned when the WithStats option is not enabled. Newly inserted keys count as Pushes, deleted keys count as Pops, overwriting an existing value is not counted
+func (m *splitOrderedMapOf[K, V]) Stats() Stats {
+	return m.stats.Snapshot()
+}
@@ -0,0 +1,73 @@
+package hashmap
+
+// LockFreeHashMap 是一个无锁哈希表结构体，键和值的类型都为 interface{}，用法与 sync.Map 相同
+// LockFreeHashMap is a lock-free hash map struct, the type of both the keys and the values is interface{}, it is used the same way as sync.Map
+type LockFreeHashMap splitOrderedMapOf[interface{}, interface{}]
+
+// New 函数用于创建一个新的 LockFreeHashMap 哈希表，可以通过选项修改哈希表的行为
+// The New function is used to create a new LockFreeHashMap hash map, the behavior of the hash map can be modified with options
+func New(opts ...Option) *LockFreeHashMap {
+	// 创建一个新的分裂有序哈希表，键使用 == 比较，键的动态类型不可比较时会 panic
+	// Create a new split-ordered hash map, keys are compared with ==, it panics if the dynamic type of a key is not comparable
+	return (*LockFreeHashMap)(newSplitOrderedMapOf[interface{}, interface{}](func(a, b interface{}) bool { return a == b }, opts))
+}
+
+// of 方法用于将 LockFreeHashMap 转换为底层的分裂有序哈希表，不会产生额外的开销
+// The of method converts LockFreeHashMap to the underlying split-ordered hash map without any extra cost
+func (m *LockFreeHashMap) of() *splitOrderedMapOf[interface{}, interface{}] {
+	return (*splitOrderedMapOf[interface{}, interface{}])(m)
+}
+
+// Load 方法用于读取键 key 对应的值，第二个返回值表示键是否存在
+// The Load method is used to read the value of key, the second return value reports whether the key exists
+func (m *LockFreeHashMap) Load(key interface{}) (interface{}, bool) {
+	return m.of().Load(key)
+}
+
+// Store 方法用于写入键 key 的值
+// The Store method is used to write the value of key
+func (m *LockFreeHashMap) Store(key, value interface{}) {
+	m.of().Store(key, value)
+}
+
+// LoadOrStore 方法用于在键存在时返回它的值，否则写入 value 并返回 value。第二个返回值表示键是否已经存在
+// The LoadOrStore method returns the existing value of key if present, otherwise it stores and returns value. The second return value reports whether the key existed
+func (m *LockFreeHashMap) LoadOrStore(key, value interface{}) (interface{}, bool) {
+	return m.of().LoadOrStore(key, value)
+}
+
+// Delete 方法用于删除键 key
+// The Delete method is used to delete key
+func (m *LockFreeHashMap) Delete(key interface{}) {
+	m.of().Delete(key)
+}
+
+// CompareAndSwap 方法用于在键 key 的值等于 old 时把它替换为 new，返回是否替换成功。值的动态类型不可比较时会 panic
+// The CompareAndSwap method is used to replace the value of key with new if it equals old, it returns whether the value was replaced. It panics if the dynamic type of a value is not comparable
+func (m *LockFreeHashMap) CompareAndSwap(key, old, new interface{}) bool {
+	return m.of().CompareAndSwap(key, old, new)
+}
+
+// Range 方法用于遍历哈希表中的所有条目，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Range method is used to walk all the entries in the hash map, the walk stops when fn returns false. The walk is weakly consistent
+func (m *LockFreeHashMap) Range(fn func(key, value interface{}) bool) {
+	m.of().Range(fn)
+}
+
+// Length 方法用于获取哈希表中条目的数量
+// The Length method is used to get the number of entries in the hash map
+func (m *LockFreeHashMap) Length() int64 {
+	return m.of().Length()
+}
+
+// IsEmpty 方法用于判断哈希表是否为空
+// The IsEmpty method is used to determine whether the hash map is empty
+func (m *LockFreeHashMap) IsEmpty() bool {
+	return m.of().IsEmpty()
+}
+
+// Stats 方法用于返回 LockFreeHashMap 哈希表的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeHashMap hash map, the zero value is returned when the WithStats option is not enabled
+func (m *LockFreeHashMap) Stats() Stats {
+	return m.of().Stats()
+}
@@ -0,0 +1,16 @@
+package shared
+
+// Backoff 是 CAS 失败之后的退避策略，attempt 是当前操作连续失败的次数，从 1 开始。
+// 它可以自旋、让出处理器或者休眠，返回之后操作会立即重试。nil 表示不退避，失败后立即重试
+// Backoff is the backoff strategy after a failed CAS, attempt is the number of consecutive failures of the current operation, starting from 1.
+// It may spin, yield the processor or sleep, the operation retries right after it returns. nil means no backoff, a failure is retried immediately
+type Backoff func(attempt int)
+
+// Wait 方法用于记录当前操作的一次失败并按退避策略等待，b 为 nil 时什么也不做
+// The Wait method is used to record a failure of the current operation and wait according to the backoff strategy, it does nothing when b is nil
+func (b Backoff) Wait(attempt *int) {
+	if b != nil {
+		*attempt++
+		b(*attempt)
+	}
+}
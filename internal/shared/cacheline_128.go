@@ -0,0 +1,9 @@
+//go:build arm64 || ppc64 || ppc64le
+
+package shared
+
+// CacheLineSize 是当前架构的缓存行大小 (字节)，与 Go 运行时使用的值一致。
+// arm64 上使用 128 字节，因为部分处理器 (例如 Apple M 系列) 的缓存行为 128 字节
+// CacheLineSize is the cache line size in bytes of the current architecture, it matches the value used by the Go runtime.
+// 128 bytes are used on arm64 because some processors (for example the Apple M series) have 128-byte cache lines
+const CacheLineSize = 128
@@ -0,0 +1,7 @@
+//go:build s390x
+
+package shared
+
+// CacheLineSize 是当前架构的缓存行大小 (字节)，与 Go 运行时使用的值一致
+// CacheLineSize is the cache line size in bytes of the current architecture, it matches the value used by the Go runtime
+const CacheLineSize = 256
@@ -8,20 +8,32 @@ import (
 // On a 64-bit computer, the size of a Go object is typically a multiple of 8 bytes, which is the size of a pointer on a 64-bit architecture.
 // Therefore, the size of a Go object is usually 8 bytes, 16 bytes, 32 bytes, and so on.  24 bytes is not a common size for a Go object.
 
-// Node 数据单元节点
-// Node represents a data unit node
-type Node struct {
-	// Value 是节点存储的值，类型为 interface{}，可以存储任何类型的值
-	// Value is the Value stored in the node, of type interface{}, which can store any type of Value
-	Value interface{}
+// NodeOf 泛型数据单元节点，T 为节点存储的值的类型
+// NodeOf represents a generic data unit node, T is the type of the value stored in the node
+type NodeOf[T any] struct {
+	// Value 是节点存储的值，类型为 T
+	// Value is the Value stored in the node, of type T
+	Value T
 
 	// Next 是指向下一个节点的指针，类型为 unsafe.Pointer
 	// Next is a pointer to the Next node, of type unsafe.Pointer
 	Next unsafe.Pointer
 
-	// _ 是一个占位符，用于填充内存对齐
-	// _ is a placeholder used to fill memory alignment
-	_ int64
+	// link 用于在回收器中串联已退休的节点，同时起到填充内存对齐的作用
+	// link is used to chain retired nodes in the reclaimer, it also fills memory alignment
+	link unsafe.Pointer
+}
+
+// Node 数据单元节点，值的类型为 interface{}，可以存储任何类型的值
+// Node represents a data unit node, the value is of type interface{}, which can store any type of value
+type Node = NodeOf[interface{}]
+
+// NewNodeOf 函数用于创建一个新的 NodeOf 结构体实例
+// The NewNodeOf function is used to create a new instance of the NodeOf struct
+func NewNodeOf[T any](v T) *NodeOf[T] {
+	// 返回一个新的 NodeOf 结构体实例
+	// Returns a new instance of the NodeOf struct
+	return &NodeOf[T]{Value: v}
 }
 
 // NewNode 函数用于创建一个新的 Node 结构体实例
@@ -29,60 +41,130 @@ type Node struct {
 func NewNode(v interface{}) *Node {
 	// 返回一个新的 Node 结构体实例
 	// Returns a new instance of the Node struct
-	return &Node{Value: v}
+	return NewNodeOf[interface{}](v)
 }
 
 // Reset 方法用于重置 Node 结构体的值
 // The Reset method is used to reset the value of the Node struct
-func (n *Node) ResetAll() {
-	// 将 value 字段设置为 nil
-	// Set the value field to nil
-	n.Value = nil
+func (n *NodeOf[T]) ResetAll() {
+	// 将 value 字段设置为 T 的零值
+	// Set the value field to the zero value of T
+	var zero T
+	n.Value = zero
 
 	// 将 next 字段设置为 nil
 	// Set the next field to nil
 	n.Next = nil
+
+	// 将 link 字段设置为 nil
+	// Set the link field to nil
+	n.link = nil
+}
+
+// NodeAllocatorOf 是一个接口，定义了自定义的节点分配器。Get 返回的节点所有字段必须为零值，
+// Put 收到的节点已经被重置，并且已经没有 goroutine 引用它，可以直接复用
+// NodeAllocatorOf is an interface that defines a custom node allocator. All the fields of a node returned by Get must be zero,
+// a node passed to Put has already been reset and is no longer referenced by any goroutine, so it can be reused directly
+type NodeAllocatorOf[T any] interface {
+	// Get 方法用于分配一个节点
+	// The Get method is used to allocate a node
+	Get() *NodeOf[T]
+
+	// Put 方法用于归还一个节点
+	// The Put method is used to give a node back
+	Put(n *NodeOf[T])
 }
 
-// NodePool 结构体用于表示一个节点池
-// The NodePool struct is used to represent a node pool
-type NodePool struct {
+// NodePoolOf 结构体用于表示一个泛型节点池
+// The NodePoolOf struct is used to represent a generic node pool
+type NodePoolOf[T any] struct {
 	// pool 是一个同步池，用于存储和获取节点
 	// pool is a sync pool, used to store and retrieve nodes
 	pool *sync.Pool
+
+	// alloc 是自定义的节点分配器，不为 nil 时代替同步池存储和获取节点
+	// alloc is the custom node allocator, when it is not nil it stores and retrieves nodes instead of the sync pool
+	alloc NodeAllocatorOf[T]
+
+	// stats 是节点池的统计计数器，为 nil 时不统计
+	// stats are the statistics counters of the node pool, nothing is counted when it is nil
+	stats *Counters
+}
+
+// NodePool 结构体用于表示一个节点池，节点的值的类型为 interface{}
+// The NodePool struct is used to represent a node pool, the value of the node is of type interface{}
+type NodePool = NodePoolOf[interface{}]
+
+// NewNodePoolOf 函数用于创建一个新的泛型节点池
+// The NewNodePoolOf function is used to create a new generic node pool
+func NewNodePoolOf[T any]() *NodePoolOf[T] {
+	np := &NodePoolOf[T]{}
+
+	// 创建一个新的同步池，当池中没有可用的节点时，会调用 New 方法创建一个新的节点
+	// Create a new sync pool, when there are no available nodes in the pool, the New method will be called to create a new node
+	np.pool = &sync.Pool{
+		New: func() interface{} {
+			np.stats.Inc(CounterPoolMisses)
+			var zero T
+			return NewNodeOf(zero)
+		},
+	}
+	return np
 }
 
 // NewNodePool 函数用于创建一个新的节点池
 // The NewNodePool function is used to create a new node pool
 func NewNodePool() *NodePool {
-	return &NodePool{
-		// 创建一个新的同步池，当池中没有可用的节点时，会调用 New 方法创建一个新的节点
-		// Create a new sync pool, when there are no available nodes in the pool, the New method will be called to create a new node
-		pool: &sync.Pool{
-			New: func() interface{} {
-				return NewNode(nil)
-			},
-		},
-	}
+	return NewNodePoolOf[interface{}]()
+}
+
+// NewNodePoolWithAllocatorOf 函数用于创建一个新的泛型节点池，节点由自定义的分配器 alloc 分配和回收。
+// 分配器自己管理节点，因此节点池无法统计未命中的次数
+// The NewNodePoolWithAllocatorOf function is used to create a new generic node pool whose nodes are allocated and recycled by the custom allocator alloc.
+// The allocator manages the nodes itself, so the node pool cannot count the misses
+func NewNodePoolWithAllocatorOf[T any](alloc NodeAllocatorOf[T]) *NodePoolOf[T] {
+	return &NodePoolOf[T]{alloc: alloc}
 }
 
 // Get 方法用于从节点池中获取一个节点
 // The Get method is used to get a node from the node pool
-func (np *NodePool) Get() *Node {
-	// 使用 sync.Pool 的 Get 方法获取一个节点，然后将其转换为 *Node 类型
-	// Use the Get method of sync.Pool to get a node, and then convert it to *Node type
-	return np.pool.Get().(*Node)
+func (np *NodePoolOf[T]) Get() *NodeOf[T] {
+	np.stats.Inc(CounterPoolGets)
+
+	// 如果设置了自定义的分配器，由它分配节点
+	// If a custom allocator is set, it allocates the node
+	if np.alloc != nil {
+		return np.alloc.Get()
+	}
+
+	// 使用 sync.Pool 的 Get 方法获取一个节点，然后将其转换为 *NodeOf[T] 类型
+	// Use the Get method of sync.Pool to get a node, and then convert it to *NodeOf[T] type
+	return np.pool.Get().(*NodeOf[T])
+}
+
+// SetStats 方法用于设置节点池的统计计数器，必须在使用节点池之前调用
+// The SetStats method is used to set the statistics counters of the node pool, it must be called before the node pool is used
+func (np *NodePoolOf[T]) SetStats(stats *Counters) {
+	np.stats = stats
 }
 
 // Put 方法用于将一个节点放回节点池
 // The Put method is used to put a node back into the node pool
-func (np *NodePool) Put(n *Node) {
+func (np *NodePoolOf[T]) Put(n *NodeOf[T]) {
 	// 如果节点不为 nil
 	// If the node is not nil
 	if n != nil {
 		// 重置节点，包括其值、下一个节点和索引
 		// Reset the node, including its value, next node, and index
 		n.ResetAll()
+		np.stats.Inc(CounterPoolPuts)
+
+		// 如果设置了自定义的分配器，把节点还给它
+		// If a custom allocator is set, give the node back to it
+		if np.alloc != nil {
+			np.alloc.Put(n)
+			return
+		}
 
 		// 使用 sync.Pool 的 Put 方法将节点放回池中
 		// Use the Put method of sync.Pool to put the node back into the pool
@@ -5,18 +5,46 @@ import (
 	"unsafe"
 )
 
+// LoadNodeOf 函数用于加载指定指针 p 指向的 NodeOf 结构体
+// The LoadNodeOf function is used to load the NodeOf struct pointed to by the specified pointer p
+func LoadNodeOf[T any](p *unsafe.Pointer) *NodeOf[T] {
+	// 使用 atomic.LoadPointer 加载并返回指定指针 p 指向的 NodeOf 结构体
+	// Uses atomic.LoadPointer to load and return the NodeOf struct pointed to by the specified pointer p
+	return (*NodeOf[T])(atomic.LoadPointer(p))
+}
+
 // LoadNode 函数用于加载指定指针 p 指向的 Node 结构体
 // The LoadNode function is used to load the Node struct pointed to by the specified pointer p
 func LoadNode(p *unsafe.Pointer) *Node {
 	// 使用 atomic.LoadPointer 加载并返回指定指针 p 指向的 Node 结构体
 	// Uses atomic.LoadPointer to load and return the Node struct pointed to by the specified pointer p
-	return (*Node)(atomic.LoadPointer(p))
+	return LoadNodeOf[interface{}](p)
 }
 
 // CompareAndSwapNode 函数用于比较并交换指定指针 p 指向的 Node 结构体
 // The CompareAndSwapNode function is used to compare and swap the Node struct pointed to by the specified pointer p
-func CompareAndSwapNode(p *unsafe.Pointer, old, new *Node) bool {
+func CompareAndSwapNode[T any](p *unsafe.Pointer, old, new *NodeOf[T]) bool {
 	// 使用 atomic.CompareAndSwapPointer 比较并交换指定指针 p 指向的 Node 结构体
 	// Uses atomic.CompareAndSwapPointer to compare and swap the Node struct pointed to by the specified pointer p
 	return atomic.CompareAndSwapPointer(p, unsafe.Pointer(old), unsafe.Pointer(new))
 }
+
+// CompactNil 函数用于去掉 values 中的 nil 值，如果没有 nil 值，直接返回 values，不会产生额外的分配
+// The CompactNil function is used to remove the nil values from values, values is returned directly without extra allocation if it has no nil values
+func CompactNil(values []interface{}) []interface{} {
+	for i, value := range values {
+		if value == nil {
+			// 复制非 nil 的值到新的切片中
+			// Copy the non-nil values into a new slice
+			result := make([]interface{}, i, len(values)-1)
+			copy(result, values[:i])
+			for _, v := range values[i+1:] {
+				if v != nil {
+					result = append(result, v)
+				}
+			}
+			return result
+		}
+	}
+	return values
+}
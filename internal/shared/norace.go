@@ -0,0 +1,11 @@
+//go:build !race
+
+package shared
+
+// RaceDisable 函数在没有启用竞争检测器时什么也不做
+// The RaceDisable function does nothing when the race detector is not enabled
+func RaceDisable() {}
+
+// RaceEnable 函数在没有启用竞争检测器时什么也不做
+// The RaceEnable function does nothing when the race detector is not enabled
+func RaceEnable() {}
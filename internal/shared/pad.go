@@ -0,0 +1,13 @@
+//go:build !lockfree_nopad
+
+package shared
+
+// CacheLinePad 用于把结构体中被不同 goroutine 频繁修改的字段分隔到不同的缓存行，避免伪共享。
+// 它的大小是当前架构的缓存行大小，由 cacheline_*.go 中的 CacheLineSize 常量决定。
+// 使用 lockfree_nopad 构建标签可以去掉填充，它只用于通过基准测试比较两种内存布局。
+// CacheLinePad is used to separate fields of a struct that are frequently modified by different goroutines onto different cache lines, which avoids false sharing.
+// Its size is the cache line size of the current architecture, given by the CacheLineSize constant in cacheline_*.go.
+// Build with the lockfree_nopad tag to remove the padding, which is only meant for comparing the two layouts with the benchmarks.
+type CacheLinePad struct {
+	_ [CacheLineSize]byte
+}
@@ -0,0 +1,9 @@
+//go:build lockfree_nopad
+
+package shared
+
+// CacheLinePad 在使用 lockfree_nopad 构建标签时大小为 0，被频繁修改的字段重新挤在同一个缓存行中。
+// 它只用于通过基准测试比较有填充和没有填充的内存布局，不要在生产环境中使用
+// CacheLinePad has a size of 0 when building with the lockfree_nopad tag, so the frequently modified fields are packed onto the same cache line again.
+// It is only meant for comparing the padded and the unpadded memory layouts with the benchmarks, do not use it in production
+type CacheLinePad struct{}
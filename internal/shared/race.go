@@ -0,0 +1,19 @@
+//go:build race
+
+package shared
+
+import "runtime"
+
+// RaceDisable 函数用于让竞争检测器忽略当前 goroutine 之后的内存访问，直到调用 RaceEnable。
+// 它只用于乐观读取：读取可能与写入并发，但读取结果在校验失败时会被丢弃
+// The RaceDisable function is used to make the race detector ignore the memory accesses of the current goroutine until RaceEnable is called.
+// It is only used for optimistic reads: the read may run concurrently with a write, but its result is discarded when the validation fails
+func RaceDisable() {
+	runtime.RaceDisable()
+}
+
+// RaceEnable 函数用于恢复竞争检测器对当前 goroutine 的内存访问的检查
+// The RaceEnable function is used to make the race detector check the memory accesses of the current goroutine again
+func RaceEnable() {
+	runtime.RaceEnable()
+}
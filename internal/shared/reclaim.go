@@ -0,0 +1,195 @@
+package shared
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	// reclaimerSlots 是回收器中参与者槽位的数量，必须是 2 的幂
+	// reclaimerSlots is the number of participant slots in the reclaimer, must be a power of two
+	reclaimerSlots = 128
+
+	// reclaimerAdvanceInterval 是尝试推进全局 epoch 的间隔（按退休节点数计算）
+	// reclaimerAdvanceInterval is the interval (counted in retired nodes) at which the global epoch is advanced
+	reclaimerAdvanceInterval = 64
+
+	// slotIdle 表示槽位空闲，没有 goroutine 处于临界区
+	// slotIdle means the slot is idle, no goroutine is inside a critical section
+	slotIdle = 0
+)
+
+// reclaimerSlot 是一个参与者槽位，记录进入临界区的 goroutine 观察到的 epoch
+// reclaimerSlot is a participant slot, it records the epoch observed by the goroutine that entered the critical section
+type reclaimerSlot struct {
+	// state 为 slotIdle 表示空闲，否则为 (epoch << 1) | 1
+	// state is slotIdle when idle, otherwise (epoch << 1) | 1
+	state uint64
+
+	// _ 用于填充到一个缓存行，避免相邻槽位之间的伪共享
+	// _ pads the slot to a cache line to avoid false sharing between adjacent slots
+	_ [CacheLineSize - 8]byte
+}
+
+// Reclaimer 是一个基于 epoch 的安全内存回收器 (EBR)。
+// 节点被移出数据结构后不会立即放回节点池，而是先退休，等所有可能持有该节点指针的 goroutine 都离开临界区后才会被回收，从而避免 ABA 问题。
+// Reclaimer is an epoch-based reclaimer (EBR).
+// A node removed from a data structure is not put back into the node pool immediately. It is retired first and only recycled after every goroutine that may still hold a pointer to it has left its critical section, which avoids the ABA problem.
+type Reclaimer struct {
+	// slots 是参与者槽位，放在最前面，保证每个槽位的 state 在 32 位平台上也按 8 字节对齐
+	// slots are the participant slots, they come first so the state of every slot is 8-byte aligned on 32-bit platforms as well
+	slots [reclaimerSlots]reclaimerSlot
+
+	// epoch 是全局 epoch，每次进入临界区都会读取它
+	// epoch is the global epoch, it is read on every entry into a critical section
+	epoch uint64
+
+	// _ 用于把很少修改的 epoch 与每次退休都会修改的计数器分隔到不同的缓存行
+	// _ separates the rarely modified epoch from the counters modified on every retire onto different cache lines
+	_ CacheLinePad
+
+	// retired 是已退休节点的计数，用于决定何时尝试推进 epoch
+	// retired is the number of retired nodes, used to decide when to try to advance the epoch
+	retired uint64
+
+	// overflow 是没有拿到槽位而进入临界区的 goroutine 数量，不为 0 时 epoch 不会推进
+	// overflow is the number of goroutines inside a critical section without a slot, the epoch does not advance while it is not 0
+	overflow int64
+
+	// limbo 是按 epoch 分组的退休节点链表
+	// limbo holds the retired node lists grouped by epoch
+	limbo [3]unsafe.Pointer
+
+	// link 返回节点中用于串联退休链表的指针字段
+	// link returns the pointer field of a node used to chain the retired list
+	link func(p unsafe.Pointer) *unsafe.Pointer
+
+	// free 在宽限期结束后回收节点
+	// free recycles a node once its grace period is over
+	free func(p unsafe.Pointer)
+
+	// interval 是尝试推进全局 epoch 的间隔（按退休节点数计算）
+	// interval is the interval (counted in retired nodes) at which the global epoch is advanced
+	interval uint64
+}
+
+// NewReclaimer 函数用于创建一个新的回收器，link 返回节点的链接字段，free 用于回收节点
+// The NewReclaimer function is used to create a new reclaimer, link returns the link field of a node and free recycles a node
+func NewReclaimer(link func(p unsafe.Pointer) *unsafe.Pointer, free func(p unsafe.Pointer)) *Reclaimer {
+	return NewReclaimerWithInterval(reclaimerAdvanceInterval, link, free)
+}
+
+// NewReclaimerWithInterval 函数用于创建一个新的回收器，每退休 interval 个节点尝试推进一次 epoch。
+// 退休的节点很大或者很少时，较小的间隔可以让节点更快地被复用
+// The NewReclaimerWithInterval function is used to create a new reclaimer that tries to advance the epoch every interval retired nodes.
+// When the retired nodes are large or rare, a smaller interval lets them be reused sooner
+func NewReclaimerWithInterval(interval uint64, link func(p unsafe.Pointer) *unsafe.Pointer, free func(p unsafe.Pointer)) *Reclaimer {
+	if interval == 0 {
+		interval = reclaimerAdvanceInterval
+	}
+	return &Reclaimer{link: link, free: free, interval: interval}
+}
+
+// NewNodeReclaimerOf 函数用于创建一个 NodeOf[T] 的回收器，宽限期结束后节点会被放回节点池
+// The NewNodeReclaimerOf function is used to create a reclaimer for NodeOf[T], nodes are put back into the node pool once their grace period is over
+func NewNodeReclaimerOf[T any](pool *NodePoolOf[T]) *Reclaimer {
+	return NewReclaimer(
+		func(p unsafe.Pointer) *unsafe.Pointer { return &(*NodeOf[T])(p).link },
+		func(p unsafe.Pointer) { pool.Put((*NodeOf[T])(p)) },
+	)
+}
+
+// Enter 方法用于进入临界区，返回值需要传给 Exit 方法。在临界区内读取到的节点不会被回收
+// The Enter method is used to enter a critical section, the return value must be passed to the Exit method. Nodes read inside the critical section will not be recycled
+func (r *Reclaimer) Enter() int {
+	// 从基于 goroutine 栈地址的槽位开始探测，减少竞争
+	// Start probing from a slot derived from the goroutine stack address to reduce contention
+	start := int(StackHash())
+
+	for i := 0; i < reclaimerSlots; i++ {
+		idx := (start + i) & (reclaimerSlots - 1)
+		slot := &r.slots[idx]
+
+		// 如果槽位空闲，尝试使用当前 epoch 占用该槽位
+		// If the slot is idle, try to occupy it with the current epoch
+		if atomic.LoadUint64(&slot.state) == slotIdle &&
+			atomic.CompareAndSwapUint64(&slot.state, slotIdle, atomic.LoadUint64(&r.epoch)<<1|1) {
+			return idx
+		}
+	}
+
+	// 所有槽位都被占用，记为溢出参与者，阻止 epoch 推进
+	// All slots are occupied, register as an overflow participant which blocks the epoch from advancing
+	atomic.AddInt64(&r.overflow, 1)
+	return -1
+}
+
+// Exit 方法用于离开临界区
+// The Exit method is used to leave a critical section
+func (r *Reclaimer) Exit(idx int) {
+	if idx < 0 {
+		atomic.AddInt64(&r.overflow, -1)
+		return
+	}
+	atomic.StoreUint64(&r.slots[idx].state, slotIdle)
+}
+
+// Retire 方法用于退休一个已经从数据结构中移除的节点，必须在临界区内调用
+// The Retire method is used to retire a node that has been removed from the data structure, it must be called inside a critical section
+func (r *Reclaimer) Retire(p unsafe.Pointer) {
+	// 将节点压入当前 epoch 的退休链表
+	// Push the node onto the retired list of the current epoch
+	list := &r.limbo[atomic.LoadUint64(&r.epoch)%3]
+	link := r.link(p)
+	for {
+		head := atomic.LoadPointer(list)
+		*link = head
+		if atomic.CompareAndSwapPointer(list, head, p) {
+			break
+		}
+	}
+
+	// 每退休一定数量的节点，尝试推进一次 epoch
+	// Try to advance the epoch every time a certain number of nodes have been retired
+	if atomic.AddUint64(&r.retired, 1)%r.interval == 0 {
+		r.tryAdvance()
+	}
+}
+
+// tryAdvance 方法尝试推进全局 epoch，成功后回收两个 epoch 之前退休的节点，必须在临界区内调用
+// The tryAdvance method tries to advance the global epoch, on success it recycles the nodes retired two epochs ago, it must be called inside a critical section
+func (r *Reclaimer) tryAdvance() {
+	epoch := atomic.LoadUint64(&r.epoch)
+
+	// 只有当所有活跃的参与者都已经观察到当前 epoch 时才能推进
+	// The epoch can only advance when every active participant has observed the current epoch
+	for i := 0; i < reclaimerSlots; i++ {
+		state := atomic.LoadUint64(&r.slots[i].state)
+		if state != slotIdle && state>>1 != epoch {
+			return
+		}
+	}
+	if atomic.LoadInt64(&r.overflow) != 0 {
+		return
+	}
+
+	if !atomic.CompareAndSwapUint64(&r.epoch, epoch, epoch+1) {
+		return
+	}
+
+	// 在 epoch-1 退休的节点已经没有任何 goroutine 能访问，可以安全回收
+	// Nodes retired in epoch-1 can no longer be reached by any goroutine and can be recycled safely
+	p := atomic.SwapPointer(&r.limbo[(epoch+2)%3], nil)
+	for p != nil {
+		next := *r.link(p)
+		r.free(p)
+		p = next
+	}
+}
+
+// StackHash 函数返回一个基于当前 goroutine 栈地址的散列值，用于把并发的调用者分散到不同的槽位
+// The StackHash function returns a hash based on the stack address of the current goroutine, used to spread concurrent callers across different slots
+func StackHash() uint32 {
+	var marker byte
+	return uint32((uint64(uintptr(unsafe.Pointer(&marker))>>11) * 0x9E3779B97F4A7C15) >> 32)
+}
@@ -0,0 +1,159 @@
+package shared
+
+import "sync/atomic"
+
+// Counter 是统计计数器的编号
+// Counter is the index of a statistics counter
+type Counter int
+
+const (
+	// CounterPushes 统计成功推入 (插入) 的元素数量
+	// CounterPushes counts the elements pushed (inserted) successfully
+	CounterPushes Counter = iota
+
+	// CounterPops 统计成功弹出 (删除) 的元素数量
+	// CounterPops counts the elements popped (deleted) successfully
+	CounterPops
+
+	// CounterCASFailures 统计失败并需要重试的 CAS 操作数量
+	// CounterCASFailures counts the CAS operations that failed and had to be retried
+	CounterCASFailures
+
+	// CounterFullRejections 统计因为容器已满而被拒绝的推入数量
+	// CounterFullRejections counts the pushes rejected because the container was full
+	CounterFullRejections
+
+	// CounterEmptyPops 统计因为容器为空而没有弹出元素的次数
+	// CounterEmptyPops counts the pops that found the container empty
+	CounterEmptyPops
+
+	// CounterPoolGets 统计从池中获取节点的次数
+	// CounterPoolGets counts the nodes taken from the pool
+	CounterPoolGets
+
+	// CounterPoolPuts 统计放回池中的节点数量
+	// CounterPoolPuts counts the nodes put back into the pool
+	CounterPoolPuts
+
+	// CounterPoolMisses 统计池中没有可用节点而新分配节点的次数
+	// CounterPoolMisses counts the nodes newly allocated because the pool had none available
+	CounterPoolMisses
+
+	// statsCounters 是计数器的数量
+	// statsCounters is the number of counters
+	statsCounters
+)
+
+const (
+	// statsShards 是计数器分片的数量，必须是 2 的幂
+	// statsShards is the number of counter shards, must be a power of two
+	statsShards = 32
+
+	// statsShardPad 是把每个分片填充到缓存行大小整数倍所需的字节数
+	// statsShardPad is the number of bytes needed to pad every shard to a multiple of the cache line size
+	statsShardPad = (CacheLineSize - statsCounters*8%CacheLineSize) % CacheLineSize
+)
+
+// Stats 是容器统计信息的快照。计数器只在启用统计时累加，并且在容器的整个生命周期内单调递增，Reset 不会清零它们
+// Stats is a snapshot of the statistics of a container. The counters only accumulate when statistics are enabled and increase monotonically over the whole life of the container, Reset does not clear them
+type Stats struct {
+	// Pushes 是成功推入 (插入) 的元素数量
+	// Pushes is the number of elements pushed (inserted) successfully
+	Pushes uint64
+
+	// Pops 是成功弹出 (删除) 的元素数量
+	// Pops is the number of elements popped (deleted) successfully
+	Pops uint64
+
+	// CASFailures 是失败并需要重试的 CAS 操作数量，反映了竞争的激烈程度
+	// CASFailures is the number of CAS operations that failed and had to be retried, it reflects how heavy the contention is
+	CASFailures uint64
+
+	// FullRejections 是因为容器已满而被拒绝的推入数量
+	// FullRejections is the number of pushes rejected because the container was full
+	FullRejections uint64
+
+	// EmptyPops 是因为容器为空而没有弹出元素的次数
+	// EmptyPops is the number of pops that found the container empty
+	EmptyPops uint64
+
+	// PoolGets 是从池中获取节点的次数
+	// PoolGets is the number of nodes taken from the pool
+	PoolGets uint64
+
+	// PoolPuts 是放回池中的节点数量
+	// PoolPuts is the number of nodes put back into the pool
+	PoolPuts uint64
+
+	// PoolMisses 是池中没有可用节点而新分配节点的次数，PoolGets 减去 PoolMisses 就是池的命中次数
+	// PoolMisses is the number of nodes newly allocated because the pool had none available, PoolGets minus PoolMisses is the number of pool hits
+	PoolMisses uint64
+}
+
+// statsShard 是计数器的一个分片，填充到缓存行大小的整数倍，避免不同分片之间的伪共享。
+// 填充放在计数器之前：结构体末尾的零长度字段会让编译器额外填充，破坏 32 位平台上计数器的 8 字节对齐，而填充的长度总是 8 的倍数
+// statsShard is a shard of the counters, padded to a multiple of the cache line size to avoid false sharing between shards.
+// The padding comes before the counters: a zero-size field at the end of a struct makes the compiler add extra padding, which breaks the 8-byte alignment of the counters on 32-bit platforms, while the length of the padding is always a multiple of 8
+type statsShard struct {
+	_        [statsShardPad]byte
+	counters [statsCounters]uint64
+}
+
+// Counters 是一组分片的统计计数器。并发的调用者根据 goroutine 栈地址分散到不同的分片，读取时再把所有分片相加。
+// nil 的 *Counters 表示没有启用统计，此时所有的方法都只需要一次 nil 判断
+// Counters is a set of sharded statistics counters. Concurrent callers are spread across different shards by their goroutine stack address, and the shards are summed up when read.
+// A nil *Counters means statistics are disabled, every method then only costs a nil check
+type Counters struct {
+	shards [statsShards]statsShard
+}
+
+// NewCounters 函数用于创建一组新的统计计数器
+// The NewCounters function is used to create a new set of statistics counters
+func NewCounters() *Counters {
+	return &Counters{}
+}
+
+// Inc 方法用于把计数器 k 加 1，c 为 nil 时什么也不做
+// The Inc method is used to add 1 to counter k, it does nothing when c is nil
+func (c *Counters) Inc(k Counter) {
+	if c != nil {
+		c.add(k, 1)
+	}
+}
+
+// Add 方法用于把计数器 k 加 n，c 为 nil 时什么也不做
+// The Add method is used to add n to counter k, it does nothing when c is nil
+func (c *Counters) Add(k Counter, n int) {
+	if c != nil && n > 0 {
+		c.add(k, uint64(n))
+	}
+}
+
+// add 方法用于把当前 goroutine 所在分片中的计数器 k 加 n
+// The add method is used to add n to counter k in the shard of the current goroutine
+func (c *Counters) add(k Counter, n uint64) {
+	atomic.AddUint64(&c.shards[StackHash()&(statsShards-1)].counters[k], n)
+}
+
+// Snapshot 方法用于返回所有计数器的快照，c 为 nil 时返回零值
+// The Snapshot method is used to return a snapshot of all the counters, the zero value is returned when c is nil
+func (c *Counters) Snapshot() Stats {
+	var sum [statsCounters]uint64
+	if c != nil {
+		for i := range c.shards {
+			for k := range sum {
+				sum[k] += atomic.LoadUint64(&c.shards[i].counters[k])
+			}
+		}
+	}
+	return Stats{
+		Pushes:         sum[CounterPushes],
+		Pops:           sum[CounterPops],
+		CASFailures:    sum[CounterCASFailures],
+		FullRejections: sum[CounterFullRejections],
+		EmptyPops:      sum[CounterEmptyPops],
+		PoolGets:       sum[CounterPoolGets],
+		PoolPuts:       sum[CounterPoolPuts],
+		PoolMisses:     sum[CounterPoolMisses],
+	}
+}
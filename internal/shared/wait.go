@@ -0,0 +1,130 @@
+package shared
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// minWaiterSpins 是挂起之前最少的自旋次数
+	// minWaiterSpins is the minimum number of spins before parking
+	minWaiterSpins = 4
+
+	// maxWaiterSpins 是挂起之前最多的自旋次数
+	// maxWaiterSpins is the maximum number of spins before parking
+	maxWaiterSpins = 128
+)
+
+// Waiter 是一个自适应的等待器，先自旋一段时间，如果条件仍然不满足，再挂起 goroutine 直到被唤醒。
+// 零值可以直接使用。没有 goroutine 等待时，Notify 只有一次原子读取的开销。
+// Waiter is an adaptive waiter, it spins for a while first and parks the goroutine until it is woken up if the condition is still not met.
+// The zero value is ready to use. When no goroutine is waiting, Notify only costs one atomic load.
+type Waiter struct {
+	// waiters 是当前挂起的 goroutine 数量
+	// waiters is the number of goroutines currently parked
+	waiters int32
+
+	// spins 是当前的自旋次数，根据自旋的成功率自适应调整
+	// spins is the current number of spins, adjusted adaptively according to how often spinning succeeds
+	spins int32
+
+	// mu 用于保护 ch
+	// mu is used to protect ch
+	mu sync.Mutex
+
+	// ch 在唤醒时被关闭，所有挂起的 goroutine 都会被唤醒
+	// ch is closed on wake up, all parked goroutines are woken up
+	ch chan struct{}
+}
+
+// Notify 方法用于唤醒所有挂起的 goroutine
+// The Notify method is used to wake up all parked goroutines
+func (w *Waiter) Notify() {
+	// 没有 goroutine 挂起时直接返回
+	// Return directly when no goroutine is parked
+	if atomic.LoadInt32(&w.waiters) == 0 {
+		return
+	}
+
+	// 关闭当前的通道，唤醒所有挂起的 goroutine
+	// Close the current channel to wake up all parked goroutines
+	w.mu.Lock()
+	if w.ch != nil {
+		close(w.ch)
+		w.ch = nil
+	}
+	w.mu.Unlock()
+}
+
+// Wait 方法用于等待，直到 try 返回 true 或者 ctx 结束。ctx 结束时返回 ctx.Err()
+// The Wait method is used to wait until try returns true or ctx is done. It returns ctx.Err() when ctx is done
+func (w *Waiter) Wait(ctx context.Context, try func() bool) error {
+	// 如果 ctx 已经结束，直接返回
+	// If ctx is already done, return directly
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// 自旋阶段，让出处理器，等待其他 goroutine 生产数据
+	// Spinning phase, yield the processor and wait for other goroutines to produce data
+	spins := atomic.LoadInt32(&w.spins)
+	if spins < minWaiterSpins {
+		spins = minWaiterSpins
+	}
+	for i := int32(0); i < spins; i++ {
+		if try() {
+			// 自旋成功，增加下一次的自旋次数
+			// Spinning succeeded, increase the number of spins next time
+			if spins < maxWaiterSpins {
+				atomic.StoreInt32(&w.spins, spins*2)
+			}
+			return nil
+		}
+		runtime.Gosched()
+	}
+
+	// 自旋失败，减少下一次的自旋次数
+	// Spinning failed, reduce the number of spins next time
+	if spins > minWaiterSpins {
+		atomic.StoreInt32(&w.spins, spins/2)
+	}
+
+	// 挂起阶段
+	// Parking phase
+	for {
+		// 在登记为等待者之前获取通道，保证之后的 Notify 一定会关闭这个通道
+		// Get the channel before registering as a waiter, so a later Notify is guaranteed to close this channel
+		w.mu.Lock()
+		if w.ch == nil {
+			w.ch = make(chan struct{})
+		}
+		ch := w.ch
+		atomic.AddInt32(&w.waiters, 1)
+		w.mu.Unlock()
+
+		// 登记之后再检查一次条件，避免丢失唤醒
+		// Check the condition again after registering to avoid a lost wake up
+		if try() {
+			atomic.AddInt32(&w.waiters, -1)
+			return nil
+		}
+
+		// 等待唤醒或者 ctx 结束
+		// Wait for a wake up or for ctx to be done
+		select {
+		case <-ch:
+			atomic.AddInt32(&w.waiters, -1)
+		case <-ctx.Done():
+			atomic.AddInt32(&w.waiters, -1)
+			return ctx.Err()
+		}
+
+		// 被唤醒后再次尝试，失败则继续挂起
+		// Try again after being woken up, park again on failure
+		if try() {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import "expvar"
+
+// defaultExpvarName 是默认注册表在 expvar 中的名称
+// defaultExpvarName is the name of the default registry in expvar
+const defaultExpvarName = "lockfree"
+
+func init() {
+	// 与 expvar 自动发布 memstats 一样，导入本包时自动发布默认注册表。名称已经被宿主程序或者其他库使用时跳过发布，导入本包不会引起 panic
+	// Like expvar publishes memstats automatically, the default registry is published automatically when this package is imported. Publishing is skipped when the name is already used by the host program or another library, so importing this package never panics
+	publishUnused(DefaultRegistry, defaultExpvarName)
+}
+
+// publishUnused 函数用于在 name 还没有被使用时以 name 为名称发布注册表 r，返回是否发布了 r
+// The publishUnused function is used to publish the registry r under name if name is not used yet, it returns whether r was published
+func publishUnused(r *Registry, name string) bool {
+	if expvar.Get(name) != nil {
+		return false
+	}
+	r.Publish(name)
+	return true
+}
+
+// Publish 方法用于以 name 为名称通过 expvar 发布注册表。每次读取 expvar 时都会重新采样所有的容器，
+// 结果是一个以容器名称为键、以 Sample 为值的 JSON 对象。与 expvar.Publish 一样，名称已经被使用时会 panic
+// The Publish method is used to publish the registry through expvar under name. All the containers are sampled again every time expvar is read,
+// the result is a JSON object with the container names as keys and the Samples as values. Like expvar.Publish, it panics when the name is already used
+func (r *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return r.Snapshot()
+	}))
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/shengyanli1982/lockfree/queue"
+	"github.com/shengyanli1982/lockfree/ringbuffer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Publish(t *testing.T) {
+	r := NewRegistry()
+	q := queue.New(queue.WithStats())
+	assert.NoError(t, r.Register("orders", q))
+	r.Publish("metrics_test_publish")
+
+	// expvar samples the containers every time it is read
+	q.Push(1)
+	q.Push(2)
+
+	var samples map[string]Sample
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("metrics_test_publish").String()), &samples), "Failed to decode the published value")
+	assert.Equal(t, int64(2), samples["orders"].Length, "Incorrect published length")
+	assert.Equal(t, uint64(2), samples["orders"].Pushes, "Incorrect published pushes")
+
+	// Publishing a name twice panics like expvar.Publish
+	assert.Panics(t, func() { r.Publish("metrics_test_publish") }, "Publishing a name twice should panic")
+}
+
+func TestDefaultRegistry_Published(t *testing.T) {
+	rb := ringbuffer.New(8)
+	assert.NoError(t, Register("metrics_test_ring", rb))
+	defer Unregister("metrics_test_ring")
+	rb.Push(1)
+
+	// The default registry is published under "lockfree" as soon as the package is imported
+	var samples map[string]Sample
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("lockfree").String()), &samples), "Failed to decode the published value")
+	assert.Equal(t, int64(1), samples["metrics_test_ring"].Length, "Incorrect published length")
+	assert.Equal(t, int64(8), samples["metrics_test_ring"].Capacity, "Incorrect published capacity")
+}
+
+func TestRegistry_PublishUnused(t *testing.T) {
+	// A name already published by the host program is left alone instead of panicking
+	expvar.Publish("metrics_test_taken", expvar.Func(func() interface{} { return "host" }))
+	r := NewRegistry()
+	assert.NotPanics(t, func() {
+		assert.False(t, publishUnused(r, "metrics_test_taken"), "A used name should not be published")
+	})
+	assert.Equal(t, `"host"`, expvar.Get("metrics_test_taken").String(), "The value of the host program should be kept")
+
+	// A name that is not used yet is published
+	assert.True(t, publishUnused(r, "metrics_test_unused"), "An unused name should be published")
+	assert.NotNil(t, expvar.Get("metrics_test_unused"), "The registry should be published")
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是容器统计信息的快照，与各个容器包中的 Stats 是同一个类型
+// Stats is a snapshot of the statistics of a container, it is the same type as Stats in every container package
+type Stats = shd.Stats
+
+// Container 是一个接口，定义了可以注册的容器。所有的容器都实现了它，
+// 除此之外，容器还需要通过 Length 或 Count 方法报告元素的数量，可以通过 Capacity 方法报告容量
+// Container is an interface that defines a container that can be registered. Every container implements it,
+// besides that a container has to report the number of its elements with a Length or Count method, and may report its capacity with a Capacity method
+type Container = interface {
+	// Stats 方法用于返回容器的统计信息快照
+	// The Stats method is used to return a snapshot of the statistics of the container
+	Stats() Stats
+}
+
+// Kind 是指标的类型
+// Kind is the type of a metric
+type Kind int
+
+const (
+	// Gauge 是可以增加也可以减少的指标，例如长度
+	// Gauge is a metric that can go up and down, such as the length
+	Gauge Kind = iota
+
+	// Counter 是单调递增的指标，例如推入的元素数量
+	// Counter is a metric that only goes up, such as the number of pushed elements
+	Counter
+)
+
+// Desc 是指标的描述，它的字段与 Prometheus 客户端创建描述所需的参数一一对应
+// Desc is the description of a metric, its fields map one to one to the arguments the Prometheus client needs to create a description
+type Desc struct {
+	// Name 是指标的完整名称
+	// Name is the fully qualified name of the metric
+	Name string
+
+	// Help 是指标的说明
+	// Help is the help text of the metric
+	Help string
+
+	// Kind 是指标的类型
+	// Kind is the type of the metric
+	Kind Kind
+
+	// Labels 是指标的标签名称
+	// Labels are the label names of the metric
+	Labels []string
+}
+
+// Metric 是一个指标的采样值
+// Metric is a sampled value of a metric
+type Metric struct {
+	// Desc 是指标的描述
+	// Desc is the description of the metric
+	Desc *Desc
+
+	// LabelValues 是与 Desc.Labels 一一对应的标签值
+	// LabelValues are the label values, one for each of Desc.Labels
+	LabelValues []string
+
+	// Value 是指标的值
+	// Value is the value of the metric
+	Value float64
+}
+
+// Collector 是一个接口，定义了指标的收集器。它不依赖任何 Prometheus 包，
+// 只需要几行代码就可以把它适配为 Prometheus 客户端的 Collector 并注册
+// Collector is an interface that defines a collector of metrics. It does not depend on any Prometheus package,
+// a few lines of code adapt it to a Collector of the Prometheus client that can be registered
+type Collector = interface {
+	// Describe 方法用于把收集器可能产生的所有指标的描述传给 fn
+	// The Describe method is used to pass the descriptions of all the metrics the collector may produce to fn
+	Describe(fn func(*Desc))
+
+	// Collect 方法用于采样所有的指标，并把它们逐个传给 fn
+	// The Collect method is used to sample all the metrics and pass them to fn one by one
+	Collect(fn func(Metric))
+}
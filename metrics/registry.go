@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrEmptyName 表示注册容器时没有提供名称
+	// ErrEmptyName means no name was given when registering a container
+	ErrEmptyName = errors.New("metrics: empty container name")
+
+	// ErrDuplicateName 表示这个名称已经注册过其他容器
+	// ErrDuplicateName means another container has already been registered under the name
+	ErrDuplicateName = errors.New("metrics: container name already registered")
+
+	// ErrNoLength 表示容器既没有 Length 方法也没有 Count 方法
+	// ErrNoLength means the container has neither a Length method nor a Count method
+	ErrNoLength = errors.New("metrics: container has no Length or Count method")
+)
+
+// nameLabel 是区分不同容器的标签名称
+// nameLabel is the name of the label that tells the containers apart
+const nameLabel = "name"
+
+// 所有指标的描述，每个容器都通过 name 标签区分
+// The descriptions of all the metrics, every container is told apart by the name label
+var (
+	lengthDesc         = &Desc{Name: "lockfree_length", Help: "Number of elements in the container.", Kind: Gauge, Labels: []string{nameLabel}}
+	capacityDesc       = &Desc{Name: "lockfree_capacity", Help: "Capacity of the bounded container.", Kind: Gauge, Labels: []string{nameLabel}}
+	pushesDesc         = &Desc{Name: "lockfree_pushes_total", Help: "Elements pushed (inserted) successfully.", Kind: Counter, Labels: []string{nameLabel}}
+	popsDesc           = &Desc{Name: "lockfree_pops_total", Help: "Elements popped (deleted) successfully.", Kind: Counter, Labels: []string{nameLabel}}
+	casFailuresDesc    = &Desc{Name: "lockfree_cas_failures_total", Help: "CAS operations that failed and had to be retried.", Kind: Counter, Labels: []string{nameLabel}}
+	fullRejectionsDesc = &Desc{Name: "lockfree_full_rejections_total", Help: "Pushes rejected because the container was full.", Kind: Counter, Labels: []string{nameLabel}}
+	emptyPopsDesc      = &Desc{Name: "lockfree_empty_pops_total", Help: "Pops that found the container empty.", Kind: Counter, Labels: []string{nameLabel}}
+	poolGetsDesc       = &Desc{Name: "lockfree_pool_gets_total", Help: "Nodes taken from the pool.", Kind: Counter, Labels: []string{nameLabel}}
+	poolPutsDesc       = &Desc{Name: "lockfree_pool_puts_total", Help: "Nodes put back into the pool.", Kind: Counter, Labels: []string{nameLabel}}
+	poolMissesDesc     = &Desc{Name: "lockfree_pool_misses_total", Help: "Nodes newly allocated because the pool had none available.", Kind: Counter, Labels: []string{nameLabel}}
+
+	allDescs = []*Desc{
+		lengthDesc, capacityDesc, pushesDesc, popsDesc, casFailuresDesc,
+		fullRejectionsDesc, emptyPopsDesc, poolGetsDesc, poolPutsDesc, poolMissesDesc,
+	}
+)
+
+// Sample 是一个容器在某一时刻的采样，通过 expvar 发布时编码为 JSON 对象
+// Sample is a sample of a container at one point in time, it is encoded as a JSON object when published through expvar
+type Sample struct {
+	// Length 是容器中元素的数量
+	// Length is the number of elements in the container
+	Length int64
+
+	// Capacity 是有界容器的容量，无界容器为 0
+	// Capacity is the capacity of a bounded container, it is 0 for an unbounded container
+	Capacity int64 `json:",omitempty"`
+
+	// Stats 是容器的统计信息，没有启用 WithStats 选项的容器所有计数器都为 0
+	// Stats are the statistics of the container, all the counters are 0 for a container without the WithStats option
+	Stats
+}
+
+// entry 是注册表中的一个容器，长度和容量的读取函数在注册时确定
+// entry is a container in the registry, the functions reading the length and the capacity are determined at registration
+type entry struct {
+	// container 是注册的容器
+	// container is the registered container
+	container Container
+
+	// length 是读取容器长度的函数
+	// length is the function reading the length of the container
+	length func() int64
+
+	// capacity 是读取容器容量的函数，无界容器为 nil
+	// capacity is the function reading the capacity of the container, it is nil for an unbounded container
+	capacity func() int64
+}
+
+// sample 方法用于采样容器
+// The sample method is used to sample the container
+func (e *entry) sample() Sample {
+	s := Sample{Length: e.length(), Stats: e.container.Stats()}
+	if e.capacity != nil {
+		s.Capacity = e.capacity()
+	}
+	return s
+}
+
+// Registry 是按名称注册容器的注册表，可以并发使用。注册表只在注册和读取时加锁，容器本身的操作不受影响
+// Registry is a registry of containers by name, it is safe for concurrent use. The registry only locks on registration and when it is read, the operations of the containers are not affected
+type Registry struct {
+	// mu 保护 entries
+	// mu protects entries
+	mu sync.RWMutex
+
+	// entries 是按名称保存的容器
+	// entries are the containers by name
+	entries map[string]*entry
+}
+
+// NewRegistry 函数用于创建一个新的空注册表
+// The NewRegistry function is used to create a new empty registry
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]*entry)}
+}
+
+// Register 方法用于以 name 为名称注册容器 c。容器需要通过 Length 或 Count 方法报告元素的数量，
+// 如果它还有 Capacity 方法，容量也会被导出。名称为空、已经被使用或者容器没有长度时返回错误
+// The Register method is used to register container c under name. The container has to report the number of its elements with a Length or Count method,
+// if it also has a Capacity method the capacity is exported as well. An error is returned when the name is empty or already used, or when the container has no length
+func (r *Registry) Register(name string, c Container) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	// 确定读取长度和容量的方法
+	// Determine the methods reading the length and the capacity
+	e := &entry{container: c}
+	switch v := c.(type) {
+	case interface{ Length() int64 }:
+		e.length = v.Length
+	case interface{ Count() int64 }:
+		e.length = v.Count
+	default:
+		return ErrNoLength
+	}
+	if v, ok := c.(interface{ Capacity() int64 }); ok {
+		e.capacity = v.Capacity
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		return ErrDuplicateName
+	}
+	r.entries[name] = e
+	return nil
+}
+
+// Unregister 方法用于注销名称为 name 的容器，返回是否注销了容器
+// The Unregister method is used to unregister the container named name, returns whether a container was unregistered
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; !ok {
+		return false
+	}
+	delete(r.entries, name)
+	return true
+}
+
+// Names 方法用于返回所有注册的容器名称，按字典序排序
+// The Names method is used to return the names of all the registered containers, sorted lexicographically
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// Snapshot 方法用于采样所有注册的容器，返回按名称保存的采样
+// The Snapshot method is used to sample all the registered containers, returns the samples by name
+func (r *Registry) Snapshot() map[string]Sample {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	samples := make(map[string]Sample, len(r.entries))
+	for name, e := range r.entries {
+		samples[name] = e.sample()
+	}
+	return samples
+}
+
+// Describe 方法用于把注册表可能产生的所有指标的描述传给 fn，它实现了 Collector 接口
+// The Describe method is used to pass the descriptions of all the metrics the registry may produce to fn, it implements the Collector interface
+func (r *Registry) Describe(fn func(*Desc)) {
+	for _, d := range allDescs {
+		fn(d)
+	}
+}
+
+// Collect 方法用于采样所有注册的容器，并把每个容器的指标逐个传给 fn，它实现了 Collector 接口。
+// 无界容器没有容量指标
+// The Collect method is used to sample all the registered containers and pass the metrics of every container to fn one by one, it implements the Collector interface.
+// Unbounded containers have no capacity metric
+func (r *Registry) Collect(fn func(Metric)) {
+	for name, s := range r.Snapshot() {
+		labels := []string{name}
+		emit := func(d *Desc, v uint64) {
+			fn(Metric{Desc: d, LabelValues: labels, Value: float64(v)})
+		}
+
+		fn(Metric{Desc: lengthDesc, LabelValues: labels, Value: float64(s.Length)})
+		if s.Capacity > 0 {
+			fn(Metric{Desc: capacityDesc, LabelValues: labels, Value: float64(s.Capacity)})
+		}
+		emit(pushesDesc, s.Pushes)
+		emit(popsDesc, s.Pops)
+		emit(casFailuresDesc, s.CASFailures)
+		emit(fullRejectionsDesc, s.FullRejections)
+		emit(emptyPopsDesc, s.EmptyPops)
+		emit(poolGetsDesc, s.PoolGets)
+		emit(poolPutsDesc, s.PoolPuts)
+		emit(poolMissesDesc, s.PoolMisses)
+	}
+}
+
+// DefaultRegistry 是默认的注册表，包级别的 Register 和 Unregister 函数使用它，它以 "lockfree" 为名称通过 expvar 发布
+// DefaultRegistry is the default registry, it is used by the package level Register and Unregister functions and published through expvar under the name "lockfree"
+var DefaultRegistry = NewRegistry()
+
+// Register 函数用于在默认注册表中以 name 为名称注册容器 c
+// The Register function is used to register container c under name in the default registry
+func Register(name string, c Container) error {
+	return DefaultRegistry.Register(name, c)
+}
+
+// Unregister 函数用于从默认注册表中注销名称为 name 的容器
+// The Unregister function is used to unregister the container named name from the default registry
+func Unregister(name string) bool {
+	return DefaultRegistry.Unregister(name)
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/shengyanli1982/lockfree/hashmap"
+	"github.com/shengyanli1982/lockfree/queue"
+	"github.com/shengyanli1982/lockfree/ringbuffer"
+	"github.com/stretchr/testify/assert"
+)
+
+// noLength is a container with statistics but without a Length or Count method
+type noLength struct{}
+
+func (noLength) Stats() Stats { return Stats{} }
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+
+	// Test registering containers with Length and with Count
+	assert.NoError(t, r.Register("orders", queue.New()), "Failed to register a queue")
+	assert.NoError(t, r.Register("events", ringbuffer.New(8)), "Failed to register a ring buffer")
+	assert.NoError(t, r.Register("sessions", hashmap.NewOf[string, int]()), "Failed to register a generic hash map")
+
+	// Test the registration errors
+	assert.ErrorIs(t, r.Register("", queue.New()), ErrEmptyName, "An empty name should be rejected")
+	assert.ErrorIs(t, r.Register("orders", queue.New()), ErrDuplicateName, "A duplicate name should be rejected")
+	assert.ErrorIs(t, r.Register("broken", noLength{}), ErrNoLength, "A container without length should be rejected")
+	assert.Equal(t, []string{"events", "orders", "sessions"}, r.Names(), "Incorrect registered names")
+
+	// Test unregistering, the name can be used again afterwards
+	assert.True(t, r.Unregister("orders"), "Failed to unregister a container")
+	assert.False(t, r.Unregister("orders"), "Unregistered a container twice")
+	assert.NoError(t, r.Register("orders", queue.New()), "Failed to register a name again after unregistering it")
+}
+
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+	q := queue.New(queue.WithStats())
+	rb := ringbuffer.New(4, ringbuffer.WithStats())
+	assert.NoError(t, r.Register("queue", q))
+	assert.NoError(t, r.Register("ring", rb))
+
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+	q.Pop()
+	for i := 0; i < 5; i++ {
+		rb.Push(i)
+	}
+
+	// Verify the samples, only the ring buffer has a capacity
+	s := r.Snapshot()
+	assert.Equal(t, int64(2), s["queue"].Length, "Incorrect queue length")
+	assert.Equal(t, int64(0), s["queue"].Capacity, "An unbounded queue should have no capacity")
+	assert.Equal(t, uint64(3), s["queue"].Pushes, "Incorrect number of queue pushes")
+	assert.Equal(t, uint64(1), s["queue"].Pops, "Incorrect number of queue pops")
+	assert.Equal(t, int64(4), s["ring"].Length, "Incorrect ring buffer count")
+	assert.Equal(t, int64(4), s["ring"].Capacity, "Incorrect ring buffer capacity")
+	assert.Equal(t, uint64(1), s["ring"].FullRejections, "Incorrect number of full rejections")
+}
+
+func TestRegistry_Collect(t *testing.T) {
+	r := NewRegistry()
+	rb := ringbuffer.New(4, ringbuffer.WithStats())
+	assert.NoError(t, r.Register("queue", queue.New()))
+	assert.NoError(t, r.Register("ring", rb))
+	rb.Push(1)
+
+	// Every collected metric must have been described
+	described := map[*Desc]bool{}
+	r.Describe(func(d *Desc) {
+		described[d] = true
+	})
+
+	values := map[string]float64{}
+	r.Collect(func(m Metric) {
+		assert.True(t, described[m.Desc], "Metric %s was not described", m.Desc.Name)
+		assert.Equal(t, len(m.Desc.Labels), len(m.LabelValues), "Incorrect number of label values")
+		values[m.Desc.Name+"/"+m.LabelValues[0]] = m.Value
+	})
+
+	assert.Equal(t, float64(1), values["lockfree_length/ring"], "Incorrect length metric")
+	assert.Equal(t, float64(4), values["lockfree_capacity/ring"], "Incorrect capacity metric")
+	assert.Equal(t, float64(1), values["lockfree_pushes_total/ring"], "Incorrect pushes metric")
+	assert.Equal(t, Counter, pushesDesc.Kind, "Pushes should be a counter")
+	_, ok := values["lockfree_capacity/queue"]
+	assert.False(t, ok, "An unbounded queue should have no capacity metric")
+	assert.Equal(t, float64(0), values["lockfree_pops_total/queue"], "A queue without WithStats should report zero counters")
+}
+
+func TestRegistry_Parallel(t *testing.T) {
+	r := NewRegistry()
+	q := queue.New(queue.WithStats())
+
+	wg := sync.WaitGroup{}
+
+	// Register, unregister and collect while the queue is in use
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("queue-%d", i)
+			for j := 0; j < 1000; j++ {
+				switch i % 3 {
+				case 0:
+					_ = r.Register(name, q)
+					r.Unregister(name)
+				case 1:
+					r.Collect(func(Metric) {})
+				default:
+					q.Push(j)
+					q.Pop()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Empty(t, r.Names(), "All the containers should have been unregistered")
+}
@@ -0,0 +1,35 @@
+package priorityqueue
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是优先队列统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a priority queue, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// PriorityQueue 是一个接口，定义了优先队列的基本操作
+// PriorityQueue is an interface that defines basic operations of a priority queue
+type PriorityQueue = interface {
+	// Push 方法用于向优先队列中添加一个元素，priority 越小越先弹出
+	// The Push method is used to add an element to the priority queue, smaller priorities are popped first
+	Push(value interface{}, priority int64)
+
+	// PopMin 方法用于从优先队列中移除并返回优先级最小的元素
+	// The PopMin method is used to remove and return the element with the smallest priority from the priority queue
+	PopMin() interface{}
+
+	// Reset 方法用于重置/清空优先队列
+	// The Reset method is used to reset/clear the priority queue
+	Reset()
+
+	// Length 方法返回优先队列的长度（即优先队列中元素的数量）
+	// The Length method returns the length of the priority queue (i.e., the number of elements in the priority queue)
+	Length() int64
+
+	// IsEmpty 方法用于检查优先队列是否为空
+	// The IsEmpty method is used to check if the priority queue is empty
+	IsEmpty() bool
+}
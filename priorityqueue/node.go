@@ -0,0 +1,154 @@
+package priorityqueue
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// levelOf 是节点在某一层的链接。next 指向下一个节点，节点在这一层被逻辑删除后，next 改为指向同一个链接中的 succ，
+// succ 保存删除时的下一个节点。删除标记和下一个节点因此可以通过一次 CAS 操作同时修改，并且不需要为每次 CAS 分配新的对象。
+// levelOf is the link of a node at one level. next points to the next node, once the node is logically deleted at this level next points to succ in the same link instead,
+// and succ holds the next node at the time of the deletion. The deletion mark and the next node can therefore be changed together with a single CAS operation, without allocating a new object for every CAS.
+type levelOf[T any] struct {
+	// next 是下一个节点，类型为 *nodeOf[T]，nil 表示这一层的末尾。指向 succ 时表示节点在这一层已经被删除
+	// next is the next node, the type is *nodeOf[T], nil means the end of this level. It means the node has been deleted at this level when it points to succ
+	next unsafe.Pointer
+
+	// succ 是节点在这一层被删除时的下一个节点，只由弹出节点的 goroutine 写入
+	// succ is the next node at the time the node was deleted at this level, it is only written by the goroutine popping the node
+	succ unsafe.Pointer
+}
+
+// nodeOf 是优先队列底层跳表中的节点，T 为节点中值的类型
+// nodeOf is a node of the skiplist under the priority queue, T is the type of the value in the node
+type nodeOf[T any] struct {
+	// priority 是节点的优先级，值越小越先弹出
+	// priority is the priority of the node, smaller values are popped first
+	priority int64
+
+	// seq 是节点推入时的序号，用于让优先级相同的节点按推入顺序排列，并保证每个节点的键都是唯一的
+	// seq is the sequence number of the node when it was pushed, it keeps nodes with the same priority in push order and makes the key of every node unique
+	seq uint64
+
+	// value 是节点的值
+	// value is the value of the node
+	value T
+
+	// levels 是节点在每一层的链接，与节点一起分配，使用节点池时与节点一起复用
+	// levels holds the link of the node at every level, it is allocated with the node and reused with it when a node pool is used
+	levels []levelOf[T]
+
+	// popped 表示节点是否已经被弹出，只有把它从 0 改为 1 的 goroutine 可以弹出节点并标记它的链接
+	// popped indicates whether the node has been popped, only the goroutine that changes it from 0 to 1 may pop the node and mark its links
+	popped int32
+
+	// done 记录推入和弹出中已经结束的数量，两者都结束之后节点才能被回收
+	// done counts how many of the push and the pop have finished, the node can only be reclaimed after both of them have finished
+	done int32
+
+	// link 是节点回收器串联退休节点时使用的指针
+	// link is the pointer used by the node reclaimer to chain retired nodes
+	link unsafe.Pointer
+}
+
+// less 方法用于判断节点的键是否小于 (priority, seq)
+// The less method is used to determine whether the key of the node is less than (priority, seq)
+func (n *nodeOf[T]) less(priority int64, seq uint64) bool {
+	return n.priority < priority || (n.priority == priority && n.seq < seq)
+}
+
+// loadNext 方法用于加载第 level 层的下一个节点，marked 表示节点在这一层已经被删除，此时返回删除时的下一个节点
+// The loadNext method is used to load the next node at the given level, marked means the node has been deleted at this level, the next node at the time of the deletion is returned then
+func (n *nodeOf[T]) loadNext(level int) (next *nodeOf[T], marked bool) {
+	l := &n.levels[level]
+	p := atomic.LoadPointer(&l.next)
+	if p == unsafe.Pointer(&l.succ) {
+		return (*nodeOf[T])(atomic.LoadPointer(&l.succ)), true
+	}
+	return (*nodeOf[T])(p), false
+}
+
+// storeNext 方法用于设置第 level 层的下一个节点
+// The storeNext method is used to set the next node at the given level
+func (n *nodeOf[T]) storeNext(level int, next *nodeOf[T]) {
+	atomic.StorePointer(&n.levels[level].next, unsafe.Pointer(next))
+}
+
+// casNext 方法用于比较并交换第 level 层的下一个节点，节点在这一层已经被删除时总是失败
+// The casNext method is used to compare and swap the next node at the given level, it always fails once the node has been deleted at this level
+func (n *nodeOf[T]) casNext(level int, old, new *nodeOf[T]) bool {
+	return atomic.CompareAndSwapPointer(&n.levels[level].next, unsafe.Pointer(old), unsafe.Pointer(new))
+}
+
+// mark 方法用于在第 level 层逻辑删除节点，只能由弹出节点的 goroutine 调用，因此 succ 只有一个写入者
+// The mark method is used to logically delete the node at the given level, it must only be called by the goroutine popping the node, so succ has a single writer
+func (n *nodeOf[T]) mark(level int) {
+	l := &n.levels[level]
+	for {
+		p := atomic.LoadPointer(&l.next)
+		if p == unsafe.Pointer(&l.succ) {
+			return
+		}
+		atomic.StorePointer(&l.succ, p)
+		if atomic.CompareAndSwapPointer(&l.next, p, unsafe.Pointer(&l.succ)) {
+			return
+		}
+	}
+}
+
+// reset 方法用于重置节点的所有字段，保留 levels 切片的底层数组以便复用
+// The reset method is used to reset all the fields of the node, the backing array of the levels slice is kept for reuse
+func (n *nodeOf[T]) reset() {
+	var zero T
+	n.priority = 0
+	n.seq = 0
+	n.value = zero
+	for i := range n.levels {
+		n.levels[i] = levelOf[T]{}
+	}
+	n.levels = n.levels[:0]
+	n.popped = 0
+	n.done = 0
+	n.link = nil
+}
+
+// nodePoolOf 是优先队列节点的节点池
+// nodePoolOf is the node pool of priority queue nodes
+type nodePoolOf[T any] struct {
+	// pool 是一个同步池，用于存储和获取节点
+	// pool is a sync pool, used to store and retrieve nodes
+	pool sync.Pool
+
+	// stats 是节点池的统计计数器，为 nil 时不统计，必须在使用节点池之前设置
+	// stats are the statistics counters of the node pool, nothing is counted when it is nil, they must be set before the node pool is used
+	stats *shd.Counters
+}
+
+// newNodePoolOf 函数用于创建一个新的节点池，节点的 levels 切片按最大层数分配，复用时不需要重新分配
+// The newNodePoolOf function is used to create a new node pool, the levels slice of a node is allocated with the maximum number of levels so it never needs to be reallocated on reuse
+func newNodePoolOf[T any]() *nodePoolOf[T] {
+	np := &nodePoolOf[T]{}
+	np.pool.New = func() interface{} {
+		np.stats.Inc(shd.CounterPoolMisses)
+		return &nodeOf[T]{levels: make([]levelOf[T], 0, maxLevel)}
+	}
+	return np
+}
+
+// get 方法用于从节点池中获取一个节点
+// The get method is used to get a node from the node pool
+func (np *nodePoolOf[T]) get() *nodeOf[T] {
+	np.stats.Inc(shd.CounterPoolGets)
+	return np.pool.Get().(*nodeOf[T])
+}
+
+// put 方法用于重置一个节点并将其放回节点池
+// The put method is used to reset a node and put it back into the node pool
+func (np *nodePoolOf[T]) put(n *nodeOf[T]) {
+	n.reset()
+	np.stats.Inc(shd.CounterPoolPuts)
+	np.pool.Put(n)
+}
@@ -0,0 +1,343 @@
+package priorityqueue
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// maxLevel 是跳表的最大层数，每一层的节点数量约为下一层的 1/4，足以容纳 4^16 个元素
+// maxLevel is the maximum number of levels of the skiplist, every level holds about 1/4 of the nodes of the level below, enough for 4^16 elements
+const maxLevel = 16
+
+// LockFreePriorityQueueOf 是一个泛型无锁优先队列，T 为队列中元素的类型。
+// 它基于无锁跳表：节点按 (优先级, 推入序号) 升序排列，PopMin 逻辑删除第一个还没有被删除的节点，因此优先级相同的元素按推入顺序弹出。
+// LockFreePriorityQueueOf is a generic lock-free priority queue, T is the type of the elements in the queue.
+// It is based on a lock-free skiplist: nodes are sorted by (priority, push sequence) in ascending order, PopMin logically deletes the first node that has not been deleted yet, so elements with the same priority are popped in push order.
+type LockFreePriorityQueueOf[T any] struct {
+	// length 是优先队列的长度
+	// length is the length of the priority queue
+	length int64
+
+	// _ 用于把每次推入和删除都会修改的长度与序号计数器分隔到不同的缓存行
+	// _ separates the length, modified by every push and delete, from the sequence counter onto different cache lines
+	_ shd.CacheLinePad
+
+	// seq 是推入序号计数器
+	// seq is the push sequence counter
+	seq uint64
+
+	// _ 用于把序号计数器与之后只读的字段分隔到不同的缓存行
+	// _ separates the sequence counter from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// head 是跳表的头节点，它的键小于所有节点，并且永远不会被删除
+	// head is the head node of the skiplist, its key is less than every node and it is never deleted
+	head *nodeOf[T]
+
+	// pool 是一个节点池，用于存储和获取节点
+	// pool is a node pool used to store and retrieve nodes
+	pool *nodePoolOf[T]
+
+	// reclaimer 是节点回收器，仅在使用节点池时启用，保证节点在没有 goroutine 引用后才会被复用
+	// reclaimer is the node reclaimer, only enabled with a node pool, it ensures nodes are reused only after no goroutine references them
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// NewOf 函数用于创建一个新的泛型 LockFreePriorityQueueOf 优先队列，可以通过选项修改优先队列的行为
+// The NewOf function is used to create a new generic LockFreePriorityQueueOf priority queue, the behavior of the priority queue can be modified with options
+func NewOf[T any](opts ...Option) *LockFreePriorityQueueOf[T] {
+	// 调用 newLFPQ 函数创建一个新的 LockFreePriorityQueueOf 优先队列，是否使用节点池由选项决定
+	// Call the newLFPQ function to create a new LockFreePriorityQueueOf priority queue, whether a node pool is used is decided by the options
+	return newLFPQ[T](opts)
+}
+
+// NewWithPoolOf 函数用于创建一个新的泛型 LockFreePriorityQueueOf 优先队列，该优先队列使用一个节点池，可以通过选项修改优先队列的行为。
+// 它是 NewOf 加上 WithPool 选项的简写
+// The NewWithPoolOf function is used to create a new generic LockFreePriorityQueueOf priority queue, this priority queue uses a node pool, the behavior of the priority queue can be modified with options.
+// It is a shortcut for NewOf with the WithPool option
+func NewWithPoolOf[T any](opts ...Option) *LockFreePriorityQueueOf[T] {
+	return newLFPQ[T](append([]Option{WithPool()}, opts...))
+}
+
+// newLFPQ 函数用于创建一个新的 LockFreePriorityQueueOf 优先队列，参数为所有的选项
+// The newLFPQ function is used to create a new LockFreePriorityQueueOf priority queue, the parameter is all the options
+func newLFPQ[T any](opts []Option) *LockFreePriorityQueueOf[T] {
+	// 应用所有的选项，如果启用了节点池，那么创建节点池
+	// Apply all the options, if the node pool is enabled, then create the node pool
+	conf := newConfig(opts)
+	var pool *nodePoolOf[T]
+	if conf.pool {
+		pool = newNodePoolOf[T]()
+	}
+
+	// 创建头节点，每一层都指向这一层的末尾
+	// Create the head node, every level points to the end of the level
+	head := &nodeOf[T]{levels: make([]levelOf[T], maxLevel)}
+
+	// 创建一个新的 LockFreePriorityQueueOf 优先队列
+	// Create a new LockFreePriorityQueueOf priority queue
+	q := &LockFreePriorityQueueOf[T]{head: head, pool: pool}
+
+	// 如果使用节点池，那么创建节点回收器，宽限期结束后节点会被放回节点池
+	// If a node pool is used, then create the node reclaimer, nodes are put back into the node pool once their grace period is over
+	if pool != nil {
+		q.reclaimer = shd.NewReclaimer(
+			func(p unsafe.Pointer) *unsafe.Pointer { return &(*nodeOf[T])(p).link },
+			func(p unsafe.Pointer) { pool.put((*nodeOf[T])(p)) },
+		)
+	}
+
+	// 设置退避策略，如果启用了统计，那么创建统计计数器，节点池也使用同一组计数器
+	// Set the backoff strategy, if statistics are enabled, then create the statistics counters, the node pool uses the same counters
+	q.backoff = conf.backoff
+	if conf.stats {
+		q.stats = shd.NewCounters()
+		if pool != nil {
+			pool.stats = q.stats
+		}
+	}
+
+	// 返回新创建的优先队列
+	// Return the newly created priority queue
+	return q
+}
+
+// randomLevel 函数用于根据推入序号计算节点的层数，第 k 层出现的概率为 1/4^(k-1)。序号经过 splitmix64 混合，不需要共享的随机数生成器
+// The randomLevel function is used to compute the number of levels of a node from its push sequence, level k appears with probability 1/4^(k-1). The sequence is mixed with splitmix64, so no shared random generator is needed
+func randomLevel(seq uint64) int {
+	z := seq + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+
+	level := bits.TrailingZeros64(z)/2 + 1
+	if level > maxLevel {
+		level = maxLevel
+	}
+	return level
+}
+
+// newNode 方法用于创建一个有 level 层的新节点，如果使用节点池，那么从节点池中获取
+// The newNode method is used to create a new node with the given number of levels, the node is taken from the node pool if one is used
+func (q *LockFreePriorityQueueOf[T]) newNode(value T, priority int64, seq uint64, level int) *nodeOf[T] {
+	var node *nodeOf[T]
+	if q.pool != nil {
+		node = q.pool.get()
+		node.levels = node.levels[:level]
+	} else {
+		node = &nodeOf[T]{levels: make([]levelOf[T], level)}
+	}
+	node.value = value
+	node.priority = priority
+	node.seq = seq
+	return node
+}
+
+// find 方法用于在每一层找到键小于 (priority, seq) 的最后一个节点，写入 preds，并把它的下一个节点写入 succs。
+// 查找过程中遇到的已经被逻辑删除的节点会被物理地移除。
+// The find method is used to find the last node whose key is less than (priority, seq) at every level, writing it into preds and its next node into succs.
+// Logically deleted nodes met during the search are physically removed.
+func (q *LockFreePriorityQueueOf[T]) find(priority int64, seq uint64, preds, succs []*nodeOf[T]) {
+retry:
+	pred := q.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		// 如果前驱节点在这一层已经被删除，那么从头开始重新查找
+		// If the predecessor has been deleted at this level, then restart the search from the head
+		predNext, marked := pred.loadNext(level)
+		if marked {
+			goto retry
+		}
+
+		for curr := predNext; curr != nil; curr = predNext {
+			currNext, marked := curr.loadNext(level)
+
+			// 当前节点已经被删除，把它从这一层移除，失败说明前驱节点发生了变化，从头开始重新查找
+			// The current node has been deleted, remove it from this level, a failure means the predecessor has changed, restart the search from the head
+			if marked {
+				if !pred.casNext(level, curr, currNext) {
+					goto retry
+				}
+				predNext = currNext
+				continue
+			}
+
+			// 当前节点的键不小于目标键，停止在这一层前进
+			// The key of the current node is not less than the target key, stop moving forward at this level
+			if !curr.less(priority, seq) {
+				break
+			}
+			pred, predNext = curr, currNext
+		}
+
+		if preds != nil {
+			preds[level] = pred
+			succs[level] = predNext
+		}
+	}
+}
+
+// finish 方法用于在推入或者弹出结束时调用，两者都结束之后，把节点从所有层中移除并退休
+// The finish method is called when the push or the pop finishes, once both have finished the node is removed from every level and retired
+func (q *LockFreePriorityQueueOf[T]) finish(node *nodeOf[T]) {
+	if q.pool == nil {
+		return
+	}
+	if atomic.AddInt32(&node.done, 1) == 2 {
+		q.find(node.priority, node.seq, nil, nil)
+		q.reclaimer.Retire(unsafe.Pointer(node))
+	}
+}
+
+// Push 方法用于向优先队列中推入一个元素，priority 越小越先弹出，优先级相同的元素按推入顺序弹出
+// The Push method is used to push an element into the priority queue, smaller priorities are popped first, elements with the same priority are popped in push order
+func (q *LockFreePriorityQueueOf[T]) Push(value T, priority int64) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	seq := atomic.AddUint64(&q.seq, 1)
+	level := randomLevel(seq)
+	node := q.newNode(value, priority, seq, level)
+
+	var preds, succs [maxLevel]*nodeOf[T]
+	attempt := 0
+
+	// 在最底层插入节点，插入成功后节点就可以被弹出
+	// Insert the node at the bottom level, the node can be popped once the insertion succeeds
+	for {
+		q.find(priority, seq, preds[:], succs[:])
+		for i := 0; i < level; i++ {
+			node.storeNext(i, succs[i])
+		}
+		if preds[0].casNext(0, succs[0], node) {
+			break
+		}
+
+		// 前驱节点在最底层已经改变，记录一次 CAS 失败，退避之后重新查找
+		// The predecessor has changed at the bottom level, count a CAS failure, back off and search again
+		q.stats.Inc(shd.CounterCASFailures)
+		q.backoff.Wait(&attempt)
+	}
+
+	// 增加优先队列的长度
+	// Increase the length of the priority queue
+	atomic.AddInt64(&q.length, 1)
+	q.stats.Inc(shd.CounterPushes)
+
+	// 自底向上把节点链接到上面的层，上面的层只用于加速查找。如果节点已经被弹出，停止链接
+	// Link the node into the upper levels from the bottom up, the upper levels only speed up searches. Stop linking if the node has already been popped
+	for i := 1; i < level; i++ {
+		for {
+			next, marked := node.loadNext(i)
+			if marked {
+				q.finish(node)
+				return
+			}
+			if next != succs[i] && !node.casNext(i, next, succs[i]) {
+				continue
+			}
+			if preds[i].casNext(i, succs[i], node) {
+				break
+			}
+			q.stats.Inc(shd.CounterCASFailures)
+			q.backoff.Wait(&attempt)
+			q.find(priority, seq, preds[:], succs[:])
+		}
+	}
+
+	q.finish(node)
+}
+
+// PopMin 方法用于从优先队列中移除并返回优先级最小的元素，如果优先队列为空，返回 T 的零值和 false
+// The PopMin method is used to remove and return the element with the smallest priority from the priority queue, returns the zero value of T and false if the priority queue is empty
+func (q *LockFreePriorityQueueOf[T]) PopMin() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+	for {
+		// 在最底层找到第一个还没有被弹出的节点
+		// Find the first node that has not been popped at the bottom level
+		curr, _ := q.head.loadNext(0)
+		for curr != nil && atomic.LoadInt32(&curr.popped) != 0 {
+			curr, _ = curr.loadNext(0)
+		}
+
+		// 优先队列为空，返回 T 的零值和 false
+		// The priority queue is empty, return the zero value of T and false
+		if curr == nil {
+			q.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+
+		// 通过 CAS 操作占用节点，失败说明它已经被其他 goroutine 弹出，继续查找
+		// Claim the node with a CAS operation, a failure means it has been popped by another goroutine, keep searching
+		if !atomic.CompareAndSwapInt32(&curr.popped, 0, 1) {
+			q.stats.Inc(shd.CounterCASFailures)
+			q.backoff.Wait(&attempt)
+			continue
+		}
+
+		// 只有占用了节点的 goroutine 会自顶向下标记它的每一层
+		// Only the goroutine that claimed the node marks every level of it from the top down
+		for i := len(curr.levels) - 1; i >= 0; i-- {
+			curr.mark(i)
+		}
+
+		// 减少优先队列的长度，读取节点的值，然后物理地移除节点
+		// Decrease the length of the priority queue, read the value of the node, then physically remove the node
+		atomic.AddInt64(&q.length, -1)
+		q.stats.Inc(shd.CounterPops)
+		value := curr.value
+		q.find(curr.priority, curr.seq, nil, nil)
+		q.finish(curr)
+		return value, true
+	}
+}
+
+// Length 方法用于获取优先队列的长度
+// The Length method is used to get the length of the priority queue
+func (q *LockFreePriorityQueueOf[T]) Length() int64 {
+	return atomic.LoadInt64(&q.length)
+}
+
+// IsEmpty 方法用于判断优先队列是否为空
+// The IsEmpty method is used to determine whether the priority queue is empty
+func (q *LockFreePriorityQueueOf[T]) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+// Reset 方法用于重置优先队列，它会弹出并丢弃所有元素，可以与 Push 和 PopMin 并发调用，并发推入的元素可能不会被移除
+// The Reset method is used to reset the priority queue, it pops and discards all the elements, it can be called concurrently with Push and PopMin, elements pushed concurrently may not be removed
+func (q *LockFreePriorityQueueOf[T]) Reset() {
+	for {
+		if _, ok := q.PopMin(); !ok {
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回优先队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the priority queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreePriorityQueueOf[T]) Stats() Stats {
+	return q.stats.Snapshot()
+}
@@ -0,0 +1,345 @@
+package priorityqueue
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreePriorityQueue_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 100000
+
+	// Create a new priority queue
+	q := New()
+
+	// Test pushing elements in random priority order
+	priorities := rand.Perm(count)
+	for _, p := range priorities {
+		q.Push(p, int64(p))
+	}
+	assert.Equal(t, int64(count), q.Length(), "Incorrect queue length. Expected %d, got %d", count, q.Length())
+
+	// Verify that the elements are popped in ascending priority order
+	for i := 0; i < count; i++ {
+		v := q.PopMin()
+		assert.Equal(t, i, v, "Incorrect value in the queue. Expected %d, got %d", i, v)
+	}
+
+	// Verify the queue length
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreePriorityQueue_SamePriority(t *testing.T) {
+	q := New()
+
+	// Test that elements with the same priority are popped in push order
+	for i := 0; i < 100; i++ {
+		q.Push(i, int64(i%2))
+	}
+	for _, priority := range []int{0, 1} {
+		for i := priority; i < 100; i += 2 {
+			v := q.PopMin()
+			assert.Equal(t, i, v, "Incorrect value in the queue. Expected %d, got %d", i, v)
+		}
+	}
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreePriorityQueue_NegativePriority(t *testing.T) {
+	q := New()
+
+	q.Push("b", 0)
+	q.Push("c", 10)
+	q.Push("a", -10)
+
+	assert.Equal(t, "a", q.PopMin(), "Incorrect value in the queue. Expected a")
+	assert.Equal(t, "b", q.PopMin(), "Incorrect value in the queue. Expected b")
+	assert.Equal(t, "c", q.PopMin(), "Incorrect value in the queue. Expected c")
+}
+
+func TestLockFreePriorityQueue_Length(t *testing.T) {
+	q := New()
+
+	// Test the length of an empty queue
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+
+	// Test the length of a non-empty queue
+	for i := 0; i < 100; i++ {
+		q.Push(i, int64(100-i))
+		assert.Equal(t, int64(i+1), q.Length(), "Incorrect queue length. Expected %d, got %d", i+1, q.Length())
+	}
+
+	// Test the length of a queue after popping elements
+	for i := 0; i < 100; i++ {
+		q.PopMin()
+		assert.Equal(t, int64(100-i-1), q.Length(), "Incorrect queue length. Expected %d, got %d", 100-i-1, q.Length())
+	}
+}
+
+func TestLockFreePriorityQueue_EmptyPop(t *testing.T) {
+	q := New()
+
+	// Test popping elements from an empty queue
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, q.PopMin(), "Expected nil value from an empty queue")
+	}
+
+	// Test that nil values are ignored
+	q.Push(nil, 0)
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreePriorityQueue_Parallel(t *testing.T) {
+	q := New()
+
+	// Test pushing elements at the same time
+	wg := sync.WaitGroup{}
+	for i := 0; i < 1000; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q.Push(i, int64(i))
+		}(i)
+	}
+	wg.Wait()
+
+	// Verify that the elements are popped in ascending priority order once the pushes are done
+	for i := 0; i < 1000; i++ {
+		v := q.PopMin()
+		assert.Equal(t, i, v, "Incorrect value in the queue. Expected %d, got %d", i, v)
+	}
+}
+
+func TestLockFreePriorityQueue_WithPool_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 100000
+
+	// Create a new priority queue
+	q := NewWithPool()
+
+	// Test pushing and popping elements in several rounds so nodes are recycled
+	for round := 0; round < 3; round++ {
+		for _, p := range rand.Perm(count) {
+			q.Push(p, int64(p))
+		}
+		for i := 0; i < count; i++ {
+			v := q.PopMin()
+			assert.Equal(t, i, v, "Incorrect value in the queue. Expected %d, got %d", i, v)
+		}
+	}
+
+	// Verify the queue length
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreePriorityQueue_WithPool_StressNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 8, 8, 20000
+	total := producers * perProducer
+
+	q := NewWithPool()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values with random priorities
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(p)))
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer+i, r.Int63n(1000))
+			}
+		}(p)
+	}
+
+	// Start the consumers, they pop until every value has been seen
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v := q.PopMin(); v != nil {
+					atomic.AddInt32(&seen[v.(int)], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreePriorityQueueOf_Standard(t *testing.T) {
+	q := NewOf[int]()
+
+	// Test that zero values are stored and not mistaken for an empty queue
+	q.Push(0, 5)
+	q.Push(1, 3)
+
+	v, ok := q.PopMin()
+	assert.True(t, ok, "Failed to pop value")
+	assert.Equal(t, 1, v, "Incorrect value in the queue. Expected 1, got %d", v)
+
+	v, ok = q.PopMin()
+	assert.True(t, ok, "Failed to pop zero value")
+	assert.Equal(t, 0, v, "Incorrect value in the queue. Expected 0, got %d", v)
+
+	// Test popping elements from an empty queue
+	v, ok = q.PopMin()
+	assert.False(t, ok, "Popped value from an empty queue")
+	assert.Equal(t, 0, v, "Expected zero value from an empty queue")
+}
+
+func TestLockFreePriorityQueueOf_WithPool_SortedPerConsumer(t *testing.T) {
+	q := NewWithPoolOf[int]()
+
+	// Push every value before popping, so each consumer must see its values in ascending order
+	for _, p := range rand.Perm(50000) {
+		q.Push(p, int64(p))
+	}
+
+	wg := sync.WaitGroup{}
+	results := make([][]int, 4)
+	for c := range results {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for {
+				v, ok := q.PopMin()
+				if !ok {
+					return
+				}
+				results[c] = append(results[c], v)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	all := 0
+	for _, r := range results {
+		assert.True(t, sort.IntsAreSorted(r), "Values popped by a consumer should be in ascending order")
+		all += len(r)
+	}
+	assert.Equal(t, 50000, all, "Incorrect number of popped values. Expected 50000, got %d", all)
+}
+
+func TestLockFreePriorityQueue_ResetParallel(t *testing.T) {
+	q := NewWithPool()
+
+	wg := sync.WaitGroup{}
+
+	// Push, pop and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				switch {
+				case i == 0 && j%100 == 0:
+					q.Reset()
+				case i%2 == 0:
+					q.PopMin()
+				default:
+					q.Push(j, int64(j%10))
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	q.Reset()
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+	assert.Nil(t, q.PopMin(), "PopMin on a reset queue should return nil")
+}
+
+func TestLockFreePriorityQueue_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	q := New()
+	q.Push(1, 1)
+	q.PopMin()
+	assert.Equal(t, Stats{}, q.Stats(), "Statistics should be disabled by default")
+
+	// Count the operations of a priority queue with a node pool
+	q = NewWithPool(WithStats())
+	for i := 0; i < 100; i++ {
+		q.Push(i, int64(100-i))
+	}
+	for i := 0; i < 101; i++ {
+		q.PopMin()
+	}
+
+	s := q.Stats()
+	assert.Equal(t, uint64(100), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(100), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+	assert.Equal(t, uint64(100), s.PoolGets, "Incorrect number of pool gets")
+	assert.LessOrEqual(t, s.PoolMisses, s.PoolGets, "Pool misses should not exceed pool gets")
+}
+
+func TestLockFreePriorityQueue_WithPoolAndBackoff(t *testing.T) {
+	var calls atomic.Int64
+	q := New(WithPool(), WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5000; j++ {
+				q.Push(j, int64(j*8+i))
+				runtime.Gosched()
+				for q.PopMin() == nil {
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// New with WithPool takes the nodes from the node pool like NewWithPool
+	s := q.Stats()
+	assert.True(t, q.IsEmpty(), "Priority queue should be empty")
+	assert.Equal(t, uint64(40000), s.PoolGets, "The nodes should come from the node pool")
+	assert.LessOrEqual(t, uint64(calls.Load()), s.CASFailures, "Only CAS failures should back off")
+}
+
+func TestLockFreePriorityQueueOf_WithPool_ZeroAllocs(t *testing.T) {
+	q := NewWithPoolOf[int]()
+	for i := 0; i < 1000; i++ {
+		q.Push(i, int64(i%7))
+	}
+
+	// Deletions are marked inside the links of the node, so once the pool is warm Push and PopMin do not allocate
+	i := 0
+	allocs := testing.AllocsPerRun(10000, func() {
+		q.Push(i, int64(i%7))
+		q.PopMin()
+		i++
+	})
+	assert.Equal(t, float64(0), allocs, "Push and PopMin should not allocate, got %v allocs per run", allocs)
+}
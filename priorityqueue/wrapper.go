@@ -0,0 +1,74 @@
+package priorityqueue
+
+// LockFreePriorityQueue 是一个无锁优先队列结构体，元素的类型为 interface{}
+// LockFreePriorityQueue is a lock-free priority queue struct, the type of the elements is interface{}
+type LockFreePriorityQueue LockFreePriorityQueueOf[interface{}]
+
+// New 函数用于创建一个新的 LockFreePriorityQueue 优先队列，可以通过选项修改优先队列的行为
+// The New function is used to create a new LockFreePriorityQueue priority queue, the behavior of the priority queue can be modified with options
+func New(opts ...Option) *LockFreePriorityQueue {
+	// 调用 newLFPQ 函数创建一个新的 LockFreePriorityQueue 优先队列，是否使用节点池由选项决定
+	// Call the newLFPQ function to create a new LockFreePriorityQueue priority queue, whether a node pool is used is decided by the options
+	return (*LockFreePriorityQueue)(newLFPQ[interface{}](opts))
+}
+
+// NewWithPool 函数用于创建一个新的 LockFreePriorityQueue 优先队列，该优先队列使用一个节点池，可以通过选项修改优先队列的行为。
+// 它是 New 加上 WithPool 选项的简写
+// The NewWithPool function is used to create a new LockFreePriorityQueue priority queue, this priority queue uses a node pool, the behavior of the priority queue can be modified with options.
+// It is a shortcut for New with the WithPool option
+func NewWithPool(opts ...Option) *LockFreePriorityQueue {
+	return (*LockFreePriorityQueue)(NewWithPoolOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreePriorityQueue 转换为底层的 LockFreePriorityQueueOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreePriorityQueue to the underlying LockFreePriorityQueueOf[interface{}] without any extra cost
+func (q *LockFreePriorityQueue) of() *LockFreePriorityQueueOf[interface{}] {
+	return (*LockFreePriorityQueueOf[interface{}])(q)
+}
+
+// Push 方法用于将一个值以 priority 优先级添加到 LockFreePriorityQueue 优先队列中
+// The Push method is used to add a value with the given priority to the LockFreePriorityQueue priority queue
+func (q *LockFreePriorityQueue) Push(value interface{}, priority int64) {
+	// 检查值是否为空, 如果为空则直接返回
+	// Check if the value is nil, if it is, return directly
+	if value == nil {
+		return
+	}
+
+	// 将值添加到底层的泛型优先队列中
+	// Add the value to the underlying generic priority queue
+	q.of().Push(value, priority)
+}
+
+// PopMin 方法用于从 LockFreePriorityQueue 优先队列中移除并返回优先级最小的值，如果优先队列为空，返回 nil
+// The PopMin method is used to remove and return the value with the smallest priority from the LockFreePriorityQueue priority queue, returns nil if the priority queue is empty
+func (q *LockFreePriorityQueue) PopMin() interface{} {
+	// 从底层的泛型优先队列中弹出一个值，优先队列为空时该值为 nil
+	// Pop a value from the underlying generic priority queue, the value is nil when the priority queue is empty
+	value, _ := q.of().PopMin()
+	return value
+}
+
+// Length 方法用于获取 LockFreePriorityQueue 优先队列的长度
+// The Length method is used to get the length of the LockFreePriorityQueue priority queue
+func (q *LockFreePriorityQueue) Length() int64 {
+	return q.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreePriorityQueue 优先队列是否为空
+// The IsEmpty method is used to determine whether the LockFreePriorityQueue priority queue is empty
+func (q *LockFreePriorityQueue) IsEmpty() bool {
+	return q.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreePriorityQueue 优先队列，可以与 Push 和 PopMin 并发调用
+// The Reset method is used to reset the LockFreePriorityQueue priority queue, it can be called concurrently with Push and PopMin
+func (q *LockFreePriorityQueue) Reset() {
+	q.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreePriorityQueue 优先队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreePriorityQueue priority queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreePriorityQueue) Stats() Stats {
+	return q.of().Stats()
+}
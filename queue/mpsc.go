@@ -0,0 +1,145 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeMPSCQueueOf 是一个泛型多生产者单消费者无锁队列结构体，T 为队列中元素的类型。
+// 它基于 Vyukov 的 MPSC 队列：生产者通过一次原子交换占用尾节点，再把新节点链接到原来的尾节点之后，Push 不需要 CAS 循环，是无等待的。
+// 头节点只由唯一的消费者修改，因此 Pop 也不需要 CAS 操作。同一时刻最多只能有一个 goroutine 调用 Pop、TryPop 和 Reset
+// LockFreeMPSCQueueOf is a generic multi-producer single-consumer lock-free queue struct, T is the type of the elements in the queue.
+// It is based on the MPSC queue of Vyukov: a producer claims the tail node with a single atomic swap and then links the new node after the previous tail node, so Push needs no CAS loop and is wait-free.
+// The head node is only modified by the single consumer, so Pop needs no CAS operation either. At most one goroutine may call Pop, TryPop and Reset at the same time
+type LockFreeMPSCQueueOf[T any] struct {
+	// length 是队列的长度
+	// length is the length of the queue
+	length int64
+
+	// _ 用于把长度与头尾指针分隔到不同的缓存行
+	// _ separates the length from the head and tail pointers onto different cache lines
+	_ shd.CacheLinePad
+
+	// head 是指向队列头部哨兵节点的指针，只由消费者访问
+	// head is a pointer to the sentinel node at the head of the queue, it is only accessed by the consumer
+	head *shd.NodeOf[T]
+
+	// _ 用于把消费者独占的 head 与生产者争用的 tail 分隔到不同的缓存行
+	// _ separates head, owned by the consumer, from tail, contended by the producers, onto different cache lines
+	_ shd.CacheLinePad
+
+	// tail 是指向队列尾部的指针，生产者通过原子交换修改它
+	// tail is a pointer to the tail of the queue, producers modify it with an atomic swap
+	tail unsafe.Pointer
+
+	// _ 用于把 tail 与之后只读的字段分隔到不同的缓存行
+	// _ separates tail from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// acceptNil 表示 Push 是否接受 nil 值，只在元素类型为 interface{} 的 LockFreeMPSCQueue 中使用
+	// acceptNil indicates whether Push accepts nil values, it is only used by LockFreeMPSCQueue whose element type is interface{}
+	acceptNil bool
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+}
+
+// NewMPSCOf 函数用于创建一个新的泛型 LockFreeMPSCQueueOf 队列，可以通过选项修改队列的行为
+// The NewMPSCOf function is used to create a new generic LockFreeMPSCQueueOf queue, the behavior of the queue can be modified with options
+func NewMPSCOf[T any](opts ...Option) *LockFreeMPSCQueueOf[T] {
+	// 创建一个值为 T 的零值的哨兵节点，队列的头节点和尾节点都指向它
+	// Create a sentinel node whose value is the zero value of T, both the head node and the tail node of the queue point to it
+	var zero T
+	stub := shd.NewNodeOf(zero)
+	q := &LockFreeMPSCQueueOf[T]{
+		head: stub,
+		tail: unsafe.Pointer(stub),
+	}
+
+	// 应用所有的选项
+	// Apply all the options
+	conf := newConfig(opts)
+	q.acceptNil = conf.acceptNil
+	if conf.stats {
+		q.stats = shd.NewCounters()
+	}
+	return q
+}
+
+// Push 方法用于将一个值添加到队列的末尾，可以由任意多个生产者并发调用
+// The Push method is used to add a value to the end of the queue, it can be called concurrently by any number of producers
+func (q *LockFreeMPSCQueueOf[T]) Push(value T) {
+	node := shd.NewNodeOf(value)
+
+	// 增加队列的长度，必须在发布节点之前，否则长度可能短暂为负数
+	// Increase the length of the queue, this must happen before the node is published, otherwise the length could briefly be negative
+	atomic.AddInt64(&q.length, 1)
+
+	// 通过原子交换把新节点设置为尾节点，然后把它链接到原来的尾节点之后。两步之间消费者看不到新节点以及之后推入的节点
+	// Make the new node the tail node with an atomic swap, then link it after the previous tail node. Between the two steps the consumer cannot see the new node or any node pushed after it
+	prev := (*shd.NodeOf[T])(atomic.SwapPointer(&q.tail, unsafe.Pointer(node)))
+	atomic.StorePointer(&prev.Next, unsafe.Pointer(node))
+	q.stats.Inc(shd.CounterPushes)
+}
+
+// Pop 方法用于从队列的头部移除并返回一个值，如果队列为空，返回 T 的零值和 false。只能由唯一的消费者调用。
+// 如果某个生产者已经占用了尾节点但还没有完成链接，它之后的值暂时不可见，此时 Pop 也会返回 false
+// The Pop method is used to remove and return a value from the head of the queue, returns the zero value of T and false if the queue is empty. It must only be called by the single consumer.
+// If a producer has claimed the tail node but has not finished linking it yet, the values after it are not visible for the moment and Pop returns false as well
+func (q *LockFreeMPSCQueueOf[T]) Pop() (T, bool) {
+	var zero T
+
+	// 加载哨兵节点的下一个节点，没有下一个节点说明队列为空
+	// Load the next node of the sentinel node, no next node means the queue is empty
+	next := shd.LoadNodeOf[T](&q.head.Next)
+	if next == nil {
+		q.stats.Inc(shd.CounterEmptyPops)
+		return zero, false
+	}
+
+	// 读取值并清空节点，然后把它设置为新的哨兵节点，原来的哨兵节点交给 GC 回收
+	// Read the value and clear the node, then make it the new sentinel node, the old sentinel node is left to the GC
+	value := next.Value
+	next.Value = zero
+	q.head = next
+
+	// 减少队列的长度
+	// Decrease the length of the queue
+	atomic.AddInt64(&q.length, -1)
+	q.stats.Inc(shd.CounterPops)
+
+	// 返回值和 true
+	// Return the value and true
+	return value, true
+}
+
+// Length 方法用于获取队列的长度
+// The Length method is used to get the length of the queue
+func (q *LockFreeMPSCQueueOf[T]) Length() int64 {
+	return atomic.LoadInt64(&q.length)
+}
+
+// IsEmpty 方法用于判断队列是否为空
+// The IsEmpty method is used to determine whether the queue is empty
+func (q *LockFreeMPSCQueueOf[T]) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+// Reset 方法用于重置队列，它会弹出并丢弃队列中所有可见的值。只能由唯一的消费者调用，可以与 Push 并发调用
+// The Reset method is used to reset the queue, it pops and discards all the visible values in the queue. It must only be called by the single consumer, and can be called concurrently with Push
+func (q *LockFreeMPSCQueueOf[T]) Reset() {
+	for {
+		if _, ok := q.Pop(); !ok {
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeMPSCQueueOf[T]) Stats() Stats {
+	return q.stats.Snapshot()
+}
@@ -0,0 +1,104 @@
+package queue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeMPSCQueue_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 100000
+
+	// The MPSC queue is used through the same interface as the MPMC one
+	var q Queue = NewMPSC()
+
+	// Test enqueueing elements into the queue
+	for i := 0; i < count; i++ {
+		q.Push(i)
+	}
+	assert.Equal(t, int64(count), q.Length(), "Incorrect queue length. Expected %d, got %d", count, q.Length())
+
+	// Verify the elements in the queue
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, q.Pop(), "Incorrect value in the queue. Expected %d", i)
+	}
+	assert.Nil(t, q.Pop(), "Popped value from an empty queue")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreeMPSCQueue_NilAndReset(t *testing.T) {
+	q := NewMPSC()
+
+	// Test that nil values are dropped by default
+	q.Push(nil)
+	assert.True(t, q.IsEmpty(), "Nil value should be dropped")
+
+	// Test that nil values are kept with WithAcceptNil
+	q = NewMPSC(WithAcceptNil())
+	q.Push(nil)
+	v, ok := q.TryPop()
+	assert.True(t, ok, "Failed to pop the nil value")
+	assert.Nil(t, v, "Incorrect value popped")
+
+	// Test resetting the queue
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	q.Reset()
+	assert.True(t, q.IsEmpty(), "Queue should be empty after reset")
+	_, ok = q.TryPop()
+	assert.False(t, ok, "Popped value from a reset queue")
+}
+
+func TestLockFreeMPSCQueue_ManyProducers(t *testing.T) {
+	producers, perProducer := 8, 20000
+	q := NewMPSCOf[int]()
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values in order
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	// The single consumer checks that no value is lost or duplicated and that each producer's values keep their order
+	next := make([]int, producers)
+	for popped := 0; popped < producers*perProducer; {
+		v, ok := q.Pop()
+		if !ok {
+			runtime.Gosched()
+			continue
+		}
+		p := v / perProducer
+		assert.Equal(t, p*perProducer+next[p], v, "Values of producer %d out of order", p)
+		next[p]++
+		popped++
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeMPSCQueue_Stats(t *testing.T) {
+	q := NewMPSC(WithStats())
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 11; i++ {
+		q.Pop()
+	}
+
+	s := q.Stats()
+	assert.Equal(t, uint64(10), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(10), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+}
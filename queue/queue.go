@@ -1,76 +1,145 @@
 package queue
 
 import (
+	"context"
 	"sync/atomic"
 	"unsafe"
 
 	shd "github.com/shengyanli1982/lockfree/internal/shared"
 )
 
-// LockFreeQueue 是一个无锁队列结构体
-// LockFreeQueue is a lock-free queue struct
-type LockFreeQueue struct {
+// LockFreeQueueOf 是一个泛型无锁队列结构体，T 为队列中元素的类型
+// LockFreeQueueOf is a generic lock-free queue struct, T is the type of the elements in the queue
+type LockFreeQueueOf[T any] struct {
 	// length 是队列的长度
 	// length is the length of the queue
 	length int64
 
+	// _ 用于把长度与头尾指针分隔到不同的缓存行
+	// _ separates the length from the head and tail pointers onto different cache lines
+	_ shd.CacheLinePad
+
 	// head 是指向队列头部的指针
 	// head is a pointer to the head of the queue
 	head unsafe.Pointer
 
+	// _ 用于把消费者修改的 head 和生产者修改的 tail 分隔到不同的缓存行，避免伪共享
+	// _ separates head, modified by consumers, from tail, modified by producers, onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
 	// tail 是指向队列尾部的指针
 	// tail is a pointer to the tail of the queue
 	tail unsafe.Pointer
 
+	// _ 用于把 tail 与之后只读的字段分隔到不同的缓存行
+	// _ separates tail from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
 	// pool 是一个节点池，用于存储和获取节点
 	// pool is a node pool used to store and retrieve nodes
-	pool *shd.NodePool
+	pool *shd.NodePoolOf[T]
+
+	// reclaimer 是节点回收器，仅在使用节点池时启用，保证节点在没有 goroutine 引用后才会被复用
+	// reclaimer is the node reclaimer, only enabled with a node pool, it ensures nodes are reused only after no goroutine references them
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+
+	// notEmpty 用于挂起等待数据的 goroutine，在推入数据后唤醒它们
+	// notEmpty is used to park goroutines waiting for data, they are woken up after data is pushed
+	notEmpty shd.Waiter
+
+	// acceptNil 表示 Push 是否接受 nil 值，只在元素类型为 interface{} 的 LockFreeQueue 中使用
+	// acceptNil indicates whether Push accepts nil values, it is only used by LockFreeQueue whose element type is interface{}
+	acceptNil bool
 }
 
-// New 函数用于创建一个新的 LockFreeQueue 队列
-// The New function is used to create a new LockFreeQueue queue
-func New() *LockFreeQueue {
-	// 调用 newLFQ 函数创建一个新的 LockFreeQueue 队列，参数为 nil
-	// Call the newLFQ function to create a new LockFreeQueue queue, the parameter is nil
-	return newLFQ(nil)
+// NewOf 函数用于创建一个新的泛型 LockFreeQueueOf 队列，可以通过选项修改队列的行为
+// The NewOf function is used to create a new generic LockFreeQueueOf queue, the behavior of the queue can be modified with options
+func NewOf[T any](opts ...Option) *LockFreeQueueOf[T] {
+	// 调用 newLFQ 函数创建一个新的 LockFreeQueueOf 队列，是否使用节点池由选项决定
+	// Call the newLFQ function to create a new LockFreeQueueOf queue, whether a node pool is used is decided by the options
+	return newLFQ[T](opts)
 }
 
-// NewWithPool 函数用于创建一个新的 LockFreeQueue 队列，该队列使用一个节点池
-// The NewWithPool function is used to create a new LockFreeQueue queue, this queue uses a node pool
-func NewWithPool() *LockFreeQueue {
-	// 调用 newLFQ 函数创建一个新的 LockFreeQueue 队列，参数为一个新的节点池
-	// Call the newLFQ function to create a new LockFreeQueue queue, the parameter is a new node pool
-	return newLFQ(shd.NewNodePool())
+// NewWithPoolOf 函数用于创建一个新的泛型 LockFreeQueueOf 队列，该队列使用一个节点池，可以通过选项修改队列的行为。
+// 它是 NewOf 加上 WithPool 选项的简写
+// The NewWithPoolOf function is used to create a new generic LockFreeQueueOf queue, this queue uses a node pool, the behavior of the queue can be modified with options.
+// It is a shortcut for NewOf with the WithPool option
+func NewWithPoolOf[T any](opts ...Option) *LockFreeQueueOf[T] {
+	return newLFQ[T](append([]Option{WithPool()}, opts...))
 }
 
-// newLFQ 函数用于创建一个新的 LockFreeQueue 队列，参数为一个节点池
-// The newLFQ function is used to create a new LockFreeQueue queue, the parameter is a node pool
-func newLFQ(pool *shd.NodePool) *LockFreeQueue {
-	// 创建一个新的 Node 结构体实例，值为 nil
-	// Create a new Node struct instance, the value is nil
-	fristNode := shd.NewNode(nil)
+// newLFQ 函数用于创建一个新的 LockFreeQueueOf 队列，参数为所有的选项
+// The newLFQ function is used to create a new LockFreeQueueOf queue, the parameter is all the options
+func newLFQ[T any](opts []Option) *LockFreeQueueOf[T] {
+	// 应用所有的选项，设置了自定义的节点分配器或者启用了节点池时，创建节点池
+	// Apply all the options, create the node pool when a custom node allocator is set or the node pool is enabled
+	conf := newConfig(opts)
+	pool := newNodePool[T](conf)
+
+	// 创建哨兵节点，值为 T 的零值。如果使用节点池，那么从节点池中获取，之后它会像其他节点一样被退休并放回节点池
+	// Create the sentinel node, the value is the zero value of T. If a node pool is used, it is taken from the node pool, later it is retired and put back into the node pool like any other node
+	var fristNode *shd.NodeOf[T]
+	if pool != nil {
+		fristNode = pool.Get()
+	} else {
+		var zero T
+		fristNode = shd.NewNodeOf(zero)
+	}
 
-	// 返回一个新的 LockFreeQueue 队列，该队列的头节点和尾节点都是刚刚创建的节点，节点池为传入的参数
-	// Return a new LockFreeQueue queue, the head node and tail node of this queue are the nodes just created, and the node pool is the passed in parameter
-	return &LockFreeQueue{
+	// 创建一个新的 LockFreeQueueOf 队列，该队列的头节点和尾节点都是刚刚创建的节点，节点池为传入的参数
+	// Create a new LockFreeQueueOf queue, the head node and tail node of this queue are the nodes just created, and the node pool is the passed in parameter
+	q := &LockFreeQueueOf[T]{
 		pool: pool,
 		head: unsafe.Pointer(fristNode),
 		tail: unsafe.Pointer(fristNode),
 	}
+
+	// 如果使用节点池，那么创建节点回收器
+	// If a node pool is used, then create the node reclaimer
+	if pool != nil {
+		q.reclaimer = shd.NewNodeReclaimerOf(pool)
+	}
+
+	// 设置是否接受 nil 值以及退避策略
+	// Set whether nil values are accepted and the backoff strategy
+	q.acceptNil = conf.acceptNil
+	q.backoff = conf.backoff
+
+	// 如果启用了统计，那么创建统计计数器，节点池也使用同一组计数器
+	// If statistics are enabled, then create the statistics counters, the node pool uses the same counters
+	if conf.stats {
+		q.stats = shd.NewCounters()
+		if pool != nil {
+			pool.SetStats(q.stats)
+		}
+	}
+
+	// 返回新创建的队列
+	// Return the newly created queue
+	return q
 }
 
 // Push 方法用于将一个值添加到 LockFreeQueue 队列的末尾
 // The Push method is used to add a value to the end of the LockFreeQueue queue
-func (q *LockFreeQueue) Push(value interface{}) {
-	// 检查值是否为空, 如果为空则直接返回
-	// Check if the value is nil, if it is, return directly
-	if value == nil {
-		return
+func (q *LockFreeQueueOf[T]) Push(value T) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
 	}
 
 	// 创建一个新的 Node 结构体实例
 	// Create a new Node struct instance
-	var node *shd.Node
+	var node *shd.NodeOf[T]
 	if q.pool != nil {
 		// 如果节点池不为空，那么从节点池中获取一个节点
 		// If the node pool is not nil, then get a node from the node pool
@@ -79,23 +148,25 @@ func (q *LockFreeQueue) Push(value interface{}) {
 	} else {
 		// 如果节点池为空，那么创建一个新的节点
 		// If the node pool is nil, then create a new node
-		node = shd.NewNode(value)
+		node = shd.NewNodeOf(value)
 	}
 
+	attempt := 0
+
 	// 使用无限循环来尝试将新节点添加到队列的末尾
 	// Use an infinite loop to try to add the new node to the end of the queue
 	for {
 		// 加载队列的尾节点
 		// Load the tail node of the queue
-		tail := shd.LoadNode(&q.tail)
+		tail := shd.LoadNodeOf[T](&q.tail)
 
 		// 加载尾节点的下一个节点
 		// Load the next node of the tail node
-		next := shd.LoadNode(&tail.Next)
+		next := shd.LoadNodeOf[T](&tail.Next)
 
 		// 检查尾节点是否仍然是队列的尾节点
 		// Check if the tail node is still the tail node of the queue
-		if tail == shd.LoadNode(&q.tail) {
+		if tail == shd.LoadNodeOf[T](&q.tail) {
 			// 如果尾节点的下一个节点是 nil，说明尾节点是队列的最后一个节点
 			// If the next node of the tail node is nil, it means that the tail node is the last node of the queue
 			if next == nil {
@@ -109,11 +180,21 @@ func (q *LockFreeQueue) Push(value interface{}) {
 					// 并增加队列的长度
 					// And increase the length of the queue
 					atomic.AddInt64(&q.length, 1)
+					q.stats.Inc(shd.CounterPushes)
+
+					// 唤醒等待数据的 goroutine
+					// Wake up the goroutines waiting for data
+					q.notEmpty.Notify()
 
 					// 然后返回，结束函数
 					// Then return to end the function
 					return
 				}
+
+				// 其他生产者抢先链接了节点，记录一次 CAS 失败，退避之后重试
+				// Another producer linked its node first, count a CAS failure, back off and try again
+				q.stats.Inc(shd.CounterCASFailures)
+				q.backoff.Wait(&attempt)
 			} else {
 				// 如果尾节点的下一个节点不是 nil，说明尾节点不是队列的最后一个节点，那么将队列的尾节点设置为尾节点的下一个节点
 				// If the next node of the tail node is not nil, it means that the tail node is not the last node of the queue, then set the tail node of the queue to the next node of the tail node
@@ -123,34 +204,45 @@ func (q *LockFreeQueue) Push(value interface{}) {
 	}
 }
 
-// Pop 方法用于从 LockFreeQueue 队列的头部移除并返回一个值
-// The Pop method is used to remove and return a value from the head of the LockFreeQueue queue
-func (q *LockFreeQueue) Pop() interface{} {
+// Pop 方法用于从 LockFreeQueue 队列的头部移除并返回一个值，如果队列为空，返回 T 的零值和 false
+// The Pop method is used to remove and return a value from the head of the LockFreeQueue queue, returns the zero value of T and false if the queue is empty
+func (q *LockFreeQueueOf[T]) Pop() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+
 	// 使用无限循环来尝试从队列的头部移除一个值
 	// Use an infinite loop to try to remove a value from the head of the queue
 	for {
 		// 加载队列的头节点
 		// Load the head node of the queue
-		head := shd.LoadNode(&q.head)
+		head := shd.LoadNodeOf[T](&q.head)
 
 		// 加载队列的尾节点
 		// Load the tail node of the queue
-		tail := shd.LoadNode(&q.tail)
+		tail := shd.LoadNodeOf[T](&q.tail)
 
 		// 加载头节点的下一个节点
 		// Load the next node of the head node
-		next := shd.LoadNode(&head.Next)
+		next := shd.LoadNodeOf[T](&head.Next)
 
 		// 检查头节点是否仍然是队列的头节点
 		// Check if the head node is still the head node of the queue
-		if head == shd.LoadNode(&q.head) {
+		if head == shd.LoadNodeOf[T](&q.head) {
 			// 如果头节点等于尾节点
 			// If the head node is equal to the tail node
 			if head == tail {
-				// 如果头节点的下一个节点是 nil，说明队列是空的，返回 nil
-				// If the next node of the head node is nil, it means that the queue is empty, return nil
+				// 如果头节点的下一个节点是 nil，说明队列是空的，返回 T 的零值和 false
+				// If the next node of the head node is nil, it means that the queue is empty, return the zero value of T and false
 				if next == nil {
-					return nil
+					q.stats.Inc(shd.CounterEmptyPops)
+					var zero T
+					return zero, false
 				}
 
 				// 如果头节点的下一个节点不是 nil，说明尾节点落后了，尝试将队列的尾节点设置为头节点的下一个节点
@@ -167,29 +259,315 @@ func (q *LockFreeQueue) Pop() interface{} {
 					// 如果成功，那么减少队列的长度
 					// If successful, then decrease the length of the queue
 					atomic.AddInt64(&q.length, -1)
+					q.stats.Inc(shd.CounterPops)
 
-					// 如果节点池不为空，那么将头节点放回节点池
-					// If the node pool is not nil, then put the head node back into the node pool
+					// 如果节点池不为空，那么退休头节点，等到没有 goroutine 引用它之后再放回节点池。
+					// 否则头节点交给 GC 回收，不能重置它，因为其他 goroutine 可能仍在读取它
+					// If the node pool is not nil, then retire the head node, it is put back into the node pool once no goroutine references it.
+					// Otherwise the head node is left to the GC, it must not be reset because other goroutines may still be reading it
 					if q.pool != nil {
-						q.pool.Put(head)
-					} else {
-						// 如果节点池为空，那么重置头节点
-						// If the node pool is nil, then reset the head node
-						head.ResetAll()
+						q.reclaimer.Retire(unsafe.Pointer(head))
 					}
 
 					// 返回头节点的值，表示成功从队列中弹出一个元素
 					// Return the value of the head node, indicating that an element has been successfully popped from the queue
-					return result
+					return result, true
 				}
+
+				// 其他消费者抢先弹出了头节点，记录一次 CAS 失败，退避之后重试
+				// Another consumer popped the head node first, count a CAS failure, back off and try again
+				q.stats.Inc(shd.CounterCASFailures)
+				q.backoff.Wait(&attempt)
 			}
 		}
 	}
 }
 
+// newNode 方法用于创建一个保存 value 的新节点，如果使用节点池，那么从节点池中获取
+// The newNode method is used to create a new node holding value, the node is taken from the node pool if one is used
+func (q *LockFreeQueueOf[T]) newNode(value T) *shd.NodeOf[T] {
+	if q.pool != nil {
+		node := q.pool.Get()
+		node.Value = value
+		return node
+	}
+	return shd.NewNodeOf(value)
+}
+
+// PushBatch 方法用于将一组值按顺序添加到 LockFreeQueue 队列的末尾。
+// 这些值会先被串成一条节点链，然后通过一次 CAS 操作链接到队列中，并且只更新一次队列的长度
+// The PushBatch method is used to add a group of values to the end of the LockFreeQueue queue in order.
+// The values are first built into a chain of nodes, which is then linked into the queue with a single CAS operation, and the length of the queue is updated only once
+func (q *LockFreeQueueOf[T]) PushBatch(values []T) {
+	// 如果没有值，直接返回
+	// If there are no values, return directly
+	if len(values) == 0 {
+		return
+	}
+
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	// 按顺序把所有的值串成一条节点链，first 是链的第一个节点，last 是链的最后一个节点
+	// Build all the values into a chain of nodes in order, first is the first node of the chain and last is the last node of the chain
+	first := q.newNode(values[0])
+	last := first
+	for _, value := range values[1:] {
+		node := q.newNode(value)
+		last.Next = unsafe.Pointer(node)
+		last = node
+	}
+
+	attempt := 0
+
+	// 使用无限循环来尝试将节点链添加到队列的末尾
+	// Use an infinite loop to try to add the chain of nodes to the end of the queue
+	for {
+		// 加载队列的尾节点以及尾节点的下一个节点
+		// Load the tail node of the queue and the next node of the tail node
+		tail := shd.LoadNodeOf[T](&q.tail)
+		next := shd.LoadNodeOf[T](&tail.Next)
+
+		// 检查尾节点是否仍然是队列的尾节点
+		// Check if the tail node is still the tail node of the queue
+		if tail == shd.LoadNodeOf[T](&q.tail) {
+			if next == nil {
+				// 尝试将节点链的第一个节点链接到尾节点之后
+				// Try to link the first node of the chain after the tail node
+				if shd.CompareAndSwapNode(&tail.Next, next, first) {
+					// 如果成功，那么将队列的尾节点设置为节点链的最后一个节点，失败说明其他 goroutine 已经帮助推进了尾节点
+					// If successful, then set the tail node of the queue to the last node of the chain, a failure means another goroutine has already helped to advance the tail node
+					shd.CompareAndSwapNode(&q.tail, tail, last)
+
+					// 一次性增加队列的长度
+					// Increase the length of the queue at once
+					atomic.AddInt64(&q.length, int64(len(values)))
+					q.stats.Add(shd.CounterPushes, len(values))
+
+					// 唤醒等待数据的 goroutine
+					// Wake up the goroutines waiting for data
+					q.notEmpty.Notify()
+					return
+				}
+
+				// 其他生产者抢先链接了节点，记录一次 CAS 失败，退避之后重试
+				// Another producer linked its node first, count a CAS failure, back off and try again
+				q.stats.Inc(shd.CounterCASFailures)
+				q.backoff.Wait(&attempt)
+			} else {
+				// 尾节点落后了，帮助推进尾节点
+				// The tail node is lagging behind, help to advance the tail node
+				shd.CompareAndSwapNode(&q.tail, tail, next)
+			}
+		}
+	}
+}
+
+// PopBatch 方法用于从 LockFreeQueue 队列的头部移除最多 len(dst) 个值，并按顺序写入 dst，返回移除的值的数量。
+// 这些值通过一次 CAS 操作从队列中摘下，并且只更新一次队列的长度，队列为空时返回 0
+// The PopBatch method is used to remove up to len(dst) values from the head of the LockFreeQueue queue and write them into dst in order, returning the number of values removed.
+// The values are detached from the queue with a single CAS operation and the length of the queue is updated only once, 0 is returned when the queue is empty
+func (q *LockFreeQueueOf[T]) PopBatch(dst []T) int {
+	// 如果 dst 没有空间，直接返回
+	// If dst has no room, return directly
+	if len(dst) == 0 {
+		return 0
+	}
+
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+
+	// 使用无限循环来尝试从队列的头部摘下一段节点
+	// Use an infinite loop to try to detach a run of nodes from the head of the queue
+	for {
+		// 加载队列的头节点、尾节点以及头节点的下一个节点
+		// Load the head node, the tail node of the queue and the next node of the head node
+		head := shd.LoadNodeOf[T](&q.head)
+		tail := shd.LoadNodeOf[T](&q.tail)
+		next := shd.LoadNodeOf[T](&head.Next)
+
+		// 检查头节点是否仍然是队列的头节点
+		// Check if the head node is still the head node of the queue
+		if head != shd.LoadNodeOf[T](&q.head) {
+			continue
+		}
+
+		if head == tail {
+			// 队列为空，返回 0
+			// The queue is empty, return 0
+			if next == nil {
+				q.stats.Inc(shd.CounterEmptyPops)
+				return 0
+			}
+
+			// 尾节点落后了，帮助推进尾节点
+			// The tail node is lagging behind, help to advance the tail node
+			shd.CompareAndSwapNode(&q.tail, tail, next)
+			continue
+		}
+
+		// 从头节点开始向后读取值，最多读取到尾节点为止，保证新的头节点不会越过尾节点
+		// Read the values starting from the head node, at most up to the tail node, so that the new head node never passes the tail node
+		n := 0
+		last := head
+		for n < len(dst) && last != tail {
+			node := shd.LoadNodeOf[T](&last.Next)
+			if node == nil {
+				break
+			}
+			dst[n] = node.Value
+			last = node
+			n++
+		}
+
+		// 头节点已经被其他 goroutine 移除，重试
+		// The head node has been removed by another goroutine, retry
+		if n == 0 {
+			continue
+		}
+
+		// 尝试将队列的头节点设置为读取到的最后一个节点，它会成为新的哨兵节点
+		// Try to set the head node of the queue to the last node read, it becomes the new sentinel node
+		if shd.CompareAndSwapNode(&q.head, head, last) {
+			// 一次性减少队列的长度
+			// Decrease the length of the queue at once
+			atomic.AddInt64(&q.length, -int64(n))
+			q.stats.Add(shd.CounterPops, n)
+
+			// 如果节点池不为空，那么退休被摘下的节点，否则交给 GC 回收，其他 goroutine 可能仍在读取它们
+			// If the node pool is not nil, then retire the detached nodes, otherwise leave them to the GC, other goroutines may still be reading them
+			if q.pool != nil {
+				for node := head; node != last; {
+					following := shd.LoadNodeOf[T](&node.Next)
+					q.reclaimer.Retire(unsafe.Pointer(node))
+					node = following
+				}
+			}
+
+			// 返回移除的值的数量
+			// Return the number of values removed
+			return n
+		}
+
+		// 其他消费者抢先移除了头节点，记录一次 CAS 失败，退避之后重试
+		// Another consumer removed the head node first, count a CAS failure, back off and try again
+		q.stats.Inc(shd.CounterCASFailures)
+		q.backoff.Wait(&attempt)
+	}
+}
+
+// PopWait 方法用于从 LockFreeQueue 队列的头部移除并返回一个值，如果队列为空，会先自旋再挂起等待，直到有值可用或者 ctx 结束
+// The PopWait method is used to remove and return a value from the head of the LockFreeQueue queue, if the queue is empty it spins and then parks until a value is available or ctx is done
+func (q *LockFreeQueueOf[T]) PopWait(ctx context.Context) (T, error) {
+	// 快速路径，队列不为空时直接返回
+	// Fast path, return directly when the queue is not empty
+	if value, ok := q.Pop(); ok {
+		return value, nil
+	}
+
+	// 慢速路径，等待直到弹出一个值或者 ctx 结束
+	// Slow path, wait until a value is popped or ctx is done
+	var value T
+	err := q.notEmpty.Wait(ctx, func() bool {
+		var ok bool
+		value, ok = q.Pop()
+		return ok
+	})
+	return value, err
+}
+
+// Peek 方法用于返回 LockFreeQueue 队列头部的值但不移除它，如果队列为空，返回 T 的零值和 false。
+// Peek 是可线性化的：返回的值在重新确认头节点的那一刻位于队列的头部，返回 false 时，读取到哨兵节点没有下一个节点的那一刻队列为空。
+// 在并发的 Pop 之后，该值可能已经不在队列中
+// The Peek method is used to return the value at the head of the LockFreeQueue queue without removing it, returns the zero value of T and false if the queue is empty.
+// Peek is linearizable: the returned value was at the head of the queue at the moment the head node was confirmed again, and when false is returned the queue was empty at the moment the sentinel node was seen to have no next node.
+// After a concurrent Pop the value may no longer be in the queue
+func (q *LockFreeQueueOf[T]) Peek() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	for {
+		// 加载队列的头节点以及头节点的下一个节点
+		// Load the head node of the queue and the next node of the head node
+		head := shd.LoadNodeOf[T](&q.head)
+		next := shd.LoadNodeOf[T](&head.Next)
+
+		// 哨兵节点没有下一个节点，说明队列是空的。节点只有在拥有下一个节点之后才会被移除，所以此时它一定还是头节点
+		// The sentinel node has no next node, the queue is empty. A node is only removed after it has a next node, so it must still be the head node at this moment
+		if next == nil {
+			var zero T
+			return zero, false
+		}
+
+		// 读取第一个值，然后确认头节点没有变化，保证读取时该值仍在队列中
+		// Read the first value, then confirm the head node has not changed, so the value was still in the queue when it was read
+		value := next.Value
+		if head == shd.LoadNodeOf[T](&q.head) {
+			return value, true
+		}
+	}
+}
+
+// Range 方法用于从头部开始按 FIFO 顺序遍历队列中的值，但不移除它们，fn 返回 false 时停止遍历。
+// 遍历是弱一致的：它不会阻塞 Push 和 Pop，可能会看到遍历开始之后推入的值，也可能看到遍历期间已经被弹出的值，但每个值最多只会出现一次。
+// 使用节点池时，遍历期间被弹出的节点不会被复用，因此 fn 不应该长时间阻塞
+// The Range method is used to walk the values in the queue in FIFO order starting from the head without removing them, the walk stops when fn returns false.
+// The walk is weakly consistent: it never blocks Push and Pop, it may see values pushed after the walk started as well as values popped during the walk, but each value appears at most once.
+// With a node pool, nodes popped during the walk are not reused, so fn should not block for a long time
+func (q *LockFreeQueueOf[T]) Range(fn func(value T) bool) {
+	// 如果启用了节点回收器，进入临界区，保证遍历到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes walked are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	// 从哨兵节点的下一个节点开始，沿着节点链遍历，直到最后一个节点。被弹出的节点仍然指向它的下一个节点，所以遍历可以继续
+	// Start from the next node of the sentinel node and follow the chain of nodes until the last node. A popped node still points to its next node, so the walk can go on
+	for node := shd.LoadNodeOf[T](&shd.LoadNodeOf[T](&q.head).Next); node != nil; node = shd.LoadNodeOf[T](&node.Next) {
+		if !fn(node.Value) {
+			return
+		}
+	}
+}
+
+// Snapshot 方法用于按 FIFO 顺序返回队列中所有值的副本，但不移除它们。它与 Range 一样是弱一致的
+// The Snapshot method is used to return a copy of all the values in the queue in FIFO order without removing them. It is weakly consistent just like Range
+func (q *LockFreeQueueOf[T]) Snapshot() []T {
+	values := make([]T, 0, snapshotCap(q.Length()))
+	q.Range(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// snapshotCap 函数用于根据长度计算快照的初始容量，并发修改时长度可能短暂为负数
+// The snapshotCap function is used to compute the initial capacity of a snapshot from the length, the length may briefly be negative under concurrent modification
+func snapshotCap(length int64) int {
+	if length < 0 {
+		return 0
+	}
+	return int(length)
+}
+
 // Length 方法用于获取 LockFreeQueue 队列的长度
 // The Length method is used to get the length of the LockFreeQueue queue
-func (q *LockFreeQueue) Length() int64 {
+func (q *LockFreeQueueOf[T]) Length() int64 {
 	// 使用 atomic.Loadint64 函数获取队列的长度
 	// Use the atomic.Loadint64 function to get the length of the queue
 	return atomic.LoadInt64(&q.length)
@@ -197,25 +575,114 @@ func (q *LockFreeQueue) Length() int64 {
 
 // IsEmpty 方法用于判断 LockFreeQueue 队列是否为空
 // The IsEmpty method is used to determine whether the LockFreeQueue queue is empty
-func (q *LockFreeQueue) IsEmpty() bool {
+func (q *LockFreeQueueOf[T]) IsEmpty() bool {
 	// 使用 Length 方法获取队列的长度，如果长度为 0，那么队列为空
 	// Use the Length method to get the length of the queue, if the length is 0, then the queue is empty
 	return q.Length() == 0
 }
 
-// Reset 方法用于重置 LockFreeQueue 队列
-// The Reset method is used to reset the LockFreeQueue queue
-func (q *LockFreeQueue) Reset() {
-	// 创建一个新的 Node 结构体实例
-	// Create a new Node struct instance
-	fristNode := shd.NewNode(nil)
+// Reset 方法用于重置 LockFreeQueue 队列，它会原子地摘下队列中的所有值并丢弃，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeQueue queue, it atomically detaches and discards all the values in the queue, and can be called concurrently with Push and Pop
+func (q *LockFreeQueueOf[T]) Reset() {
+	q.detach(false)
+}
+
+// Drain 方法用于原子地摘下队列中的所有值，并按 FIFO 顺序返回，队列为空时返回 nil。
+// 它可以与 Push 和 Pop 并发调用，摘下之后推入的值会留在队列中
+// The Drain method is used to atomically detach all the values in the queue and return them in FIFO order, nil is returned when the queue is empty.
+// It can be called concurrently with Push and Pop, values pushed after the detach stay in the queue
+func (q *LockFreeQueueOf[T]) Drain() []T {
+	return q.detach(true)
+}
+
+// detach 方法用于通过一次 CAS 操作把队列的头节点移动到尾节点，摘下两者之间的所有节点，collect 为 true 时按顺序返回这些节点的值
+// The detach method is used to move the head node of the queue to the tail node with a single CAS operation, detaching all the nodes between them, the values of these nodes are returned in order when collect is true
+func (q *LockFreeQueueOf[T]) detach(collect bool) []T {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if q.reclaimer != nil {
+		guard := q.reclaimer.Enter()
+		defer q.reclaimer.Exit(guard)
+	}
+
+	var values []T
+	attempt := 0
+	for {
+		// 加载队列的头节点、尾节点以及头节点的下一个节点
+		// Load the head node, the tail node of the queue and the next node of the head node
+		head := shd.LoadNodeOf[T](&q.head)
+		tail := shd.LoadNodeOf[T](&q.tail)
+		next := shd.LoadNodeOf[T](&head.Next)
+
+		// 检查头节点是否仍然是队列的头节点
+		// Check if the head node is still the head node of the queue
+		if head != shd.LoadNodeOf[T](&q.head) {
+			continue
+		}
 
-	// 将队列的头节点和尾节点都设置为新创建的节点
-	// Set both the head node and the tail node of the queue to the newly created node
-	q.head = unsafe.Pointer(fristNode)
-	q.tail = unsafe.Pointer(fristNode)
+		// 队列为空，没有可以摘下的节点
+		// The queue is empty, there are no nodes to detach
+		if head == tail && next == nil {
+			return nil
+		}
+
+		// 尾节点落后了，帮助推进尾节点，直到它是队列的最后一个节点
+		// The tail node is lagging behind, help to advance the tail node until it is the last node of the queue
+		if last := shd.LoadNodeOf[T](&tail.Next); last != nil {
+			shd.CompareAndSwapNode(&q.tail, tail, last)
+			continue
+		}
+
+		// 统计头节点之后直到尾节点的所有节点，需要时读取它们的值。尾节点会成为新的哨兵节点，它的值也必须在 CAS 之前读取
+		// Count all the nodes after the head node up to the tail node and read their values if needed. The tail node becomes the new sentinel node, so its value must also be read before the CAS
+		n := int64(0)
+		values = values[:0]
+		for node := head; node != tail; {
+			node = shd.LoadNodeOf[T](&node.Next)
+			if node == nil {
+				break
+			}
+			if collect {
+				values = append(values, node.Value)
+			}
+			n++
+		}
+
+		// 尝试将队列的头节点设置为尾节点，成功后两者之间的节点都被摘下
+		// Try to set the head node of the queue to the tail node, the nodes between them are detached once it succeeds
+		if shd.CompareAndSwapNode(&q.head, head, tail) {
+			// 一次性减少队列的长度
+			// Decrease the length of the queue at once
+			atomic.AddInt64(&q.length, -n)
+
+			// 如果节点池不为空，那么退休被摘下的节点，否则交给 GC 回收，其他 goroutine 可能仍在读取它们
+			// If the node pool is not nil, then retire the detached nodes, otherwise leave them to the GC, other goroutines may still be reading them
+			if q.pool != nil {
+				for node := head; node != tail; {
+					following := shd.LoadNodeOf[T](&node.Next)
+					q.reclaimer.Retire(unsafe.Pointer(node))
+					node = following
+				}
+			}
+
+			// 返回摘下的值，只有 Drain 取走的值计入弹出的数量
+			// Return the detached values, only the values taken by Drain count as popped
+			if !collect {
+				return nil
+			}
+			q.stats.Add(shd.CounterPops, len(values))
+			return values
+		}
+
+		// 其他 goroutine 抢先移除了头节点，记录一次 CAS 失败，退避之后重试
+		// Another goroutine removed the head node first, count a CAS failure, back off and try again
+		q.stats.Inc(shd.CounterCASFailures)
+		q.backoff.Wait(&attempt)
+	}
+}
 
-	// 使用 atomic.Storeint64 函数将队列的长度设置为 0
-	// Use the atomic.Storeint64 function to set the length of the queue to 0
-	atomic.StoreInt64(&q.length, 0)
+// Stats 方法用于返回队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeQueueOf[T]) Stats() Stats {
+	return q.stats.Snapshot()
 }
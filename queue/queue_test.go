@@ -1,8 +1,12 @@
 package queue
 
 import (
+	"context"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -292,3 +296,790 @@ func TestLockFreeQueue_WithPool_ParallelDevilMode(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestLockFreeQueueOf_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 1000000
+
+	// Create a new generic queue
+	q := NewOf[int]()
+
+	// Test enqueueing elements into the queue
+	for i := 0; i < count; i++ {
+		q.Push(i)
+	}
+
+	// Verify the elements in the queue
+	for i := 0; i < count; i++ {
+		v, ok := q.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, v, "Incorrect value in the queue. Expected %d, got %d", i, v)
+	}
+
+	// Verify the queue length
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeQueueOf_ZeroValue(t *testing.T) {
+	q := NewOf[int]()
+
+	// Test that zero values are stored and not mistaken for an empty queue
+	q.Push(0)
+	assert.Equal(t, int64(1), q.Length(), "Incorrect queue length. Expected 1, got %d", q.Length())
+
+	v, ok := q.Pop()
+	assert.True(t, ok, "Failed to pop zero value")
+	assert.Equal(t, 0, v, "Incorrect value in the queue. Expected 0, got %d", v)
+
+	// Test popping elements from an empty queue
+	v, ok = q.Pop()
+	assert.False(t, ok, "Popped value from an empty queue")
+	assert.Equal(t, 0, v, "Expected zero value from an empty queue")
+}
+
+func TestLockFreeQueueOf_WithPool_ParallelDevilMode(t *testing.T) {
+	q := NewWithPoolOf[int]()
+
+	// Test enqueueing elements into the queue
+	wg := sync.WaitGroup{}
+	for j := 0; j < 10000; j++ {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				q.Push(i)
+			}(i)
+		}
+	}
+
+	// Verify the elements in the queue
+	for j := 0; j < 10000; j++ {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if v, ok := q.Pop(); ok {
+					assert.True(t, v >= 0 && v < 10, "Incorrect value in the queue. Got %d", v)
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestLockFreeQueue_WithPool_StressNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 8, 8, 50000
+	total := producers * perProducer
+
+	q := NewWithPool()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	// Start the consumers, they pop until every value has been seen
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v := q.Pop(); v != nil {
+					atomic.AddInt32(&seen[v.(int)], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeQueue_PopWait(t *testing.T) {
+	q := New()
+
+	// Push a value after the consumer has started waiting
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.Push(1)
+	}()
+
+	v, err := q.PopWait(context.Background())
+	assert.NoError(t, err, "PopWait returned an error")
+	assert.Equal(t, 1, v, "Incorrect value from PopWait. Expected 1, got %v", v)
+}
+
+func TestLockFreeQueue_PopWaitCanceled(t *testing.T) {
+	q := New()
+
+	// Waiting on an empty queue must return once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	v, err := q.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected a deadline exceeded error")
+	assert.Nil(t, v, "Expected nil value from an empty queue")
+}
+
+func TestLockFreeQueue_PopWaitParallel(t *testing.T) {
+	q := New()
+	count := 1000
+
+	// Start the consumers before anything is pushed
+	wg := sync.WaitGroup{}
+	received := int64(0)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count/10; j++ {
+				if _, err := q.PopWait(context.Background()); err == nil {
+					atomic.AddInt64(&received, 1)
+				}
+			}
+		}()
+	}
+
+	// Push the values from several producers
+	for i := 0; i < count; i++ {
+		go q.Push(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(count), received, "Incorrect number of values received. Expected %d, got %d", count, received)
+}
+
+func TestLockFreeQueue_PushBatchPopBatch(t *testing.T) {
+	q := New()
+
+	// Push a batch, nil values are ignored
+	q.PushBatch([]interface{}{1, nil, 2, 3, nil, 4, 5})
+	assert.Equal(t, int64(5), q.Length(), "Incorrect queue length. Expected 5, got %d", q.Length())
+
+	// A batch can be mixed with single pushes
+	q.Push(6)
+
+	// Pop a partial batch, values come out in FIFO order
+	dst := make([]interface{}, 4)
+	n := q.PopBatch(dst)
+	assert.Equal(t, 4, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, dst[:n], "Popped values are incorrect")
+	assert.Equal(t, int64(2), q.Length(), "Incorrect queue length. Expected 2, got %d", q.Length())
+
+	// Pop the rest, the batch is cut short by the end of the queue
+	n = q.PopBatch(dst)
+	assert.Equal(t, 2, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{5, 6}, dst[:n], "Popped values are incorrect")
+
+	// The queue is empty now
+	assert.Equal(t, 0, q.PopBatch(dst), "PopBatch on an empty queue should return 0")
+	assert.Nil(t, q.Pop(), "Pop on an empty queue should return nil")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+
+	// The queue keeps working normally after the batches
+	q.PushBatch(nil)
+	q.Push(7)
+	assert.Equal(t, 7, q.Pop(), "Popped value is incorrect")
+}
+
+func TestLockFreeQueue_WithPool_BatchNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer, batch := 8, 8, 50000, 16
+	total := producers * perProducer
+
+	q := NewWithPoolOf[int]()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values in batches
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			values := make([]int, 0, batch)
+			for i := 0; i < perProducer; i++ {
+				values = append(values, p*perProducer+i)
+				if len(values) == batch || i == perProducer-1 {
+					q.PushBatch(values)
+					values = values[:0]
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, half of them pop in batches and the others pop one by one
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			dst := make([]int, batch)
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if c%2 == 0 {
+					n := q.PopBatch(dst)
+					for _, v := range dst[:n] {
+						atomic.AddInt32(&seen[v], 1)
+					}
+					atomic.AddInt64(&popped, int64(n))
+				} else if v, ok := q.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeQueue_Drain(t *testing.T) {
+	q := New()
+
+	// Draining an empty queue returns nil
+	assert.Nil(t, q.Drain(), "Drain on an empty queue should return nil")
+
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+
+	// All the values are returned in FIFO order
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, q.Drain(), "Drained values are incorrect")
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+	assert.Nil(t, q.Pop(), "Pop on a drained queue should return nil")
+
+	// The queue keeps working normally after a drain and a reset
+	q.Push(10)
+	q.Reset()
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+	q.Push(11)
+	assert.Equal(t, 11, q.Pop(), "Popped value is incorrect")
+}
+
+func TestLockFreeQueue_WithPool_DrainParallel(t *testing.T) {
+	producers, consumers, perProducer := 4, 4, 50000
+	total := producers * perProducer
+
+	q := NewWithPoolOf[int]()
+
+	// seen records how many times each value has been popped or drained
+	seen := make([]int32, total)
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+	cwg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	// Start the consumers, one of them keeps draining while the others pop
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			for {
+				// Read the flag first, so an empty result after it means the queue is drained
+				finished := atomic.LoadInt32(&done) == 1
+				got := 0
+				if c == 0 {
+					for _, v := range q.Drain() {
+						atomic.AddInt32(&seen[v], 1)
+						got++
+					}
+				} else if v, ok := q.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+					got++
+				}
+				if got == 0 && finished {
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	atomic.StoreInt32(&done, 1)
+	cwg.Wait()
+
+	// Verify that every value was either popped or drained exactly once
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value handled an unexpected number of times", "value %d handled %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeQueue_ResetParallel(t *testing.T) {
+	q := New()
+
+	wg := sync.WaitGroup{}
+
+	// Push, pop and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				switch {
+				case i == 0 && j%100 == 0:
+					q.Reset()
+				case i%2 == 0:
+					q.Pop()
+				default:
+					q.Push(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	q.Reset()
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+	assert.Nil(t, q.Pop(), "Pop on a reset queue should return nil")
+}
+
+func TestLockFreeQueue_TryPop(t *testing.T) {
+	var q Queue = New()
+
+	// TryPop on an empty queue reports that nothing was popped
+	v, ok := q.TryPop()
+	assert.False(t, ok, "TryPop on an empty queue should return false")
+	assert.Nil(t, v, "TryPop on an empty queue should return nil")
+
+	// nil is dropped by default, but a typed nil pointer is a regular value
+	var p *int
+	q.Push(nil)
+	q.Push(p)
+	q.Push(0)
+	assert.Equal(t, int64(2), q.Length(), "Incorrect queue length. Expected 2, got %d", q.Length())
+
+	v, ok = q.TryPop()
+	assert.True(t, ok, "TryPop should return true")
+	assert.Equal(t, p, v, "Popped value is incorrect")
+	v, ok = q.TryPop()
+	assert.True(t, ok, "TryPop should return true")
+	assert.Equal(t, 0, v, "Popped value is incorrect")
+	_, ok = q.TryPop()
+	assert.False(t, ok, "TryPop on an empty queue should return false")
+}
+
+func TestLockFreeQueue_WithAcceptNil(t *testing.T) {
+	for _, q := range []*LockFreeQueue{New(WithAcceptNil()), NewWithPool(WithAcceptNil())} {
+		// nil values are stored like any other value
+		q.Push(nil)
+		q.PushBatch([]interface{}{1, nil})
+		assert.Equal(t, int64(3), q.Length(), "Incorrect queue length. Expected 3, got %d", q.Length())
+
+		// TryPop tells a nil value apart from an empty queue
+		for _, expected := range []interface{}{nil, 1, nil} {
+			v, ok := q.TryPop()
+			assert.True(t, ok, "TryPop should return true")
+			assert.Equal(t, expected, v, "Popped value is incorrect")
+		}
+		v, ok := q.TryPop()
+		assert.False(t, ok, "TryPop on an empty queue should return false")
+		assert.Nil(t, v, "TryPop on an empty queue should return nil")
+		assert.True(t, q.IsEmpty(), "Queue should be empty")
+	}
+}
+
+func TestLockFreeQueue_Peek(t *testing.T) {
+	q := New()
+
+	// Peek on an empty queue reports that there is no value
+	v, ok := q.Peek()
+	assert.False(t, ok, "Peek on an empty queue should return false")
+	assert.Nil(t, v, "Peek on an empty queue should return nil")
+
+	q.Push(1)
+	q.Push(2)
+
+	// Peek returns the head value without removing it
+	for i := 0; i < 3; i++ {
+		v, ok = q.Peek()
+		assert.True(t, ok, "Peek should return true")
+		assert.Equal(t, 1, v, "Peeked value is incorrect")
+	}
+	assert.Equal(t, int64(2), q.Length(), "Incorrect queue length. Expected 2, got %d", q.Length())
+
+	// After a pop the next value is at the head
+	assert.Equal(t, 1, q.Pop(), "Popped value is incorrect")
+	v, _ = q.Peek()
+	assert.Equal(t, 2, v, "Peeked value is incorrect")
+}
+
+func TestLockFreeQueue_PeekParallel(t *testing.T) {
+	for _, q := range []*LockFreeQueueOf[int]{NewOf[int](), NewWithPoolOf[int]()} {
+		total := 100000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				q.Push(i)
+			}
+		}()
+
+		// Consumers pop the values until the queue has been drained
+		for c := 0; c < 2; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					q.Pop()
+				}
+			}()
+		}
+
+		// The head only moves forward, so every peeker must see non-decreasing values
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				last := -1
+				for atomic.LoadInt32(&done) == 0 {
+					if v, ok := q.Peek(); ok {
+						if v < last || v >= total {
+							assert.Failf(t, "Peeked value is out of order", "peeked %d after %d", v, last)
+							return
+						}
+						last = v
+					}
+				}
+			}()
+		}
+
+		// Stop once every value has been pushed and popped
+		pwg.Wait()
+		for !q.IsEmpty() {
+			time.Sleep(time.Millisecond)
+		}
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		_, ok := q.Peek()
+		assert.False(t, ok, "Peek on an empty queue should return false")
+	}
+}
+
+func TestLockFreeQueue_Range(t *testing.T) {
+	q := New()
+
+	// Range and Snapshot on an empty queue see nothing
+	q.Range(func(v interface{}) bool {
+		assert.Fail(t, "Range on an empty queue should not call fn")
+		return true
+	})
+	assert.Empty(t, q.Snapshot(), "Snapshot of an empty queue should be empty")
+
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+	}
+
+	// Snapshot returns the values in FIFO order without removing them
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4}, q.Snapshot(), "Snapshot is incorrect")
+	assert.Equal(t, int64(5), q.Length(), "Incorrect queue length. Expected 5, got %d", q.Length())
+
+	// Range stops as soon as fn returns false
+	var seen []interface{}
+	q.Range(func(v interface{}) bool {
+		seen = append(seen, v)
+		return len(seen) < 3
+	})
+	assert.Equal(t, []interface{}{0, 1, 2}, seen, "Range visited incorrect values")
+
+	// Popped values are no longer visited
+	q.Pop()
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, q.Snapshot(), "Snapshot is incorrect")
+}
+
+func TestLockFreeQueue_SnapshotParallel(t *testing.T) {
+	for _, q := range []*LockFreeQueueOf[int]{NewOf[int](), NewWithPoolOf[int]()} {
+		total := 50000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values while consumers pop them
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				q.Push(i)
+			}
+		}()
+		for c := 0; c < 2; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					q.Pop()
+				}
+			}()
+		}
+
+		// Every snapshot must be strictly increasing, so no value is visited twice or out of order
+		for p := 0; p < 2; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					values := q.Snapshot()
+					for i := 1; i < len(values); i++ {
+						if values[i] <= values[i-1] {
+							assert.Failf(t, "Snapshot is out of order", "%d after %d", values[i], values[i-1])
+							return
+						}
+					}
+				}
+			}()
+		}
+
+		pwg.Wait()
+		for !q.IsEmpty() {
+			time.Sleep(time.Millisecond)
+		}
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		assert.Empty(t, q.Snapshot(), "Snapshot of an empty queue should be empty")
+	}
+}
+
+func TestLockFreeQueue_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	q := New()
+	q.Push(1)
+	q.Pop()
+	assert.Equal(t, Stats{}, q.Stats(), "Statistics should be disabled by default")
+
+	// Count single, batch and drained operations of a queue with a node pool
+	q = NewWithPool(WithStats())
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 90; i++ {
+		q.Pop()
+	}
+	q.PushBatch([]interface{}{1, 2, 3})
+	assert.Equal(t, 2, q.PopBatch(make([]interface{}, 2)), "Incorrect number of values popped")
+	assert.Equal(t, 11, len(q.Drain()), "Incorrect number of values drained")
+	assert.Nil(t, q.Pop(), "Popped value from an empty queue")
+
+	s := q.Stats()
+	assert.Equal(t, uint64(103), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(103), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+	assert.Equal(t, uint64(0), s.FullRejections, "An unbounded queue should never reject a push")
+	assert.Equal(t, uint64(103), s.PoolGets, "Incorrect number of pool gets")
+	assert.LessOrEqual(t, s.PoolMisses, s.PoolGets, "Pool misses should not exceed pool gets")
+	assert.LessOrEqual(t, s.PoolPuts, s.Pops, "Pool puts should not exceed the nodes removed")
+}
+
+func TestLockFreeQueue_StatsParallel(t *testing.T) {
+	q := NewOf[int](WithStats())
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines, the counters of every shard are summed up
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				q.Push(j)
+			}
+			for j := 0; j < 10000; j++ {
+				for _, ok := q.Pop(); !ok; _, ok = q.Pop() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	s := q.Stats()
+	assert.Equal(t, uint64(80000), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(80000), s.Pops, "Incorrect number of pops")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+// countingAllocatorOf is a node allocator that keeps freed nodes in a free list, counts its calls and checks that Put only receives nodes returned by Get
+type countingAllocatorOf[T any] struct {
+	mu         sync.Mutex
+	free       []*NodeOf[T]
+	issued     map[*NodeOf[T]]bool
+	gets, puts int
+	foreign    int
+}
+
+func newCountingAllocatorOf[T any]() *countingAllocatorOf[T] {
+	return &countingAllocatorOf[T]{issued: map[*NodeOf[T]]bool{}}
+}
+
+func (a *countingAllocatorOf[T]) Get() *NodeOf[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.gets++
+	node := new(NodeOf[T])
+	if n := len(a.free); n > 0 {
+		node = a.free[n-1]
+		a.free = a.free[:n-1]
+	}
+	a.issued[node] = true
+	return node
+}
+
+func (a *countingAllocatorOf[T]) Put(n *NodeOf[T]) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.puts++
+	if !a.issued[n] {
+		a.foreign++
+		return
+	}
+	delete(a.issued, n)
+	a.free = append(a.free, n)
+}
+
+func TestLockFreeQueue_WithPool(t *testing.T) {
+	// New with WithPool behaves like NewWithPool
+	q := New(WithPool(), WithStats())
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, q.Pop(), "Failed to pop a value")
+	}
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+	assert.Equal(t, uint64(100), q.Stats().PoolGets, "The nodes should come from the node pool")
+}
+
+func TestLockFreeQueue_WithNodeAllocator(t *testing.T) {
+	a := newCountingAllocatorOf[interface{}]()
+	q := New(WithNodeAllocator(a))
+
+	// The sentinel node and every pushed value take a node from the allocator
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, q.Pop(), "Failed to pop a value")
+	}
+	assert.Equal(t, 101, a.gets, "The sentinel node and every push should allocate a node")
+
+	// Nodes are only given back once no goroutine references them, which takes a number of retired nodes.
+	// Drain replaces the sentinel node, and the replaced sentinel node must also come from the allocator
+	for i := 0; i < 10000; i++ {
+		q.Push(i)
+		if i%100 == 0 {
+			q.Drain()
+		} else {
+			q.Pop()
+		}
+	}
+	assert.Greater(t, a.puts, 0, "Popped nodes should be given back to the allocator")
+	assert.LessOrEqual(t, a.puts, a.gets, "More nodes were given back than allocated")
+	assert.Equal(t, 0, a.foreign, "Only nodes returned by Get should be given back")
+
+	// The allocator must allocate nodes of the element type
+	assert.Panics(t, func() { NewOf[int](WithNodeAllocator(a)) }, "An allocator of another element type should be rejected")
+}
+
+func TestLockFreeQueue_WithNodeAllocatorOf(t *testing.T) {
+	a := newCountingAllocatorOf[int]()
+	q := NewOf[int](WithNodeAllocatorOf[int](a))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines through the typed allocator
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				q.Push(j)
+				q.Pop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+	assert.Equal(t, 80001, a.gets, "The sentinel node and every push should allocate a node")
+	assert.Greater(t, a.puts, 0, "Popped nodes should be given back to the allocator")
+	assert.Equal(t, 0, a.foreign, "Only nodes returned by Get should be given back")
+}
+
+func TestLockFreeQueue_WithBackoff(t *testing.T) {
+	var calls atomic.Int64
+	q := NewOf[int](WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines, every CAS failure backs off once
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				q.Push(j)
+				runtime.Gosched()
+				q.Pop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+	assert.Equal(t, q.Stats().CASFailures, uint64(calls.Load()), "Every CAS failure should back off once")
+}
@@ -0,0 +1,123 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// segmentSize 是每个分段中槽位的数量
+// segmentSize is the number of slots in every segment
+const segmentSize = 1024
+
+// 槽位的状态：空闲、已经发布了值、已经被消费者取走或者作废
+// The states of a slot: empty, a value has been published, taken or poisoned by a consumer
+const (
+	slotEmpty uint32 = iota
+	slotFull
+	slotTaken
+)
+
+// segmentSlotOf 是分段中的一个槽位，值直接保存在槽位中
+// segmentSlotOf is a slot of a segment, the value is stored inline in the slot
+type segmentSlotOf[T any] struct {
+	// state 是槽位的状态，生产者通过 CAS 把它从空闲改为已发布，消费者通过原子交换把它改为已取走
+	// state is the state of the slot, a producer changes it from empty to full with a CAS, a consumer changes it to taken with an atomic swap
+	state uint32
+
+	// value 是槽位中的值，只有发布之后才能被读取
+	// value is the value in the slot, it can only be read after it has been published
+	value T
+}
+
+// segmentOf 是分段队列中的一个固定大小的分段，生产者和消费者分别通过原子加法占用槽位
+// segmentOf is a fixed-size segment of the segmented queue, producers and consumers claim slots with an atomic add each
+type segmentOf[T any] struct {
+	// enqueue 是下一个要被生产者占用的槽位下标，可能超过分段的大小
+	// enqueue is the index of the next slot to be claimed by a producer, it may exceed the size of the segment
+	enqueue int64
+
+	// _ 用于把生产者修改的 enqueue 和消费者修改的 dequeue 分隔到不同的缓存行，避免伪共享
+	// _ separates enqueue, modified by producers, from dequeue, modified by consumers, onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
+	// dequeue 是下一个要被消费者占用的槽位下标，可能超过分段的大小
+	// dequeue is the index of the next slot to be claimed by a consumer, it may exceed the size of the segment
+	dequeue int64
+
+	// _ 用于把 dequeue 与之后的字段分隔到不同的缓存行
+	// _ separates dequeue from the fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// next 是指向下一个分段的指针
+	// next is a pointer to the next segment
+	next unsafe.Pointer
+
+	// link 是回收器用于串联退休分段的指针
+	// link is the pointer used by the reclaimer to chain retired segments
+	link unsafe.Pointer
+
+	// slots 是分段中的槽位
+	// slots are the slots of the segment
+	slots [segmentSize]segmentSlotOf[T]
+}
+
+// reset 方法用于重置分段，使其可以被重新使用
+// The reset method is used to reset the segment so it can be reused
+func (s *segmentOf[T]) reset() {
+	var zero T
+	s.enqueue = 0
+	s.dequeue = 0
+	s.next = nil
+	s.link = nil
+	for i := range s.slots {
+		s.slots[i].state = slotEmpty
+		s.slots[i].value = zero
+	}
+}
+
+// loadSegmentOf 函数用于原子地加载一个分段指针
+// The loadSegmentOf function is used to atomically load a segment pointer
+func loadSegmentOf[T any](p *unsafe.Pointer) *segmentOf[T] {
+	return (*segmentOf[T])(atomic.LoadPointer(p))
+}
+
+// segmentPoolOf 是分段池，用于存储和获取分段
+// segmentPoolOf is the segment pool, used to store and retrieve segments
+type segmentPoolOf[T any] struct {
+	// pool 是一个同步池，用于存储和获取分段
+	// pool is a sync pool, used to store and retrieve segments
+	pool sync.Pool
+
+	// stats 是分段池的统计计数器，为 nil 时不统计
+	// stats are the statistics counters of the segment pool, nothing is counted when it is nil
+	stats *shd.Counters
+}
+
+// newSegmentPoolOf 函数用于创建一个新的分段池，stats 为 nil 时不统计
+// The newSegmentPoolOf function is used to create a new segment pool, nothing is counted when stats is nil
+func newSegmentPoolOf[T any](stats *shd.Counters) *segmentPoolOf[T] {
+	sp := &segmentPoolOf[T]{stats: stats}
+	sp.pool.New = func() interface{} {
+		sp.stats.Inc(shd.CounterPoolMisses)
+		return &segmentOf[T]{}
+	}
+	return sp
+}
+
+// get 方法用于从分段池中获取一个分段
+// The get method is used to get a segment from the segment pool
+func (sp *segmentPoolOf[T]) get() *segmentOf[T] {
+	sp.stats.Inc(shd.CounterPoolGets)
+	return sp.pool.Get().(*segmentOf[T])
+}
+
+// put 方法用于重置一个分段并将其放回分段池
+// The put method is used to reset a segment and put it back into the segment pool
+func (sp *segmentPoolOf[T]) put(s *segmentOf[T]) {
+	s.reset()
+	sp.stats.Inc(shd.CounterPoolPuts)
+	sp.pool.Put(s)
+}
@@ -0,0 +1,255 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeSegmentedQueueOf 是一个泛型无界分段无锁队列结构体，T 为队列中元素的类型。
+// 它把固定大小的数组分段链接成一个链表，生产者和消费者通过对分段下标的原子加法占用槽位，值直接保存在槽位中，
+// 因此只有在一个分段用完时才需要分配新的分段，分配的开销被分摊到整个分段。
+// 消费者用完的分段会被退休，宽限期结束后放回分段池中重新使用
+// LockFreeSegmentedQueueOf is a generic unbounded segmented lock-free queue struct, T is the type of the elements in the queue.
+// It links fixed-size array segments into a list, producers and consumers claim slots with an atomic add on the indices of a segment and the values are stored inline in the slots,
+// so a new segment only has to be allocated when a segment is used up, and the cost of the allocation is amortized over the whole segment.
+// Segments used up by the consumers are retired and put back into the segment pool for reuse once their grace period is over
+type LockFreeSegmentedQueueOf[T any] struct {
+	// length 是队列的长度
+	// length is the length of the queue
+	length int64
+
+	// _ 用于把长度与头尾指针分隔到不同的缓存行
+	// _ separates the length from the head and tail pointers onto different cache lines
+	_ shd.CacheLinePad
+
+	// head 是指向消费者正在使用的分段的指针
+	// head is a pointer to the segment used by the consumers
+	head unsafe.Pointer
+
+	// _ 用于把消费者修改的 head 和生产者修改的 tail 分隔到不同的缓存行，避免伪共享
+	// _ separates head, modified by consumers, from tail, modified by producers, onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
+	// tail 是指向生产者正在使用的分段的指针
+	// tail is a pointer to the segment used by the producers
+	tail unsafe.Pointer
+
+	// _ 用于把 tail 与之后只读的字段分隔到不同的缓存行
+	// _ separates tail from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// pool 是一个分段池，用于存储和获取分段
+	// pool is a segment pool used to store and retrieve segments
+	pool *segmentPoolOf[T]
+
+	// reclaimer 是分段回收器，保证分段在没有 goroutine 引用后才会被复用。分段很大并且很少退休，因此每次退休都尝试推进 epoch
+	// reclaimer is the segment reclaimer, it ensures segments are reused only after no goroutine references them. Segments are large and rarely retired, so every retire tries to advance the epoch
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+
+	// acceptNil 表示 Push 是否接受 nil 值，只在元素类型为 interface{} 的 LockFreeSegmentedQueue 中使用
+	// acceptNil indicates whether Push accepts nil values, it is only used by LockFreeSegmentedQueue whose element type is interface{}
+	acceptNil bool
+}
+
+// NewSegmentedOf 函数用于创建一个新的泛型 LockFreeSegmentedQueueOf 队列，可以通过选项修改队列的行为
+// The NewSegmentedOf function is used to create a new generic LockFreeSegmentedQueueOf queue, the behavior of the queue can be modified with options
+func NewSegmentedOf[T any](opts ...Option) *LockFreeSegmentedQueueOf[T] {
+	// 应用所有的选项，如果启用了统计，那么创建统计计数器，分段池也使用同一组计数器
+	// Apply all the options, if statistics are enabled, then create the statistics counters, the segment pool uses the same counters
+	conf := newConfig(opts)
+	var stats *shd.Counters
+	if conf.stats {
+		stats = shd.NewCounters()
+	}
+	pool := newSegmentPoolOf[T](stats)
+
+	// 创建第一个分段，队列的头指针和尾指针都指向它
+	// Create the first segment, both the head pointer and the tail pointer of the queue point to it
+	first := unsafe.Pointer(pool.get())
+	return &LockFreeSegmentedQueueOf[T]{
+		head:      first,
+		tail:      first,
+		pool:      pool,
+		stats:     stats,
+		backoff:   conf.backoff,
+		acceptNil: conf.acceptNil,
+		reclaimer: shd.NewReclaimerWithInterval(1,
+			func(p unsafe.Pointer) *unsafe.Pointer { return &(*segmentOf[T])(p).link },
+			func(p unsafe.Pointer) { pool.put((*segmentOf[T])(p)) },
+		),
+	}
+}
+
+// Push 方法用于将一个值添加到队列的末尾
+// The Push method is used to add a value to the end of the queue
+func (q *LockFreeSegmentedQueueOf[T]) Push(value T) {
+	// 进入临界区，保证读取到的分段不会被复用
+	// Enter the critical section to ensure the segments read are not reused
+	guard := q.reclaimer.Enter()
+	defer q.reclaimer.Exit(guard)
+
+	// 增加队列的长度，必须在发布值之前，否则长度可能短暂为负数
+	// Increase the length of the queue, this must happen before the value is published, otherwise the length could briefly be negative
+	atomic.AddInt64(&q.length, 1)
+
+	var zero T
+	attempt := 0
+	for {
+		// 加载尾分段，并通过原子加法占用其中的一个槽位
+		// Load the tail segment and claim one of its slots with an atomic add
+		tail := loadSegmentOf[T](&q.tail)
+		idx := atomic.AddInt64(&tail.enqueue, 1) - 1
+
+		if idx < segmentSize {
+			// 写入值，然后通过 CAS 发布它。如果消费者已经作废了这个槽位，清空值并重试
+			// Write the value, then publish it with a CAS. If a consumer has already poisoned the slot, clear the value and try again
+			slot := &tail.slots[idx]
+			slot.value = value
+			if atomic.CompareAndSwapUint32(&slot.state, slotEmpty, slotFull) {
+				q.stats.Inc(shd.CounterPushes)
+				return
+			}
+			slot.value = zero
+			q.stats.Inc(shd.CounterCASFailures)
+			q.backoff.Wait(&attempt)
+			continue
+		}
+
+		// 尾分段已经用完。如果尾指针已经改变，重新开始
+		// The tail segment is used up. If the tail pointer has changed, start over
+		if tail != loadSegmentOf[T](&q.tail) {
+			continue
+		}
+
+		next := loadSegmentOf[T](&tail.next)
+		if next != nil {
+			// 已经有下一个分段，帮助推进尾指针
+			// There already is a next segment, help to advance the tail pointer
+			atomic.CompareAndSwapPointer(&q.tail, unsafe.Pointer(tail), unsafe.Pointer(next))
+			continue
+		}
+
+		// 从分段池中获取一个新的分段，把值放在它的第一个槽位中，然后尝试把它链接到尾分段之后
+		// Get a new segment from the segment pool, put the value in its first slot, then try to link it after the tail segment
+		seg := q.pool.get()
+		seg.enqueue = 1
+		seg.slots[0].value = value
+		seg.slots[0].state = slotFull
+		if atomic.CompareAndSwapPointer(&tail.next, nil, unsafe.Pointer(seg)) {
+			// 链接成功，尝试推进尾指针，失败说明其他 goroutine 已经推进了它
+			// Linked successfully, try to advance the tail pointer, a failure means another goroutine has already advanced it
+			atomic.CompareAndSwapPointer(&q.tail, unsafe.Pointer(tail), unsafe.Pointer(seg))
+			q.stats.Inc(shd.CounterPushes)
+			return
+		}
+
+		// 其他生产者已经链接了新的分段，记录一次 CAS 失败。新分段从未被发布，直接放回分段池
+		// Another producer has linked a new segment, count a CAS failure. The new segment was never published and goes straight back into the segment pool
+		q.stats.Inc(shd.CounterCASFailures)
+		q.backoff.Wait(&attempt)
+		q.pool.put(seg)
+	}
+}
+
+// Pop 方法用于从队列的头部移除并返回一个值，如果队列为空，返回 T 的零值和 false
+// The Pop method is used to remove and return a value from the head of the queue, returns the zero value of T and false if the queue is empty
+func (q *LockFreeSegmentedQueueOf[T]) Pop() (T, bool) {
+	// 进入临界区，保证读取到的分段不会被复用
+	// Enter the critical section to ensure the segments read are not reused
+	guard := q.reclaimer.Enter()
+	defer q.reclaimer.Exit(guard)
+
+	var zero T
+	attempt := 0
+	for {
+		// 加载头分段，消费者已经追上生产者并且没有下一个分段时，队列为空
+		// Load the head segment, the queue is empty when the consumers have caught up with the producers and there is no next segment
+		head := loadSegmentOf[T](&q.head)
+		if atomic.LoadInt64(&head.dequeue) >= atomic.LoadInt64(&head.enqueue) && atomic.LoadPointer(&head.next) == nil {
+			q.stats.Inc(shd.CounterEmptyPops)
+			return zero, false
+		}
+
+		// 通过原子加法占用头分段中的一个槽位
+		// Claim one of the slots of the head segment with an atomic add
+		idx := atomic.AddInt64(&head.dequeue, 1) - 1
+
+		if idx < segmentSize {
+			// 把槽位标记为已取走。如果值已经发布，就取走它，否则这个槽位被作废，占用它的生产者会重试
+			// Mark the slot as taken. If the value has been published, take it, otherwise the slot is poisoned and the producer that claimed it tries again
+			slot := &head.slots[idx]
+			if atomic.SwapUint32(&slot.state, slotTaken) != slotFull {
+				q.stats.Inc(shd.CounterCASFailures)
+				q.backoff.Wait(&attempt)
+				continue
+			}
+			value := slot.value
+			slot.value = zero
+
+			// 减少队列的长度
+			// Decrease the length of the queue
+			atomic.AddInt64(&q.length, -1)
+			q.stats.Inc(shd.CounterPops)
+
+			// 返回值和 true
+			// Return the value and true
+			return value, true
+		}
+
+		// 头分段已经用完，没有下一个分段说明队列为空
+		// The head segment is used up, no next segment means the queue is empty
+		next := loadSegmentOf[T](&head.next)
+		if next == nil {
+			q.stats.Inc(shd.CounterEmptyPops)
+			return zero, false
+		}
+
+		// 尾指针可能还没有离开头分段，先帮助推进它，保证退休的分段不能再通过尾指针访问
+		// The tail pointer may not have left the head segment yet, help to advance it first, so a retired segment can no longer be reached through the tail pointer
+		atomic.CompareAndSwapPointer(&q.tail, unsafe.Pointer(head), unsafe.Pointer(next))
+
+		// 把头指针推进到下一个分段，成功的 goroutine 负责退休原来的头分段
+		// Advance the head pointer to the next segment, the goroutine that succeeds retires the old head segment
+		if atomic.CompareAndSwapPointer(&q.head, unsafe.Pointer(head), unsafe.Pointer(next)) {
+			q.reclaimer.Retire(unsafe.Pointer(head))
+		}
+	}
+}
+
+// Length 方法用于获取队列的长度
+// The Length method is used to get the length of the queue
+func (q *LockFreeSegmentedQueueOf[T]) Length() int64 {
+	return atomic.LoadInt64(&q.length)
+}
+
+// IsEmpty 方法用于判断队列是否为空
+// The IsEmpty method is used to determine whether the queue is empty
+func (q *LockFreeSegmentedQueueOf[T]) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+// Reset 方法用于重置队列，它会弹出并丢弃队列中所有的值，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the queue, it pops and discards all the values in the queue, and can be called concurrently with Push and Pop
+func (q *LockFreeSegmentedQueueOf[T]) Reset() {
+	for {
+		if _, ok := q.Pop(); !ok {
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeSegmentedQueueOf[T]) Stats() Stats {
+	return q.stats.Snapshot()
+}
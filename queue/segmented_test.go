@@ -0,0 +1,182 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeSegmentedQueue_Standard(t *testing.T) {
+	// Number of elements to test, it spans many segments
+	count := 100000
+
+	// The segmented queue is used through the same interface as the other queues
+	var q Queue = NewSegmented()
+
+	// Test enqueueing elements into the queue
+	for i := 0; i < count; i++ {
+		q.Push(i)
+	}
+	assert.Equal(t, int64(count), q.Length(), "Incorrect queue length. Expected %d, got %d", count, q.Length())
+
+	// Verify the elements in the queue
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, q.Pop(), "Incorrect value in the queue. Expected %d", i)
+	}
+	assert.Nil(t, q.Pop(), "Popped value from an empty queue")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreeSegmentedQueue_Interleaved(t *testing.T) {
+	q := NewSegmentedOf[int]()
+
+	// Keep a few elements in the queue while the head and tail move across segment boundaries
+	next := 0
+	for i := 0; i < segmentSize*5; i++ {
+		q.Push(i)
+		if i%3 == 2 {
+			for j := 0; j < 2; j++ {
+				v, ok := q.Pop()
+				assert.True(t, ok, "Failed to pop value")
+				assert.Equal(t, next, v, "Incorrect value in the queue. Expected %d, got %d", next, v)
+				next++
+			}
+		}
+	}
+	for v, ok := q.Pop(); ok; v, ok = q.Pop() {
+		assert.Equal(t, next, v, "Incorrect value in the queue. Expected %d, got %d", next, v)
+		next++
+	}
+	assert.Equal(t, segmentSize*5, next, "Incorrect number of values popped")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreeSegmentedQueue_NilAndReset(t *testing.T) {
+	q := NewSegmented()
+
+	// Test that nil values are dropped by default
+	q.Push(nil)
+	assert.True(t, q.IsEmpty(), "Nil value should be dropped")
+
+	// Test that nil values are kept with WithAcceptNil
+	q = NewSegmented(WithAcceptNil())
+	q.Push(nil)
+	v, ok := q.TryPop()
+	assert.True(t, ok, "Failed to pop the nil value")
+	assert.Nil(t, v, "Incorrect value popped")
+
+	// Test resetting the queue
+	for i := 0; i < segmentSize*2; i++ {
+		q.Push(i)
+	}
+	q.Reset()
+	assert.True(t, q.IsEmpty(), "Queue should be empty after reset")
+	_, ok = q.TryPop()
+	assert.False(t, ok, "Popped value from a reset queue")
+}
+
+func TestLockFreeSegmentedQueue_MPMCNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 4, 4, 50000
+	total := producers * perProducer
+	q := NewSegmentedOf[int]()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the consumers, they pop until every value has been seen. Values of the same producer must come out in order
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := make([]int, producers)
+			for i := range last {
+				last[i] = -1
+			}
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v, ok := q.Pop(); ok {
+					p, i := v%producers, v/producers
+					assert.Greater(t, i, last[p], "Values of producer %d popped out of order", p)
+					last[p] = i
+					atomic.AddInt32(&seen[v], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}()
+	}
+
+	// Start the producers, each pushes its own values in order
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(i*producers + p)
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeSegmentedQueue_ResetParallel(t *testing.T) {
+	q := NewSegmented()
+
+	wg := sync.WaitGroup{}
+
+	// Push, pop and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20000; j++ {
+				switch {
+				case i == 0 && j%1000 == 0:
+					q.Reset()
+				case i%2 == 0:
+					q.Pop()
+				default:
+					q.Push(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	q.Reset()
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+	assert.Nil(t, q.Pop(), "Popped value from a reset queue")
+}
+
+func TestLockFreeSegmentedQueue_Stats(t *testing.T) {
+	q := NewSegmentedOf[int](WithStats())
+
+	// Fill three segments, every new segment is taken from the segment pool
+	for i := 0; i < segmentSize*3; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < segmentSize*3; i++ {
+		q.Pop()
+	}
+	q.Pop()
+
+	s := q.Stats()
+	assert.Equal(t, uint64(segmentSize*3), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(segmentSize*3), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(3), s.PoolGets, "Incorrect number of segments taken from the pool")
+	assert.LessOrEqual(t, s.PoolMisses, s.PoolGets, "Pool misses should not exceed pool gets")
+}
@@ -0,0 +1,155 @@
+package queue
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeSPMCQueueOf 是一个泛型单生产者多消费者无锁队列结构体，T 为队列中元素的类型。
+// 尾节点只由唯一的生产者修改，Push 只需要一次原子写入来发布新节点，也不需要帮助推进落后的尾节点。
+// 消费者与 LockFreeQueueOf 一样通过 CAS 操作推进头节点。同一时刻最多只能有一个 goroutine 调用 Push
+// LockFreeSPMCQueueOf is a generic single-producer multi-consumer lock-free queue struct, T is the type of the elements in the queue.
+// The tail node is only modified by the single producer, so Push only needs a single atomic store to publish the new node, and nobody has to help a lagging tail node.
+// Consumers advance the head node with a CAS operation just like LockFreeQueueOf. At most one goroutine may call Push at the same time
+type LockFreeSPMCQueueOf[T any] struct {
+	// length 是队列的长度
+	// length is the length of the queue
+	length int64
+
+	// _ 用于把长度与头尾指针分隔到不同的缓存行
+	// _ separates the length from the head and tail pointers onto different cache lines
+	_ shd.CacheLinePad
+
+	// head 是指向队列头部哨兵节点的指针，消费者通过 CAS 操作修改它
+	// head is a pointer to the sentinel node at the head of the queue, consumers modify it with a CAS operation
+	head unsafe.Pointer
+
+	// _ 用于把消费者争用的 head 与生产者独占的 tail 分隔到不同的缓存行
+	// _ separates head, contended by the consumers, from tail, owned by the producer, onto different cache lines
+	_ shd.CacheLinePad
+
+	// tail 是指向队列尾部的指针，只由生产者访问
+	// tail is a pointer to the tail of the queue, it is only accessed by the producer
+	tail *shd.NodeOf[T]
+
+	// _ 用于把 tail 与之后只读的字段分隔到不同的缓存行
+	// _ separates tail from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// acceptNil 表示 Push 是否接受 nil 值，只在元素类型为 interface{} 的 LockFreeSPMCQueue 中使用
+	// acceptNil indicates whether Push accepts nil values, it is only used by LockFreeSPMCQueue whose element type is interface{}
+	acceptNil bool
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// NewSPMCOf 函数用于创建一个新的泛型 LockFreeSPMCQueueOf 队列，可以通过选项修改队列的行为
+// The NewSPMCOf function is used to create a new generic LockFreeSPMCQueueOf queue, the behavior of the queue can be modified with options
+func NewSPMCOf[T any](opts ...Option) *LockFreeSPMCQueueOf[T] {
+	// 创建一个值为 T 的零值的哨兵节点，队列的头节点和尾节点都指向它
+	// Create a sentinel node whose value is the zero value of T, both the head node and the tail node of the queue point to it
+	var zero T
+	stub := shd.NewNodeOf(zero)
+	q := &LockFreeSPMCQueueOf[T]{
+		head: unsafe.Pointer(stub),
+		tail: stub,
+	}
+
+	// 应用所有的选项
+	// Apply all the options
+	conf := newConfig(opts)
+	q.acceptNil = conf.acceptNil
+	q.backoff = conf.backoff
+	if conf.stats {
+		q.stats = shd.NewCounters()
+	}
+	return q
+}
+
+// Push 方法用于将一个值添加到队列的末尾。只能由唯一的生产者调用
+// The Push method is used to add a value to the end of the queue. It must only be called by the single producer
+func (q *LockFreeSPMCQueueOf[T]) Push(value T) {
+	node := shd.NewNodeOf(value)
+
+	// 增加队列的长度，必须在发布节点之前，否则长度可能短暂为负数
+	// Increase the length of the queue, this must happen before the node is published, otherwise the length could briefly be negative
+	atomic.AddInt64(&q.length, 1)
+
+	// 把新节点链接到尾节点之后，这一次原子写入同时发布了节点中的值
+	// Link the new node after the tail node, this single atomic store also publishes the value in the node
+	atomic.StorePointer(&q.tail.Next, unsafe.Pointer(node))
+	q.tail = node
+	q.stats.Inc(shd.CounterPushes)
+}
+
+// Pop 方法用于从队列的头部移除并返回一个值，如果队列为空，返回 T 的零值和 false。可以由任意多个消费者并发调用
+// The Pop method is used to remove and return a value from the head of the queue, returns the zero value of T and false if the queue is empty. It can be called concurrently by any number of consumers
+func (q *LockFreeSPMCQueueOf[T]) Pop() (T, bool) {
+	attempt := 0
+	for {
+		// 加载队列的头节点以及头节点的下一个节点，没有下一个节点说明队列为空
+		// Load the head node of the queue and the next node of the head node, no next node means the queue is empty
+		head := shd.LoadNodeOf[T](&q.head)
+		next := shd.LoadNodeOf[T](&head.Next)
+		if next == nil {
+			q.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+
+		// 先读取值，然后尝试将头节点设置为下一个节点。节点交给 GC 回收，不能清空它的值，因为其他消费者可能仍在读取它
+		// Read the value first, then try to set the head node to the next node. Nodes are left to the GC and their values must not be cleared, because other consumers may still be reading them
+		value := next.Value
+		if shd.CompareAndSwapNode(&q.head, head, next) {
+			// 减少队列的长度
+			// Decrease the length of the queue
+			atomic.AddInt64(&q.length, -1)
+			q.stats.Inc(shd.CounterPops)
+
+			// 返回值和 true
+			// Return the value and true
+			return value, true
+		}
+
+		// 其他消费者抢先弹出了头节点，记录一次 CAS 失败，退避之后重试
+		// Another consumer popped the head node first, count a CAS failure, back off and try again
+		q.stats.Inc(shd.CounterCASFailures)
+		q.backoff.Wait(&attempt)
+	}
+}
+
+// Length 方法用于获取队列的长度
+// The Length method is used to get the length of the queue
+func (q *LockFreeSPMCQueueOf[T]) Length() int64 {
+	return atomic.LoadInt64(&q.length)
+}
+
+// IsEmpty 方法用于判断队列是否为空
+// The IsEmpty method is used to determine whether the queue is empty
+func (q *LockFreeSPMCQueueOf[T]) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+// Reset 方法用于重置队列，它会弹出并丢弃队列中所有的值，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the queue, it pops and discards all the values in the queue, and can be called concurrently with Push and Pop
+func (q *LockFreeSPMCQueueOf[T]) Reset() {
+	for {
+		if _, ok := q.Pop(); !ok {
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeSPMCQueueOf[T]) Stats() Stats {
+	return q.stats.Snapshot()
+}
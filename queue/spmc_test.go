@@ -0,0 +1,109 @@
+package queue
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeSPMCQueue_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 100000
+
+	// The SPMC queue is used through the same interface as the MPMC one
+	var q Queue = NewSPMC()
+
+	// Test enqueueing elements into the queue
+	for i := 0; i < count; i++ {
+		q.Push(i)
+	}
+	assert.Equal(t, int64(count), q.Length(), "Incorrect queue length. Expected %d, got %d", count, q.Length())
+
+	// Verify the elements in the queue
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i, q.Pop(), "Incorrect value in the queue. Expected %d", i)
+	}
+	assert.Nil(t, q.Pop(), "Popped value from an empty queue")
+	assert.True(t, q.IsEmpty(), "Queue should be empty")
+}
+
+func TestLockFreeSPMCQueue_NilAndReset(t *testing.T) {
+	q := NewSPMC()
+
+	// Test that nil values are dropped by default
+	q.Push(nil)
+	assert.True(t, q.IsEmpty(), "Nil value should be dropped")
+
+	// Test that nil values are kept with WithAcceptNil
+	q = NewSPMC(WithAcceptNil())
+	q.Push(nil)
+	v, ok := q.TryPop()
+	assert.True(t, ok, "Failed to pop the nil value")
+	assert.Nil(t, v, "Incorrect value popped")
+
+	// Test resetting the queue
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	q.Reset()
+	assert.True(t, q.IsEmpty(), "Queue should be empty after reset")
+	_, ok = q.TryPop()
+	assert.False(t, ok, "Popped value from a reset queue")
+}
+
+func TestLockFreeSPMCQueue_ManyConsumers(t *testing.T) {
+	consumers, total := 8, 160000
+	q := NewSPMCOf[int]()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the consumers, they pop until every value has been seen
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v, ok := q.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}()
+	}
+
+	// The single producer pushes every value
+	for i := 0; i < total; i++ {
+		q.Push(i)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect queue length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeSPMCQueue_Stats(t *testing.T) {
+	q := NewSPMC(WithStats())
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 11; i++ {
+		q.Pop()
+	}
+
+	s := q.Stats()
+	assert.Equal(t, uint64(10), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(10), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+}
@@ -0,0 +1,325 @@
+package queue
+
+import (
+	"context"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeQueue 是一个无锁队列结构体，元素的类型为 interface{}
+// LockFreeQueue is a lock-free queue struct, the type of the elements is interface{}
+type LockFreeQueue LockFreeQueueOf[interface{}]
+
+// New 函数用于创建一个新的 LockFreeQueue 队列，可以通过选项修改队列的行为
+// The New function is used to create a new LockFreeQueue queue, the behavior of the queue can be modified with options
+func New(opts ...Option) *LockFreeQueue {
+	// 调用 newLFQ 函数创建一个新的 LockFreeQueue 队列，是否使用节点池由选项决定
+	// Call the newLFQ function to create a new LockFreeQueue queue, whether a node pool is used is decided by the options
+	return (*LockFreeQueue)(newLFQ[interface{}](opts))
+}
+
+// NewWithPool 函数用于创建一个新的 LockFreeQueue 队列，该队列使用一个节点池，可以通过选项修改队列的行为。
+// 它是 New 加上 WithPool 选项的简写
+// The NewWithPool function is used to create a new LockFreeQueue queue, this queue uses a node pool, the behavior of the queue can be modified with options.
+// It is a shortcut for New with the WithPool option
+func NewWithPool(opts ...Option) *LockFreeQueue {
+	return (*LockFreeQueue)(NewWithPoolOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeQueue 转换为底层的 LockFreeQueueOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeQueue to the underlying LockFreeQueueOf[interface{}] without any extra cost
+func (q *LockFreeQueue) of() *LockFreeQueueOf[interface{}] {
+	return (*LockFreeQueueOf[interface{}])(q)
+}
+
+// Push 方法用于将一个值添加到 LockFreeQueue 队列的末尾
+// The Push method is used to add a value to the end of the LockFreeQueue queue
+func (q *LockFreeQueue) Push(value interface{}) {
+	// 检查值是否为空, 如果为空并且没有启用 WithAcceptNil 选项，则直接返回
+	// Check if the value is nil, if it is and the WithAcceptNil option is not enabled, return directly
+	if value == nil && !q.acceptNil {
+		return
+	}
+
+	// 将值添加到底层的泛型队列中
+	// Add the value to the underlying generic queue
+	q.of().Push(value)
+}
+
+// Pop 方法用于从 LockFreeQueue 队列的头部移除并返回一个值，如果队列为空，返回 nil。启用 WithAcceptNil 选项时请使用 TryPop
+// The Pop method is used to remove and return a value from the head of the LockFreeQueue queue, returns nil if the queue is empty. Use TryPop when the WithAcceptNil option is enabled
+func (q *LockFreeQueue) Pop() interface{} {
+	// 从底层的泛型队列中弹出一个值，队列为空时该值为 nil
+	// Pop a value from the underlying generic queue, the value is nil when the queue is empty
+	value, _ := q.of().Pop()
+	return value
+}
+
+// TryPop 方法用于从 LockFreeQueue 队列的头部移除并返回一个值，第二个返回值表示是否弹出了元素，因此可以区分空队列和 nil 值
+// The TryPop method is used to remove and return a value from the head of the LockFreeQueue queue, the second return value reports whether an element was popped, so an empty queue can be told apart from a nil value
+func (q *LockFreeQueue) TryPop() (interface{}, bool) {
+	return q.of().Pop()
+}
+
+// PushBatch 方法用于将一组值按顺序添加到 LockFreeQueue 队列的末尾，其中的 nil 值会被忽略
+// The PushBatch method is used to add a group of values to the end of the LockFreeQueue queue in order, nil values are ignored
+func (q *LockFreeQueue) PushBatch(values []interface{}) {
+	// 没有启用 WithAcceptNil 选项时，去掉其中的 nil 值
+	// Remove the nil values when the WithAcceptNil option is not enabled
+	if !q.acceptNil {
+		values = shd.CompactNil(values)
+	}
+	q.of().PushBatch(values)
+}
+
+// PopBatch 方法用于从 LockFreeQueue 队列的头部移除最多 len(dst) 个值，并按顺序写入 dst，返回移除的值的数量
+// The PopBatch method is used to remove up to len(dst) values from the head of the LockFreeQueue queue and write them into dst in order, returning the number of values removed
+func (q *LockFreeQueue) PopBatch(dst []interface{}) int {
+	return q.of().PopBatch(dst)
+}
+
+// PopWait 方法用于从 LockFreeQueue 队列的头部移除并返回一个值，如果队列为空，会等待直到有值可用或者 ctx 结束
+// The PopWait method is used to remove and return a value from the head of the LockFreeQueue queue, if the queue is empty it waits until a value is available or ctx is done
+func (q *LockFreeQueue) PopWait(ctx context.Context) (interface{}, error) {
+	return q.of().PopWait(ctx)
+}
+
+// Peek 方法用于返回 LockFreeQueue 队列头部的值但不移除它，如果队列为空，返回 nil 和 false
+// The Peek method is used to return the value at the head of the LockFreeQueue queue without removing it, returns nil and false if the queue is empty
+func (q *LockFreeQueue) Peek() (interface{}, bool) {
+	return q.of().Peek()
+}
+
+// Range 方法用于按 FIFO 顺序遍历 LockFreeQueue 队列中的元素，但不移除它们，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Range method is used to walk the elements in the LockFreeQueue queue in FIFO order without removing them, the walk stops when fn returns false. The walk is weakly consistent
+func (q *LockFreeQueue) Range(fn func(value interface{}) bool) {
+	q.of().Range(fn)
+}
+
+// Snapshot 方法用于按 FIFO 顺序返回 LockFreeQueue 队列中所有元素的副本，但不移除它们
+// The Snapshot method is used to return a copy of all the elements in the LockFreeQueue queue in FIFO order without removing them
+func (q *LockFreeQueue) Snapshot() []interface{} {
+	return q.of().Snapshot()
+}
+
+// Length 方法用于获取 LockFreeQueue 队列的长度
+// The Length method is used to get the length of the LockFreeQueue queue
+func (q *LockFreeQueue) Length() int64 {
+	return q.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeQueue 队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeQueue queue is empty
+func (q *LockFreeQueue) IsEmpty() bool {
+	return q.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeQueue 队列，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeQueue queue, it can be called concurrently with Push and Pop
+func (q *LockFreeQueue) Reset() {
+	q.of().Reset()
+}
+
+// Drain 方法用于原子地摘下 LockFreeQueue 队列中的所有值，并按 FIFO 顺序返回，队列为空时返回 nil
+// The Drain method is used to atomically detach all the values in the LockFreeQueue queue and return them in FIFO order, nil is returned when the queue is empty
+func (q *LockFreeQueue) Drain() []interface{} {
+	return q.of().Drain()
+}
+
+// Stats 方法用于返回 LockFreeQueue 队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeQueue queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeQueue) Stats() Stats {
+	return q.of().Stats()
+}
+
+// LockFreeMPSCQueue 是一个多生产者单消费者无锁队列结构体，元素的类型为 interface{}
+// LockFreeMPSCQueue is a multi-producer single-consumer lock-free queue struct, the type of the elements is interface{}
+type LockFreeMPSCQueue LockFreeMPSCQueueOf[interface{}]
+
+// NewMPSC 函数用于创建一个新的 LockFreeMPSCQueue 队列，同一时刻最多只能有一个消费者，可以通过选项修改队列的行为
+// The NewMPSC function is used to create a new LockFreeMPSCQueue queue, there may be at most one consumer at the same time, the behavior of the queue can be modified with options
+func NewMPSC(opts ...Option) *LockFreeMPSCQueue {
+	return (*LockFreeMPSCQueue)(NewMPSCOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeMPSCQueue 转换为底层的 LockFreeMPSCQueueOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeMPSCQueue to the underlying LockFreeMPSCQueueOf[interface{}] without any extra cost
+func (q *LockFreeMPSCQueue) of() *LockFreeMPSCQueueOf[interface{}] {
+	return (*LockFreeMPSCQueueOf[interface{}])(q)
+}
+
+// Push 方法用于将一个值添加到 LockFreeMPSCQueue 队列的末尾，可以由任意多个生产者并发调用
+// The Push method is used to add a value to the end of the LockFreeMPSCQueue queue, it can be called concurrently by any number of producers
+func (q *LockFreeMPSCQueue) Push(value interface{}) {
+	// 检查值是否为空, 如果为空并且没有启用 WithAcceptNil 选项，则直接返回
+	// Check if the value is nil, if it is and the WithAcceptNil option is not enabled, return directly
+	if value == nil && !q.acceptNil {
+		return
+	}
+	q.of().Push(value)
+}
+
+// Pop 方法用于从 LockFreeMPSCQueue 队列的头部移除并返回一个值，如果队列为空，返回 nil。只能由唯一的消费者调用
+// The Pop method is used to remove and return a value from the head of the LockFreeMPSCQueue queue, returns nil if the queue is empty. It must only be called by the single consumer
+func (q *LockFreeMPSCQueue) Pop() interface{} {
+	value, _ := q.of().Pop()
+	return value
+}
+
+// TryPop 方法用于从 LockFreeMPSCQueue 队列的头部移除并返回一个值，第二个返回值表示是否弹出了元素。只能由唯一的消费者调用
+// The TryPop method is used to remove and return a value from the head of the LockFreeMPSCQueue queue, the second return value reports whether an element was popped. It must only be called by the single consumer
+func (q *LockFreeMPSCQueue) TryPop() (interface{}, bool) {
+	return q.of().Pop()
+}
+
+// Length 方法用于获取 LockFreeMPSCQueue 队列的长度
+// The Length method is used to get the length of the LockFreeMPSCQueue queue
+func (q *LockFreeMPSCQueue) Length() int64 {
+	return q.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeMPSCQueue 队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeMPSCQueue queue is empty
+func (q *LockFreeMPSCQueue) IsEmpty() bool {
+	return q.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeMPSCQueue 队列，只能由唯一的消费者调用
+// The Reset method is used to reset the LockFreeMPSCQueue queue, it must only be called by the single consumer
+func (q *LockFreeMPSCQueue) Reset() {
+	q.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeMPSCQueue 队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeMPSCQueue queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeMPSCQueue) Stats() Stats {
+	return q.of().Stats()
+}
+
+// LockFreeSPMCQueue 是一个单生产者多消费者无锁队列结构体，元素的类型为 interface{}
+// LockFreeSPMCQueue is a single-producer multi-consumer lock-free queue struct, the type of the elements is interface{}
+type LockFreeSPMCQueue LockFreeSPMCQueueOf[interface{}]
+
+// NewSPMC 函数用于创建一个新的 LockFreeSPMCQueue 队列，同一时刻最多只能有一个生产者，可以通过选项修改队列的行为
+// The NewSPMC function is used to create a new LockFreeSPMCQueue queue, there may be at most one producer at the same time, the behavior of the queue can be modified with options
+func NewSPMC(opts ...Option) *LockFreeSPMCQueue {
+	return (*LockFreeSPMCQueue)(NewSPMCOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeSPMCQueue 转换为底层的 LockFreeSPMCQueueOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeSPMCQueue to the underlying LockFreeSPMCQueueOf[interface{}] without any extra cost
+func (q *LockFreeSPMCQueue) of() *LockFreeSPMCQueueOf[interface{}] {
+	return (*LockFreeSPMCQueueOf[interface{}])(q)
+}
+
+// Push 方法用于将一个值添加到 LockFreeSPMCQueue 队列的末尾。只能由唯一的生产者调用
+// The Push method is used to add a value to the end of the LockFreeSPMCQueue queue. It must only be called by the single producer
+func (q *LockFreeSPMCQueue) Push(value interface{}) {
+	// 检查值是否为空, 如果为空并且没有启用 WithAcceptNil 选项，则直接返回
+	// Check if the value is nil, if it is and the WithAcceptNil option is not enabled, return directly
+	if value == nil && !q.acceptNil {
+		return
+	}
+	q.of().Push(value)
+}
+
+// Pop 方法用于从 LockFreeSPMCQueue 队列的头部移除并返回一个值，如果队列为空，返回 nil。可以由任意多个消费者并发调用
+// The Pop method is used to remove and return a value from the head of the LockFreeSPMCQueue queue, returns nil if the queue is empty. It can be called concurrently by any number of consumers
+func (q *LockFreeSPMCQueue) Pop() interface{} {
+	value, _ := q.of().Pop()
+	return value
+}
+
+// TryPop 方法用于从 LockFreeSPMCQueue 队列的头部移除并返回一个值，第二个返回值表示是否弹出了元素
+// The TryPop method is used to remove and return a value from the head of the LockFreeSPMCQueue queue, the second return value reports whether an element was popped
+func (q *LockFreeSPMCQueue) TryPop() (interface{}, bool) {
+	return q.of().Pop()
+}
+
+// Length 方法用于获取 LockFreeSPMCQueue 队列的长度
+// The Length method is used to get the length of the LockFreeSPMCQueue queue
+func (q *LockFreeSPMCQueue) Length() int64 {
+	return q.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeSPMCQueue 队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeSPMCQueue queue is empty
+func (q *LockFreeSPMCQueue) IsEmpty() bool {
+	return q.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeSPMCQueue 队列，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeSPMCQueue queue, it can be called concurrently with Push and Pop
+func (q *LockFreeSPMCQueue) Reset() {
+	q.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeSPMCQueue 队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeSPMCQueue queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeSPMCQueue) Stats() Stats {
+	return q.of().Stats()
+}
+
+// LockFreeSegmentedQueue 是一个无界分段无锁队列结构体，元素的类型为 interface{}
+// LockFreeSegmentedQueue is an unbounded segmented lock-free queue struct, the type of the elements is interface{}
+type LockFreeSegmentedQueue LockFreeSegmentedQueueOf[interface{}]
+
+// NewSegmented 函数用于创建一个新的 LockFreeSegmentedQueue 队列，可以通过选项修改队列的行为
+// The NewSegmented function is used to create a new LockFreeSegmentedQueue queue, the behavior of the queue can be modified with options
+func NewSegmented(opts ...Option) *LockFreeSegmentedQueue {
+	return (*LockFreeSegmentedQueue)(NewSegmentedOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeSegmentedQueue 转换为底层的 LockFreeSegmentedQueueOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeSegmentedQueue to the underlying LockFreeSegmentedQueueOf[interface{}] without any extra cost
+func (q *LockFreeSegmentedQueue) of() *LockFreeSegmentedQueueOf[interface{}] {
+	return (*LockFreeSegmentedQueueOf[interface{}])(q)
+}
+
+// Push 方法用于将一个值添加到 LockFreeSegmentedQueue 队列的末尾
+// The Push method is used to add a value to the end of the LockFreeSegmentedQueue queue
+func (q *LockFreeSegmentedQueue) Push(value interface{}) {
+	// 检查值是否为空, 如果为空并且没有启用 WithAcceptNil 选项，则直接返回
+	// Check if the value is nil, if it is and the WithAcceptNil option is not enabled, return directly
+	if value == nil && !q.acceptNil {
+		return
+	}
+	q.of().Push(value)
+}
+
+// Pop 方法用于从 LockFreeSegmentedQueue 队列的头部移除并返回一个值，如果队列为空，返回 nil。启用 WithAcceptNil 选项时请使用 TryPop
+// The Pop method is used to remove and return a value from the head of the LockFreeSegmentedQueue queue, returns nil if the queue is empty. Use TryPop when the WithAcceptNil option is enabled
+func (q *LockFreeSegmentedQueue) Pop() interface{} {
+	value, _ := q.of().Pop()
+	return value
+}
+
+// TryPop 方法用于从 LockFreeSegmentedQueue 队列的头部移除并返回一个值，第二个返回值表示是否弹出了元素
+// The TryPop method is used to remove and return a value from the head of the LockFreeSegmentedQueue queue, the second return value reports whether an element was popped
+func (q *LockFreeSegmentedQueue) TryPop() (interface{}, bool) {
+	return q.of().Pop()
+}
+
+// Length 方法用于获取 LockFreeSegmentedQueue 队列的长度
+// The Length method is used to get the length of the LockFreeSegmentedQueue queue
+func (q *LockFreeSegmentedQueue) Length() int64 {
+	return q.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeSegmentedQueue 队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeSegmentedQueue queue is empty
+func (q *LockFreeSegmentedQueue) IsEmpty() bool {
+	return q.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeSegmentedQueue 队列，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeSegmentedQueue queue, it can be called concurrently with Push and Pop
+func (q *LockFreeSegmentedQueue) Reset() {
+	q.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeSegmentedQueue 队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeSegmentedQueue queue, the zero value is returned when the WithStats option is not enabled
+func (q *LockFreeSegmentedQueue) Stats() Stats {
+	return q.of().Stats()
+}
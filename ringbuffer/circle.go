@@ -1,8 +1,10 @@
 package ringbuffer
 
 import (
+	"context"
+	"math/bits"
 	"sync/atomic"
-	"unsafe"
+	"time"
 
 	shd "github.com/shengyanli1982/lockfree/internal/shared"
 )
@@ -11,212 +13,674 @@ import (
 // DefaultCircleBufferSize is the default size of the ring buffer
 const DefaultCircleBufferSize = 1024
 
-// LockFreeRingBuffer 是一个无锁环形缓冲区的结构体
-// LockFreeRingBuffer is a structure of a lock-free ring buffer
-type LockFreeRingBuffer struct {
+// slotOf 是环形缓冲区中的一个槽位
+// slotOf is a slot of the ring buffer
+type slotOf[T any] struct {
+	// sequence 是槽位的序号。对于位置 pos，记 round 为 pos 所在的轮次 (pos / capacity)，序号等于 2*round 时表示槽位可写，等于 2*round+1 时表示槽位中的值已经发布，可以读取。
+	// 值直接保存在槽位中，槽位的大小取决于 T，使用 atomic.Int64 保证每个槽位中的序号在 32 位平台上也按 8 字节对齐
+	// sequence is the sequence number of the slot. For position pos, let round be the round of pos (pos / capacity), the slot is writable when the sequence equals 2*round, and the value in the slot has been published and is readable when it equals 2*round+1.
+	// The value is stored inline, so the size of a slot depends on T, atomic.Int64 keeps the sequence of every slot 8-byte aligned on 32-bit platforms as well
+	sequence atomic.Int64
+
+	// value 是直接保存在槽位中的值，访问它不需要额外的指针跳转，推入和弹出也不需要分配内存
+	// value is the value stored inline in the slot, accessing it needs no extra pointer chase, and pushing or popping needs no memory allocation
+	value T
+}
+
+// LockFreeRingBufferOf 是一个泛型无锁环形缓冲区的结构体，T 为缓冲区中元素的类型。
+// 它是一个基于槽位序号的有界多生产者多消费者队列 (Vyukov)，每个值只会被发布一次，并且不会在发布之前被读取。
+// LockFreeRingBufferOf is a structure of a generic lock-free ring buffer, T is the type of the elements in the buffer.
+// It is a bounded multi-producer multi-consumer queue based on per-slot sequence numbers (Vyukov), each value is published exactly once and never read before it is published.
+type LockFreeRingBufferOf[T any] struct {
+	// head 是环形缓冲区的头部位置，单调递增的 64 位整数，永远不会回绕。它是第一个字段，保证在 32 位平台上也按 8 字节对齐
+	// head is the head position of the ring buffer, a monotonically increasing 64-bit integer that never wraps around. It is the first field so it is 8-byte aligned on 32-bit platforms as well
+	head int64
+
+	// _ 用于把消费者修改的 head 和生产者修改的 tail 分隔到不同的缓存行，避免伪共享
+	// _ separates head, modified by consumers, from tail, modified by producers, onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
+	// tail 是环形缓冲区的尾部位置，单调递增的 64 位整数，永远不会回绕。tail - head 就是缓冲区中的元素数量
+	// tail is the tail position of the ring buffer, a monotonically increasing 64-bit integer that never wraps around. tail - head is the number of elements in the buffer
+	tail int64
+
+	// _ 用于把 tail 与之后只读的字段分隔到不同的缓存行
+	// _ separates tail from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
 	// capacity 是环形缓冲区的容量
 	// capacity is the capacity of the ring buffer
 	capacity int64
 
-	// head 是环形缓冲区的头部索引
-	// head is the head index of the ring buffer
-	head int64
+	// mask 是容量为 2 的幂时用于计算槽位下标的掩码，shift 是用于计算轮次的位移量
+	// mask is the mask used to compute the slot index when the capacity is a power of two, shift is the shift used to compute the round
+	mask  int64
+	shift int
 
-	// tail 是环形缓冲区的尾部索引
-	// tail is the tail index of the ring buffer
-	tail int64
+	// pow2 表示容量是否为 2 的幂，此时使用位运算代替取模和除法
+	// pow2 indicates whether the capacity is a power of two, bitwise operations are used instead of modulo and division in that case
+	pow2 bool
+
+	// data 是用于存储元素的槽位切片
+	// data is a slice of slots used to store elements
+	data []slotOf[T]
 
-	// count 是环形缓冲区中的元素数量
-	// count is the number of elements in the ring buffer
-	count int64
+	// overwrite 表示缓冲区已满时，Push 是否淘汰最旧的元素
+	// overwrite indicates whether Push evicts the oldest element when the buffer is full
+	overwrite bool
 
-	// data 是用于存储元素的切片
-	// data is a slice used to store elements
-	data []unsafe.Pointer
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+
+	// notEmpty 用于挂起等待数据的 goroutine，在推入数据后唤醒它们
+	// notEmpty is used to park goroutines waiting for data, they are woken up after data is pushed
+	notEmpty shd.Waiter
+
+	// notFull 用于挂起等待空闲槽位的 goroutine，在弹出数据后唤醒它们
+	// notFull is used to park goroutines waiting for a free slot, they are woken up after data is popped
+	notFull shd.Waiter
 }
 
-// New 是一个函数，用于创建一个新的 LockFreeRingBuffer 实例
-// New is a function that creates a new instance of LockFreeRingBuffer
-func New(capacity int) *LockFreeRingBuffer {
+// NewOf 是一个函数，用于创建一个新的泛型 LockFreeRingBufferOf 实例，可以通过选项修改缓冲区的行为
+// NewOf is a function that creates a new instance of the generic LockFreeRingBufferOf, the behavior of the buffer can be modified with options
+func NewOf[T any](capacity int, opts ...Option) *LockFreeRingBufferOf[T] {
 	// 如果传入的容量小于或等于 0，那么将容量设置为默认的环形缓冲区大小
 	// If the passed in capacity is less than or equal to 0, then set the capacity to the default ring buffer size
 	if capacity <= 0 {
 		capacity = DefaultCircleBufferSize
 	}
 
-	// 创建一个新的 LockFreeRingBuffer 实例
-	// Create a new instance of LockFreeRingBuffer
-	rb := &LockFreeRingBuffer{
-		// 使用 make 函数创建一个长度和容量都为 capacity 的切片
-		// Create a slice with length and capacity both equal to capacity using the make function
-		data: make([]unsafe.Pointer, capacity),
+	// 应用所有的选项
+	// Apply all the options
+	conf := newConfig(opts)
+
+	// 如果启用了 WithPowerOfTwo 选项，那么将容量向上取整为 2 的幂
+	// If the WithPowerOfTwo option is enabled, then round the capacity up to a power of two
+	if conf.powerOfTwo {
+		capacity = roundUpPowerOfTwo(capacity)
+	}
+
+	// 创建一个新的 LockFreeRingBufferOf 实例
+	// Create a new instance of LockFreeRingBufferOf
+	rb := &LockFreeRingBufferOf[T]{
+		// 使用 make 函数创建一个长度和容量都为 capacity 的槽位切片
+		// Create a slice of slots with length and capacity both equal to capacity using the make function
+		data: make([]slotOf[T], capacity),
 
 		// 设置环形缓冲区的容量为 capacity
 		// Set the capacity of the ring buffer to capacity
 		capacity: int64(capacity),
 
-		// 设置环形缓冲区的头部索引为 0
-		// Set the head index of the ring buffer to 0
-		head: 0,
+		// 设置缓冲区已满时是否淘汰最旧的元素
+		// Set whether to evict the oldest element when the buffer is full
+		overwrite: conf.overwrite,
 
-		// 设置环形缓冲区的尾部索引为 0
-		// Set the tail index of the ring buffer to 0
-		tail: 0,
+		// 设置 CAS 失败之后的退避策略
+		// Set the backoff strategy after a failed CAS
+		backoff: conf.backoff,
+	}
 
-		// 设置环形缓冲区的元素数量为 0
-		// Set the number of elements in the ring buffer to 0
-		count: 0,
+	// 如果启用了统计，那么创建统计计数器
+	// If statistics are enabled, then create the statistics counters
+	if conf.stats {
+		rb.stats = shd.NewCounters()
 	}
 
-	// 使用 for 循环初始化环形缓冲区的每个元素为一个新的节点，节点的值为 EmptyValue
-	// Use a for loop to initialize each element of the ring buffer to a new node with a value of EmptyValue
-	for i := 0; i < capacity; i++ {
-		rb.data[i] = unsafe.Pointer(shd.NewNode(nil))
+	// 如果容量是 2 的幂，那么使用掩码和位移计算槽位下标和轮次
+	// If the capacity is a power of two, then compute the slot index and the round with a mask and a shift
+	if capacity&(capacity-1) == 0 {
+		rb.pow2 = true
+		rb.mask = int64(capacity - 1)
+		rb.shift = bits.TrailingZeros64(uint64(capacity))
 	}
 
-	// 返回新创建的 LockFreeRingBuffer 实例
-	// Return the newly created LockFreeRingBuffer instance
+	// 返回新创建的 LockFreeRingBufferOf 实例
+	// Return the newly created LockFreeRingBufferOf instance
 	return rb
 }
 
+// roundUpPowerOfTwo 函数用于把 n 向上取整为 2 的幂
+// The roundUpPowerOfTwo function is used to round n up to a power of two
+func roundUpPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(uint64(n-1))
+}
+
+// slot 方法用于返回位置 pos 对应的槽位
+// The slot method is used to return the slot of position pos
+func (r *LockFreeRingBufferOf[T]) slot(pos int64) *slotOf[T] {
+	if r.pow2 {
+		return &r.data[pos&r.mask]
+	}
+	return &r.data[pos%r.capacity]
+}
+
+// round 方法用于返回位置 pos 所在的轮次
+// The round method is used to return the round of position pos
+func (r *LockFreeRingBufferOf[T]) round(pos int64) int64 {
+	if r.pow2 {
+		return pos >> r.shift
+	}
+	return pos / r.capacity
+}
+
 // IsEmpty 是一个方法，用于检查环形缓冲区是否为空
 // IsEmpty is a method that checks whether the ring buffer is empty
-func (r *LockFreeRingBuffer) IsEmpty() bool {
+func (r *LockFreeRingBufferOf[T]) IsEmpty() bool {
 	return r.Count() == 0
 }
 
 // IsFull 是一个方法，用于检查环形缓冲区是否已满
 // IsFull is a method that checks whether the ring buffer is full
-func (r *LockFreeRingBuffer) IsFull() bool {
+func (r *LockFreeRingBufferOf[T]) IsFull() bool {
 	return r.Count() == r.capacity
 }
 
 // Capacity 是一个方法，返回环形缓冲区的容量
 // Capacity is a method that returns the capacity of the ring buffer
-func (r *LockFreeRingBuffer) Capacity() int64 {
+func (r *LockFreeRingBufferOf[T]) Capacity() int64 {
 	return r.capacity
 }
 
 // Count 是一个方法，返回环形缓冲区中的元素数量
 // Count is a method that returns the number of elements in the ring buffer
-func (r *LockFreeRingBuffer) Count() int64 {
-	return atomic.LoadInt64(&r.count)
+func (r *LockFreeRingBufferOf[T]) Count() int64 {
+	// 先读取头部位置再读取尾部位置，头部位置不会越过尾部位置，因此数量不会为负数
+	// Load the head position before the tail position, the head position never passes the tail position, so the number is never negative
+	head := atomic.LoadInt64(&r.head)
+	count := atomic.LoadInt64(&r.tail) - head
+
+	// 两次读取之间消费者可能已经推进了头部位置，数量不会超过容量
+	// The consumers may have advanced the head position between the two loads, the number never exceeds the capacity
+	if count > r.capacity {
+		count = r.capacity
+	}
+	return count
 }
 
-// Reset 是一个方法，用于重置环形缓冲区
-// Reset is a method that resets the ring buffer
-func (r *LockFreeRingBuffer) Reset() {
-	// 使用 for 循环遍历环形缓冲区的每个元素
-	// Use a for loop to traverse each element of the ring buffer
+// Reset 是一个方法，用于重置环形缓冲区。它不能与 Push 和 Pop 并发调用
+// Reset is a method that resets the ring buffer. It must not be called concurrently with Push and Pop
+func (r *LockFreeRingBufferOf[T]) Reset() {
+	// 使用 for 循环清空每个槽位的值并重置序号，序号为 0 表示第一轮可写
+	// Use a for loop to clear the value and reset the sequence number of each slot, a sequence number of 0 means writable in the first round
+	var zero T
 	for i := int64(0); i < r.capacity; i++ {
-		// 使用 atomic.LoadPointer 函数获取当前元素的指针
-		// Use the atomic.LoadPointer function to get the pointer of the current element
-		ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&r.data[i])))
-
-		// 如果当前元素的指针不为空
-		// If the pointer of the current element is not null
-		if ptr != unsafe.Pointer(nil) {
-			// 使用 LoadNode 方法获取节点，并调用 ResetAll 方法重置节点
-			// Use the LoadNode method to get the node and call the ResetAll method to reset the node
-			shd.LoadNode(&ptr).ResetAll()
-		}
+		r.data[i].value = zero
+		r.data[i].sequence.Store(0)
 	}
 
-	// 使用 atomic.StoreInt64 函数将环形缓冲区的头部索引、尾部索引和元素数量都设置为 0
-	// Use the atomic.StoreInt64 function to set the head index, tail index, and number of elements in the ring buffer all to 0
+	// 使用 atomic.StoreInt64 函数将环形缓冲区的头部位置和尾部位置都设置为 0
+	// Use the atomic.StoreInt64 function to set the head position and tail position of the ring buffer both to 0
 	atomic.StoreInt64(&r.head, 0)
 	atomic.StoreInt64(&r.tail, 0)
-	atomic.StoreInt64(&r.count, 0)
 }
 
-// Push 方法用于向无锁环形缓冲区中推入一个元素
-// The Push method is used to push an element into the lock-free ring buffer
-func (r *LockFreeRingBuffer) Push(value interface{}) bool {
-	// 使用无限循环，直到成功推入元素
-	// Use an infinite loop until an element is successfully pushed
+// Push 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，返回 false。
+// 如果启用了 WithOverwrite 选项，缓冲区已满时会淘汰最旧的元素，并且总是返回 true
+// The Push method is used to push an element into the lock-free ring buffer, returns false if the buffer is full.
+// If the WithOverwrite option is enabled, the oldest element is evicted when the buffer is full and true is always returned
+func (r *LockFreeRingBufferOf[T]) Push(value T) bool {
+	_, _, ok := r.push(value, r.overwrite)
+	return ok
+}
+
+// PushOverwrite 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，淘汰最旧的元素，并返回被淘汰的元素和 true。
+// 没有元素被淘汰时，返回 T 的零值和 false。淘汰与写入是同一次操作，每个被淘汰的元素只会被报告一次
+// The PushOverwrite method is used to push an element into the lock-free ring buffer, if the buffer is full the oldest element is evicted and returned together with true.
+// When no element is evicted it returns the zero value of T and false. Eviction and write are one operation, each evicted element is reported exactly once
+func (r *LockFreeRingBufferOf[T]) PushOverwrite(value T) (T, bool) {
+	evicted, ok, _ := r.push(value, true)
+	return evicted, ok
+}
+
+// push 方法用于向无锁环形缓冲区中推入一个元素，overwrite 为 true 时缓冲区已满会淘汰最旧的元素。
+// 返回被淘汰的元素、是否有元素被淘汰，以及是否推入成功
+// The push method is used to push an element into the lock-free ring buffer, the oldest element is evicted on a full buffer when overwrite is true.
+// It returns the evicted element, whether an element was evicted, and whether the push succeeded
+func (r *LockFreeRingBufferOf[T]) push(value T, overwrite bool) (T, bool, bool) {
+	var zero T
+
+	attempt := 0
+
+	// 使用无限循环，直到成功推入元素或者缓冲区已满
+	// Use an infinite loop until an element is successfully pushed or the buffer is full
 	for {
-		// 如果缓冲区已满，返回 false
-		// If the buffer is full, return false
-		if r.IsFull() {
-			return false
+		// 获取尾部位置以及对应的槽位
+		// Get the tail position and the corresponding slot
+		tail := atomic.LoadInt64(&r.tail)
+		slot := r.slot(tail)
+
+		// 比较槽位的序号和尾部位置所在轮次的可写序号
+		// Compare the sequence number of the slot with the writable sequence number of the round of the tail position
+		round := r.round(tail)
+		diff := slot.sequence.Load() - round*2
+
+		if diff == 0 {
+			// 槽位可写，使用 CAS 操作尝试占用该位置
+			// The slot is writable, use CAS operation to try to claim the position
+			if atomic.CompareAndSwapInt64(&r.tail, tail, tail+1) {
+				// 写入值，然后通过更新序号发布该值
+				// Write the value, then publish it by updating the sequence number
+				slot.value = value
+				slot.sequence.Store(round*2 + 1)
+				r.stats.Inc(shd.CounterPushes)
+
+				// 唤醒等待数据的 goroutine
+				// Wake up the goroutines waiting for data
+				r.notEmpty.Notify()
+
+				// 返回 true，表示成功推入元素
+				// Return true, indicating that the element was successfully pushed
+				return zero, false, true
+			}
+
+			// 其他生产者抢先推进了尾部位置，记录一次 CAS 失败，退避之后重试
+			// Another producer advanced the tail position first, count a CAS failure, back off and try again
+			r.stats.Inc(shd.CounterCASFailures)
+			r.backoff.Wait(&attempt)
+		} else if diff < 0 {
+			// 槽位中的值还没有被消费，缓冲区已满，如果不淘汰元素，返回 false
+			// The value in the slot has not been consumed yet, the buffer is full, return false if no element is evicted
+			if !overwrite {
+				r.stats.Inc(shd.CounterFullRejections)
+				return zero, false, false
+			}
+
+			// 槽位中是上一轮已经发布的最旧的元素，并且它位于头部位置，使用 CAS 操作尝试从消费者手中占用该位置
+			// The slot holds the oldest element published in the previous round and it is at the head position, use CAS operation to try to claim the position from the consumers
+			oldest := tail - r.capacity
+			if diff == -1 && atomic.LoadInt64(&r.head) == oldest && atomic.CompareAndSwapInt64(&r.head, oldest, oldest+1) {
+				// 槽位不可写，其他生产者无法推进尾部位置，这里的 CAS 操作一定会成功
+				// The slot is not writable, other producers cannot advance the tail position, so this CAS operation always succeeds
+				atomic.CompareAndSwapInt64(&r.tail, tail, tail+1)
+
+				// 取出被淘汰的元素，写入新的值，然后直接发布为本轮可读
+				// Take the evicted element, write the new value, then publish it directly as readable in this round
+				evicted := slot.value
+				slot.value = value
+				slot.sequence.Store(round*2 + 1)
+				r.stats.Inc(shd.CounterPushes)
+
+				// 唤醒等待数据的 goroutine
+				// Wake up the goroutines waiting for data
+				r.notEmpty.Notify()
+
+				// 返回被淘汰的元素
+				// Return the evicted element
+				return evicted, true, true
+			}
 		}
 
-		// 获取尾部元素的位置
-		// Get the position of the tail element
-		tail := atomic.LoadInt64(&r.tail)
+		// 尾部位置已经被其他生产者推进，重试
+		// The tail position has been advanced by another producer, retry
+	}
+}
+
+// Pop 方法用于从无锁环形缓冲区中弹出一个元素，如果缓冲区为空，返回 T 的零值和 false
+// The Pop method is used to pop an element from the lock-free ring buffer, returns the zero value of T and false if the buffer is empty
+func (r *LockFreeRingBufferOf[T]) Pop() (T, bool) {
+	attempt := 0
+
+	// 使用无限循环，直到成功弹出元素或者缓冲区为空
+	// Use an infinite loop until an element is successfully popped or the buffer is empty
+	for {
+		// 获取头部位置以及对应的槽位
+		// Get the head position and the corresponding slot
+		head := atomic.LoadInt64(&r.head)
+		slot := r.slot(head)
+
+		// 比较槽位的序号和头部位置所在轮次的可读序号
+		// Compare the sequence number of the slot with the readable sequence number of the round of the head position
+		round := r.round(head)
+		diff := slot.sequence.Load() - (round*2 + 1)
+
+		if diff == 0 {
+			// 槽位中的值已经发布，使用 CAS 操作尝试占用该位置
+			// The value in the slot has been published, use CAS operation to try to claim the position
+			if atomic.CompareAndSwapInt64(&r.head, head, head+1) {
+				// 读取值并清空槽位
+				// Read the value and clear the slot
+				value := slot.value
+				var zero T
+				slot.value = zero
+
+				// 通过更新序号把槽位交还给下一轮的生产者
+				// Hand the slot back to the producer of the next round by updating the sequence number
+				slot.sequence.Store(round*2 + 2)
+				r.stats.Inc(shd.CounterPops)
+
+				// 唤醒等待空闲槽位的 goroutine
+				// Wake up the goroutines waiting for a free slot
+				r.notFull.Notify()
+
+				// 返回值和 true
+				// Return the value and true
+				return value, true
+			}
+
+			// 其他消费者抢先推进了头部位置，记录一次 CAS 失败，退避之后重试
+			// Another consumer advanced the head position first, count a CAS failure, back off and try again
+			r.stats.Inc(shd.CounterCASFailures)
+			r.backoff.Wait(&attempt)
+		} else if diff < 0 {
+			// 槽位中的值还没有发布，缓冲区为空，返回 T 的零值和 false
+			// The value in the slot has not been published yet, the buffer is empty, return the zero value of T and false
+			r.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
 
-		// 计算下一个元素的位置
-		// Calculate the position of the next element
-		next := (tail + 1) % r.capacity
+		// 头部位置已经被其他消费者推进，重试
+		// The head position has been advanced by another consumer, retry
+	}
+}
 
-		// 获取尾部元素的指针
-		// Get the pointer of the tail element
-		ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&r.data[tail])))
+// PushBatch 方法用于向无锁环形缓冲区中按顺序推入一组元素，返回成功推入的元素数量。
+// 连续的可写槽位通过一次 CAS 操作占用，缓冲区空间不足时只推入前面的一部分元素。
+// 如果启用了 WithOverwrite 选项，剩余的元素会淘汰最旧的元素后推入，因此总是返回 len(values)
+// The PushBatch method is used to push a group of elements into the lock-free ring buffer in order, returning the number of elements pushed.
+// Consecutive writable slots are claimed with a single CAS operation, only the leading part of the elements is pushed when the buffer does not have enough room.
+// If the WithOverwrite option is enabled, the remaining elements are pushed by evicting the oldest elements, so len(values) is always returned
+func (r *LockFreeRingBufferOf[T]) PushBatch(values []T) int {
+	attempt := 0
+
+	// 使用无限循环，直到成功占用槽位或者缓冲区已满
+	// Use an infinite loop until slots are successfully claimed or the buffer is full
+	for len(values) > 0 {
+		// 获取尾部位置
+		// Get the tail position
+		tail := atomic.LoadInt64(&r.tail)
 
-		// 使用 CAS 操作尝试修改尾部元素的位置, 并且尾部元素的指针不为空
-		// Use CAS operation to try to modify the position of the tail element, and the pointer of the tail element is not null
-		if atomic.CompareAndSwapInt64(&r.tail, tail, next) && ptr != unsafe.Pointer(nil) {
-			// 缓冲区的元素数量加 1
-			// The number of elements in the buffer is increased by 1
-			atomic.AddInt64(&r.count, 1)
+		// 从尾部位置开始统计连续的可写槽位，最多 len(values) 个
+		// Count the consecutive writable slots starting from the tail position, at most len(values)
+		n := int64(0)
+		for n < int64(len(values)) {
+			pos := tail + n
+			if r.slot(pos).sequence.Load() != r.round(pos)*2 {
+				break
+			}
+			n++
+		}
 
-			// 修改尾部元素的值
-			// Modify the value of the tail element
-			shd.LoadNode(&ptr).Value = value
+		if n == 0 {
+			// 第一个槽位不可写，如果尾部位置没有变化，说明缓冲区已满
+			// The first slot is not writable, if the tail position has not changed, the buffer is full
+			if tail != atomic.LoadInt64(&r.tail) {
+				continue
+			}
+			if !r.overwrite {
+				r.stats.Add(shd.CounterFullRejections, len(values))
+				return 0
+			}
+
+			// 启用了淘汰模式，逐个推入剩余的元素
+			// Overwrite mode is enabled, push the remaining elements one by one
+			for _, value := range values {
+				r.push(value, true)
+			}
+			return len(values)
+		}
 
-			// 返回 true，表示成功推入元素
-			// Return true, indicating that the element was successfully pushed
-			return true
+		// 使用 CAS 操作一次占用所有连续的可写槽位
+		// Use CAS operation to claim all the consecutive writable slots at once
+		if atomic.CompareAndSwapInt64(&r.tail, tail, tail+n) {
+			// 按顺序写入值，然后通过更新序号逐个发布
+			// Write the values in order, then publish them one by one by updating the sequence numbers
+			for i := int64(0); i < n; i++ {
+				pos := tail + i
+				slot := r.slot(pos)
+				slot.value = values[i]
+				slot.sequence.Store(r.round(pos)*2 + 1)
+			}
+			r.stats.Add(shd.CounterPushes, int(n))
+
+			// 唤醒等待数据的 goroutine
+			// Wake up the goroutines waiting for data
+			r.notEmpty.Notify()
+
+			// 在淘汰模式下继续推入剩余的元素，否则返回推入的数量，没有推入的元素计为因为已满被拒绝
+			// Keep pushing the remaining elements in overwrite mode, otherwise return the number pushed, the elements not pushed count as rejected because the buffer was full
+			if !r.overwrite || n == int64(len(values)) {
+				r.stats.Add(shd.CounterFullRejections, len(values)-int(n))
+				return int(n)
+			}
+			for _, value := range values[n:] {
+				r.push(value, true)
+			}
+			return len(values)
 		}
+
+		// 尾部位置已经被其他生产者推进，记录一次 CAS 失败，退避之后重试
+		// The tail position has been advanced by another producer, count a CAS failure, back off and try again
+		r.stats.Inc(shd.CounterCASFailures)
+		r.backoff.Wait(&attempt)
 	}
+
+	return 0
 }
 
-// Pop 方法用于从无锁环形缓冲区中弹出一个元素
-// The Pop method is used to pop an element from the lock-free ring buffer
-func (r *LockFreeRingBuffer) Pop() (interface{}, bool) {
-	// 使用无限循环，直到成功弹出元素
-	// Use an infinite loop until an element is successfully popped
-	for {
-		// 如果缓冲区为空，返回 nil 和 false
-		// If the buffer is empty, return nil and false
-		if r.IsEmpty() {
-			return nil, false
+// PopBatch 方法用于从无锁环形缓冲区中按顺序弹出最多 len(dst) 个元素并写入 dst，返回弹出的元素数量。
+// 连续的已发布槽位通过一次 CAS 操作占用，缓冲区为空时返回 0
+// The PopBatch method is used to pop up to len(dst) elements from the lock-free ring buffer in order and write them into dst, returning the number of elements popped.
+// Consecutive published slots are claimed with a single CAS operation, 0 is returned when the buffer is empty
+func (r *LockFreeRingBufferOf[T]) PopBatch(dst []T) int {
+	attempt := 0
+
+	// 使用无限循环，直到成功占用槽位或者缓冲区为空
+	// Use an infinite loop until slots are successfully claimed or the buffer is empty
+	for len(dst) > 0 {
+		// 获取头部位置
+		// Get the head position
+		head := atomic.LoadInt64(&r.head)
+
+		// 从头部位置开始统计连续的已发布槽位，最多 len(dst) 个
+		// Count the consecutive published slots starting from the head position, at most len(dst)
+		n := int64(0)
+		for n < int64(len(dst)) {
+			pos := head + n
+			if r.slot(pos).sequence.Load() != r.round(pos)*2+1 {
+				break
+			}
+			n++
 		}
 
-		// 获取头部元素的位置
-		// Get the position of the head element
-		head := atomic.LoadInt64(&r.head)
+		if n == 0 {
+			// 第一个槽位中的值还没有发布，如果头部位置没有变化，说明缓冲区为空，返回 0
+			// The value in the first slot has not been published yet, if the head position has not changed, the buffer is empty, return 0
+			if head == atomic.LoadInt64(&r.head) {
+				r.stats.Inc(shd.CounterEmptyPops)
+				return 0
+			}
+			continue
+		}
+
+		// 使用 CAS 操作一次占用所有连续的已发布槽位
+		// Use CAS operation to claim all the consecutive published slots at once
+		if atomic.CompareAndSwapInt64(&r.head, head, head+n) {
+			// 按顺序读取值并清空槽位，然后通过更新序号把槽位交还给下一轮的生产者
+			// Read the values in order and clear the slots, then hand the slots back to the producers of the next round by updating the sequence numbers
+			var zero T
+			for i := int64(0); i < n; i++ {
+				pos := head + i
+				slot := r.slot(pos)
+				dst[i] = slot.value
+				slot.value = zero
+				slot.sequence.Store(r.round(pos)*2 + 2)
+			}
+			r.stats.Add(shd.CounterPops, int(n))
+
+			// 唤醒等待空闲槽位的 goroutine
+			// Wake up the goroutines waiting for a free slot
+			r.notFull.Notify()
+
+			// 返回弹出的元素数量
+			// Return the number of elements popped
+			return int(n)
+		}
 
-		// 计算下一个元素的位置
-		// Calculate the position of the next element
-		next := (head + 1) % r.capacity
+		// 头部位置已经被其他消费者推进，记录一次 CAS 失败，退避之后重试
+		// The head position has been advanced by another consumer, count a CAS failure, back off and try again
+		r.stats.Inc(shd.CounterCASFailures)
+		r.backoff.Wait(&attempt)
+	}
 
-		// 获取头部元素的指针
-		// Get the pointer of the head element
-		ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&r.data[head])))
+	return 0
+}
 
-		// 使用 CAS 操作尝试修改头部元素的位置，并且头部元素的指针不为空
-		// Use CAS operation to try to modify the position of the head element, and the pointer of the head element is not null
-		if atomic.CompareAndSwapInt64(&r.head, head, next) && ptr != unsafe.Pointer(nil) {
-			// 如果成功修改，缓冲区的元素数量减 1
-			// If the modification is successful, the number of elements in the buffer is reduced by 1
-			atomic.AddInt64(&r.count, -1)
+// Peek 方法用于返回头部位置的元素但不移除它，如果缓冲区为空，返回 T 的零值和 false。
+// Peek 是可线性化的：返回的元素在重新确认头部位置的那一刻位于缓冲区的头部，返回 false 时，读取到头部槽位未发布的那一刻缓冲区为空。
+// 读取是乐观的，不会阻塞消费者，读取期间槽位被消费者占用时会重新读取
+// The Peek method is used to return the element at the head position without removing it, returns the zero value of T and false if the buffer is empty.
+// Peek is linearizable: the returned element was at the head of the buffer at the moment the head position was confirmed again, and when false is returned the buffer was empty at the moment the head slot was seen unpublished.
+// The read is optimistic and never blocks the consumers, it is retried when a consumer claims the slot during the read
+func (r *LockFreeRingBufferOf[T]) Peek() (T, bool) {
+	for {
+		// 获取头部位置以及对应的槽位
+		// Get the head position and the corresponding slot
+		head := atomic.LoadInt64(&r.head)
+		slot := r.slot(head)
+
+		// 比较槽位的序号和头部位置所在轮次的可读序号
+		// Compare the sequence number of the slot with the readable sequence number of the round of the head position
+		diff := slot.sequence.Load() - (r.round(head)*2 + 1)
+
+		if diff == 0 {
+			// 槽位中的值已经发布，乐观地读取它，读取期间头部位置没有变化时返回
+			// The value in the slot has been published, read it optimistically and return it when the head position did not change during the read
+			if value, ok := r.read(head); ok {
+				return value, true
+			}
+		} else if diff < 0 {
+			// 槽位中的值还没有发布，缓冲区为空，返回 T 的零值和 false
+			// The value in the slot has not been published yet, the buffer is empty, return the zero value of T and false
+			var zero T
+			return zero, false
+		}
 
-			// 获取节点
-			// Get the node
-			node := shd.LoadNode(&ptr)
+		// 头部位置已经被消费者推进，重试
+		// The head position has been advanced by a consumer, retry
+	}
+}
 
-			// 获取节点的值
-			// Get the value of the node
-			value := node.Value
+// Range 方法用于从头部位置开始按 FIFO 顺序遍历缓冲区中的元素，但不移除它们，fn 返回 false 时停止遍历。
+// 遍历是弱一致的：它只访问遍历开始时头部和尾部位置之间的槽位，跳过已经被消费或者还没有发布的槽位，每个元素最多只会出现一次
+// The Range method is used to walk the elements in the buffer in FIFO order starting from the head position without removing them, the walk stops when fn returns false.
+// The walk is weakly consistent: it only visits the slots between the head and tail positions at the start of the walk, skipping slots that have already been consumed or are not published yet, each element appears at most once
+func (r *LockFreeRingBufferOf[T]) Range(fn func(value T) bool) {
+	// 获取遍历开始时的头部和尾部位置
+	// Get the head and tail positions at the start of the walk
+	head := atomic.LoadInt64(&r.head)
+	tail := atomic.LoadInt64(&r.tail)
+
+	for pos := head; pos < tail; pos++ {
+		// 乐观地读取位置上的元素，读取不会阻塞消费者，fn 在读取结束之后调用
+		// Read the element at the position optimistically, the read never blocks the consumers and fn is called after the read is done
+		if value, ok := r.read(pos); ok && !fn(value) {
+			return
+		}
+	}
+}
 
-			// 重置节点
-			// Reset the node
-			node.ResetAll()
+// Snapshot 方法用于按 FIFO 顺序返回缓冲区中所有元素的副本，但不移除它们。它与 Range 一样是弱一致的
+// The Snapshot method is used to return a copy of all the elements in the buffer in FIFO order without removing them. It is weakly consistent just like Range
+func (r *LockFreeRingBufferOf[T]) Snapshot() []T {
+	values := make([]T, 0, r.Count())
+	r.Range(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
 
-			// 返回节点的值和 true
-			// Return the value of the node and true
+// read 方法用于乐观地读取位置 pos 上已经发布并且还没有被消费的元素，否则返回 T 的零值和 false。
+// 先确认槽位已经发布，再复制值，最后重新确认序号和头部位置。消费者在修改槽位之前总是先推进头部位置，
+// 生产者只会写入已经被消费的槽位，因此头部位置还没有越过 pos 时，复制期间槽位没有被修改，复制到的值是完整的
+// The read method is used to optimistically read the element at position pos that has been published and not yet consumed, otherwise it returns the zero value of T and false.
+// It first confirms that the slot is published, then copies the value, and finally confirms the sequence number and the head position again. A consumer always advances the head position before it modifies a slot,
+// and a producer only writes slots that have been consumed, so when the head position has not passed pos the slot was not modified during the copy and the copied value is complete
+func (r *LockFreeRingBufferOf[T]) read(pos int64) (T, bool) {
+	slot := r.slot(pos)
+	seq := r.round(pos)*2 + 1
+
+	if slot.sequence.Load() == seq {
+		// 复制值，复制可能与消费者或生产者的写入并发，校验失败时丢弃，因此让竞争检测器忽略它
+		// Copy the value, the copy may run concurrently with a write by a consumer or a producer and is discarded when the validation fails, so the race detector ignores it
+		shd.RaceDisable()
+		value := slot.value
+		shd.RaceEnable()
+
+		// 使用 CAS 操作重新确认序号，它同时是一个完整的内存屏障，保证复制在重新读取头部位置之前完成
+		// Use CAS operation to confirm the sequence number again, it is also a full memory barrier that ensures the copy completes before the head position is read again
+		if slot.sequence.CompareAndSwap(seq, seq) && atomic.LoadInt64(&r.head) <= pos {
 			return value, true
 		}
 	}
+
+	var zero T
+	return zero, false
+}
+
+// PopWait 方法用于从无锁环形缓冲区中弹出一个元素，如果缓冲区为空，会先自旋再挂起等待，直到有元素可用或者 ctx 结束
+// The PopWait method is used to pop an element from the lock-free ring buffer, if the buffer is empty it spins and then parks until an element is available or ctx is done
+func (r *LockFreeRingBufferOf[T]) PopWait(ctx context.Context) (T, error) {
+	// 快速路径，缓冲区不为空时直接返回
+	// Fast path, return directly when the buffer is not empty
+	if value, ok := r.Pop(); ok {
+		return value, nil
+	}
+
+	// 慢速路径，等待直到弹出一个元素或者 ctx 结束
+	// Slow path, wait until an element is popped or ctx is done
+	var value T
+	err := r.notEmpty.Wait(ctx, func() bool {
+		var ok bool
+		value, ok = r.Pop()
+		return ok
+	})
+	return value, err
+}
+
+// PushWait 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，会先自旋再挂起等待，直到有空闲槽位或者 ctx 结束。ctx 结束时返回 ctx.Err()
+// The PushWait method is used to push an element into the lock-free ring buffer, if the buffer is full it spins and then parks until a slot is free or ctx is done. It returns ctx.Err() when ctx is done
+func (r *LockFreeRingBufferOf[T]) PushWait(ctx context.Context, value T) error {
+	// 快速路径，缓冲区未满时直接返回
+	// Fast path, return directly when the buffer is not full
+	if r.Push(value) {
+		return nil
+	}
+
+	// 慢速路径，等待直到推入成功或者 ctx 结束
+	// Slow path, wait until the push succeeds or ctx is done
+	return r.notFull.Wait(ctx, func() bool {
+		return r.Push(value)
+	})
+}
+
+// PushTimeout 方法用于向无锁环形缓冲区中推入一个元素，如果在 timeout 时间内没有空闲槽位，返回 context.DeadlineExceeded
+// The PushTimeout method is used to push an element into the lock-free ring buffer, returns context.DeadlineExceeded if no slot becomes free within timeout
+func (r *LockFreeRingBufferOf[T]) PushTimeout(value T, timeout time.Duration) error {
+	// 快速路径，缓冲区未满时直接返回，避免创建 context
+	// Fast path, return directly when the buffer is not full to avoid creating a context
+	if r.Push(value) {
+		return nil
+	}
+
+	// 慢速路径，使用带超时的 context 等待
+	// Slow path, wait with a context that has a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return r.PushWait(ctx, value)
+}
+
+// Stats 方法用于返回环形缓冲区的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the ring buffer, the zero value is returned when the WithStats option is not enabled
+func (r *LockFreeRingBufferOf[T]) Stats() Stats {
+	return r.stats.Snapshot()
 }
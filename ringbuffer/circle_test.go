@@ -1,9 +1,12 @@
 package ringbuffer
 
 import (
+	"context"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -300,6 +303,788 @@ func TestLockFreeRingBuffer_ParallelDevilMode(t *testing.T) {
 	wg.Wait()
 }
 
+func TestLockFreeRingBufferOf_Standard(t *testing.T) {
+	// Test the generic ring buffer with a large number of elements
+	count := 1000000
+
+	r := NewOf[int](count)
+
+	// Test pushing values into the ring buffer
+	for i := 0; i < count; i++ {
+		if !r.Push(i) {
+			assert.Fail(t, "Failed to push value: %d", i)
+		}
+	}
+
+	// Verify the ring buffer length
+	assert.Equal(t, int64(count), r.Count(), "Incorrect ring buffer length. Expected %d, got %d", count, r.Count())
+
+	// Verify the elements in the ring buffer
+	for i := 0; i < count; i++ {
+		value, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, value, "Incorrect value in the ring buffer. Expected %d, got %d", i, value)
+	}
+
+	// Try popping one more value, should fail
+	value, ok := r.Pop()
+	assert.False(t, ok, "Popped value when the ring buffer is empty")
+	assert.Equal(t, 0, value, "Expected zero value from an empty ring buffer")
+}
+
+func TestLockFreeRingBufferOf_Struct(t *testing.T) {
+	type item struct {
+		id   int
+		name string
+	}
+
+	r := NewOf[item](5)
+
+	// Push struct values without boxing them into interface{}
+	for i := 0; i < 5; i++ {
+		assert.True(t, r.Push(item{id: i, name: "item"}), "Failed to push value: %d", i)
+	}
+	assert.False(t, r.Push(item{id: 5}), "Pushed value when the ring buffer is full")
+
+	// Pop struct values without type assertions
+	for i := 0; i < 5; i++ {
+		value, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, value.id, "Incorrect value in the ring buffer. Expected %d, got %d", i, value.id)
+	}
+}
+
+func TestLockFreeRingBuffer_MPMCNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 8, 8, 20000
+	total := producers * perProducer
+
+	// Use a small ring so that producers and consumers keep wrapping around
+	r := New(64)
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values and retries while the ring is full
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				for !r.Push(p*perProducer + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, they pop until every value has been seen
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v, ok := r.Pop(); ok {
+					atomic.AddInt32(&seen[v.(int)], 1)
+					atomic.AddInt64(&popped, 1)
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify that every value was popped exactly once
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), r.Count(), "Incorrect ring buffer length. Expected 0, got %d", r.Count())
+}
+
+func TestLockFreeRingBuffer_PopWait(t *testing.T) {
+	q := New(1000)
+
+	// Push a value after the consumer has started waiting
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.Push(1)
+	}()
+
+	v, err := q.PopWait(context.Background())
+	assert.NoError(t, err, "PopWait returned an error")
+	assert.Equal(t, 1, v, "Incorrect value from PopWait. Expected 1, got %v", v)
+}
+
+func TestLockFreeRingBuffer_PopWaitCanceled(t *testing.T) {
+	q := New(1000)
+
+	// Waiting on an empty ring buffer must return once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	v, err := q.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected a deadline exceeded error")
+	assert.Nil(t, v, "Expected nil value from an empty ring buffer")
+}
+
+func TestLockFreeRingBuffer_PopWaitParallel(t *testing.T) {
+	q := New(1000)
+	count := 1000
+
+	// Start the consumers before anything is pushed
+	wg := sync.WaitGroup{}
+	received := int64(0)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count/10; j++ {
+				if _, err := q.PopWait(context.Background()); err == nil {
+					atomic.AddInt64(&received, 1)
+				}
+			}
+		}()
+	}
+
+	// Push the values from several producers
+	for i := 0; i < count; i++ {
+		go q.Push(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(count), received, "Incorrect number of values received. Expected %d, got %d", count, received)
+}
+
+func TestLockFreeRingBuffer_CapacityOne(t *testing.T) {
+	r := New(1)
+
+	// A ring buffer with a single slot must alternate between full and empty
+	for i := 0; i < 10; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+		assert.False(t, r.Push(i+1), "Pushed value when the ring buffer is full")
+
+		value, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, value, "Incorrect value in the ring buffer. Expected %d, got %d", i, value)
+
+		_, ok = r.Pop()
+		assert.False(t, ok, "Popped value when the ring buffer is empty")
+	}
+}
+
+func TestLockFreeRingBuffer_PowerOfTwo(t *testing.T) {
+	// Test that the capacity is rounded up to a power of two only with the option
+	assert.Equal(t, int64(5), New(5).Capacity(), "Capacity should not be rounded without the option")
+	assert.Equal(t, int64(8), New(5, WithPowerOfTwo()).Capacity(), "Capacity should be rounded up to 8")
+	assert.Equal(t, int64(8), New(8, WithPowerOfTwo()).Capacity(), "Capacity should stay 8")
+	assert.Equal(t, int64(1), New(1, WithPowerOfTwo()).Capacity(), "Capacity should stay 1")
+
+	r := New(5, WithPowerOfTwo())
+
+	// Fill the buffer, then keep it full while the positions wrap around many rounds
+	for i := 0; i < 8; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+	}
+	assert.True(t, r.IsFull(), "Ring buffer should be full")
+	assert.False(t, r.Push(8), "Pushed value when the ring buffer is full")
+	for i := 0; i < 1000; i++ {
+		value, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, value, "Incorrect value in the ring buffer. Expected %d, got %d", i, value)
+		assert.True(t, r.Push(i+8), "Failed to push value: %d", i+8)
+		assert.Equal(t, int64(8), r.Count(), "Incorrect count of the ring buffer")
+	}
+}
+
+func TestLockFreeRingBuffer_WrapAround(t *testing.T) {
+	// Use a capacity that is not a power of two, so the slots are found with modulo and division
+	r := New(6)
+
+	// Keep the buffer half full while the positions wrap around many rounds
+	next := 0
+	for i := 0; i < 3000; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+		if i%3 == 2 {
+			for j := 0; j < 3; j++ {
+				value, ok := r.Pop()
+				assert.True(t, ok, "Failed to pop value")
+				assert.Equal(t, next, value, "Incorrect value in the ring buffer. Expected %d, got %d", next, value)
+				next++
+			}
+		}
+		assert.LessOrEqual(t, r.Count(), int64(3), "Incorrect count of the ring buffer")
+	}
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+}
+
+func TestLockFreeRingBuffer_ZeroAllocs(t *testing.T) {
+	r := New(16)
+	v := new(int)
+
+	// Pointer-shaped values are stored inline in the slots, so Push and Pop do not allocate
+	allocs := testing.AllocsPerRun(1000, func() {
+		r.Push(v)
+		r.Pop()
+	})
+	assert.Equal(t, float64(0), allocs, "Push and Pop should not allocate, got %v allocs per run", allocs)
+
+	// The generic ring buffer does not allocate for any type of value
+	g := NewOf[int](16)
+	allocs = testing.AllocsPerRun(1000, func() {
+		g.Push(1)
+		g.Pop()
+	})
+	assert.Equal(t, float64(0), allocs, "Push and Pop should not allocate, got %v allocs per run", allocs)
+}
+
+func TestLockFreeRingBuffer_PushWait(t *testing.T) {
+	r := New(1)
+	assert.True(t, r.Push(0), "Failed to push value: 0")
+
+	// Free a slot after the producer has started waiting
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		r.Pop()
+	}()
+
+	err := r.PushWait(context.Background(), 1)
+	assert.NoError(t, err, "PushWait returned an error")
+
+	v, ok := r.Pop()
+	assert.True(t, ok, "Failed to pop value")
+	assert.Equal(t, 1, v, "Incorrect value in the ring buffer. Expected 1, got %v", v)
+}
+
+func TestLockFreeRingBuffer_PushTimeout(t *testing.T) {
+	r := New(1)
+	assert.True(t, r.Push(0), "Failed to push value: 0")
+
+	// Pushing into a full ring buffer must time out
+	err := r.PushTimeout(1, 50*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected a deadline exceeded error")
+	assert.Equal(t, int64(1), r.Count(), "Incorrect ring buffer length. Expected 1, got %d", r.Count())
+
+	// Pushing into a ring buffer with a free slot must succeed immediately
+	r.Pop()
+	assert.NoError(t, r.PushTimeout(1, 50*time.Millisecond), "PushTimeout returned an error")
+}
+
+func TestLockFreeRingBuffer_PushWaitPipeline(t *testing.T) {
+	count := 100000
+
+	// Use a tiny ring so that the producer is blocked most of the time, like a buffered channel
+	r := NewOf[int](4)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			if err := r.PushWait(context.Background(), i); err != nil {
+				assert.Fail(t, "PushWait returned an error", "%v", err)
+				return
+			}
+		}
+	}()
+
+	// Verify that every value arrives in order
+	for i := 0; i < count; i++ {
+		v, err := r.PopWait(context.Background())
+		assert.NoError(t, err, "PopWait returned an error")
+		if v != i {
+			assert.Equal(t, i, v, "Incorrect value in the ring buffer. Expected %d, got %d", i, v)
+			break
+		}
+	}
+	wg.Wait()
+}
+
+func TestLockFreeRingBuffer_Overwrite(t *testing.T) {
+	r := New(5, WithOverwrite())
+
+	// Push twice the capacity, Push never fails in overwrite mode
+	for i := 0; i < 10; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+	}
+	assert.Equal(t, int64(5), r.Count(), "Incorrect ring buffer length. Expected 5, got %d", r.Count())
+	assert.True(t, r.IsFull(), "Ring buffer should be full")
+
+	// Only the newest values are left, in FIFO order
+	for i := 5; i < 10; i++ {
+		v, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, v, "Popped value is incorrect")
+	}
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+
+	// Without the option a full buffer still rejects the value
+	r = New(5)
+	for i := 0; i < 5; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+	}
+	assert.False(t, r.Push(5), "Push should fail on a full ring buffer")
+}
+
+func TestLockFreeRingBuffer_PushOverwrite(t *testing.T) {
+	r := New(5)
+
+	// No value is evicted while the buffer has free slots
+	for i := 0; i < 5; i++ {
+		v, evicted := r.PushOverwrite(i)
+		assert.False(t, evicted, "No value should be evicted")
+		assert.Nil(t, v, "Evicted value should be nil")
+	}
+
+	// Once full, the oldest values are evicted in order
+	for i := 5; i < 10; i++ {
+		v, evicted := r.PushOverwrite(i)
+		assert.True(t, evicted, "A value should be evicted")
+		assert.Equal(t, i-5, v, "Evicted value is incorrect")
+	}
+
+	// The buffer keeps working normally after the evictions
+	for i := 5; i < 10; i++ {
+		v, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, v, "Popped value is incorrect")
+	}
+	assert.Equal(t, int64(0), r.Count(), "Incorrect ring buffer length. Expected 0, got %d", r.Count())
+}
+
+func TestLockFreeRingBuffer_OverwriteParallel(t *testing.T) {
+	producers, consumers, perProducer := 8, 4, 20000
+	total := producers * perProducer
+
+	// Use a tiny ring so that producers evict values all the time
+	r := NewOf[int](8)
+
+	// seen records how many times each value has been popped or evicted
+	seen := make([]int32, total)
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+	cwg := sync.WaitGroup{}
+
+	// Start the producers, every push succeeds and may evict the oldest value
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				if v, evicted := r.PushOverwrite(p*perProducer + i); evicted {
+					atomic.AddInt32(&seen[v], 1)
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, they pop until the producers are done and the ring is drained
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			for {
+				// Read the flag before popping, so an empty pop after it means the ring is drained
+				finished := atomic.LoadInt32(&done) == 1
+				if v, ok := r.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+				} else if finished {
+					return
+				} else {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	atomic.StoreInt32(&done, 1)
+	cwg.Wait()
+
+	// Verify that every value was either popped or evicted exactly once
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value handled an unexpected number of times", "value %d handled %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), r.Count(), "Incorrect ring buffer length. Expected 0, got %d", r.Count())
+}
+
+func TestLockFreeRingBuffer_PushBatchPopBatch(t *testing.T) {
+	r := New(5)
+
+	// Only the values that fit are pushed
+	assert.Equal(t, 5, r.PushBatch([]interface{}{0, 1, 2, 3, 4, 5, 6}), "Incorrect number of pushed values")
+	assert.True(t, r.IsFull(), "Ring buffer should be full")
+	assert.Equal(t, 0, r.PushBatch([]interface{}{7}), "PushBatch on a full ring buffer should return 0")
+
+	// Pop a partial batch, values come out in FIFO order
+	dst := make([]interface{}, 3)
+	n := r.PopBatch(dst)
+	assert.Equal(t, 3, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{0, 1, 2}, dst[:n], "Popped values are incorrect")
+	assert.Equal(t, int64(2), r.Count(), "Incorrect ring buffer length. Expected 2, got %d", r.Count())
+
+	// Push a batch that wraps around the end of the slots
+	assert.Equal(t, 3, r.PushBatch([]interface{}{5, 6, 7}), "Incorrect number of pushed values")
+
+	// Pop everything, the batch is cut short by the end of the data
+	dst = make([]interface{}, 10)
+	n = r.PopBatch(dst)
+	assert.Equal(t, 5, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{3, 4, 5, 6, 7}, dst[:n], "Popped values are incorrect")
+	assert.Equal(t, 0, r.PopBatch(dst), "PopBatch on an empty ring buffer should return 0")
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+
+	// In overwrite mode the whole batch is always pushed and only the newest values are kept
+	r = New(5, WithOverwrite())
+	assert.Equal(t, 8, r.PushBatch([]interface{}{0, 1, 2, 3, 4, 5, 6, 7}), "Incorrect number of pushed values")
+	n = r.PopBatch(dst)
+	assert.Equal(t, []interface{}{3, 4, 5, 6, 7}, dst[:n], "Popped values are incorrect")
+}
+
+func TestLockFreeRingBuffer_BatchNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer, batch := 8, 8, 20000, 16
+	total := producers * perProducer
+
+	// Use a small ring so that batches keep wrapping around and get cut short
+	r := NewOf[int](64)
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values in batches and retries the rest while the ring is full
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			values := make([]int, 0, batch)
+			for i := 0; i < perProducer; i++ {
+				values = append(values, p*perProducer+i)
+				if len(values) == batch || i == perProducer-1 {
+					for pushed := 0; pushed < len(values); {
+						n := r.PushBatch(values[pushed:])
+						pushed += n
+						if n == 0 {
+							runtime.Gosched()
+						}
+					}
+					values = values[:0]
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, half of them pop in batches and the others pop one by one
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			dst := make([]int, batch)
+			for atomic.LoadInt64(&popped) < int64(total) {
+				n := 0
+				if c%2 == 0 {
+					n = r.PopBatch(dst)
+				} else if v, ok := r.Pop(); ok {
+					dst[0], n = v, 1
+				}
+				for _, v := range dst[:n] {
+					atomic.AddInt32(&seen[v], 1)
+				}
+				atomic.AddInt64(&popped, int64(n))
+				if n == 0 {
+					runtime.Gosched()
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	// Verify that every value was popped exactly once
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), r.Count(), "Incorrect ring buffer length. Expected 0, got %d", r.Count())
+}
+
+func TestLockFreeRingBuffer_Peek(t *testing.T) {
+	r := New(3)
+
+	// Peek on an empty ring buffer reports that there is no element
+	v, ok := r.Peek()
+	assert.False(t, ok, "Peek on an empty ring buffer should return false")
+	assert.Nil(t, v, "Peek on an empty ring buffer should return nil")
+
+	r.Push(1)
+	r.Push(2)
+
+	// Peek returns the element at the head without removing it
+	for i := 0; i < 3; i++ {
+		v, ok = r.Peek()
+		assert.True(t, ok, "Peek should return true")
+		assert.Equal(t, 1, v, "Peeked value is incorrect")
+	}
+	assert.Equal(t, int64(2), r.Count(), "Incorrect ring buffer length. Expected 2, got %d", r.Count())
+
+	// After a pop the next element is at the head, also after wrapping around
+	r.Pop()
+	r.Push(3)
+	r.Push(4)
+	r.Pop()
+	v, _ = r.Peek()
+	assert.Equal(t, 3, v, "Peeked value is incorrect")
+}
+
+func TestLockFreeRingBuffer_PeekParallel(t *testing.T) {
+	for _, r := range []*LockFreeRingBufferOf[int]{NewOf[int](64), NewOf[int](64, WithOverwrite())} {
+		total := 20000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values and retries while the ring is full
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				for !r.Push(i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+
+		// Consumers pop the values until the ring has been drained
+		for c := 0; c < 2; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					if _, ok := r.Pop(); !ok {
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+
+		// The head only moves forward, so every peeker must see non-decreasing values
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				last := -1
+				for atomic.LoadInt32(&done) == 0 {
+					if v, ok := r.Peek(); ok {
+						if v < last || v >= total {
+							assert.Failf(t, "Peeked value is out of order", "peeked %d after %d", v, last)
+							return
+						}
+						last = v
+					}
+					runtime.Gosched()
+				}
+			}()
+		}
+
+		// Stop once every value has been pushed and popped
+		pwg.Wait()
+		for !r.IsEmpty() {
+			time.Sleep(time.Millisecond)
+		}
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		_, ok := r.Peek()
+		assert.False(t, ok, "Peek on an empty ring buffer should return false")
+	}
+}
+
+func TestLockFreeRingBuffer_PeekTornParallel(t *testing.T) {
+	// Every pushed value repeats one number in all its words, a torn read would mix two numbers
+	r := NewOf[[4]int](4, WithOverwrite())
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+
+	// Producers overwrite the oldest values and consumers pop them while the peekers read
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; atomic.LoadInt32(&done) == 0; i++ {
+				if w%2 == 0 {
+					r.Push([4]int{i, i, i, i})
+				} else {
+					r.Pop()
+				}
+			}
+		}(w)
+	}
+
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20000; i++ {
+				if v, ok := r.Peek(); ok && (v[0] != v[1] || v[1] != v[2] || v[2] != v[3]) {
+					assert.Failf(t, "Peeked a torn value", "peeked %v", v)
+					return
+				}
+				r.Range(func(v [4]int) bool {
+					if v[0] != v[1] || v[1] != v[2] || v[2] != v[3] {
+						assert.Failf(t, "Ranged over a torn value", "got %v", v)
+					}
+					return true
+				})
+			}
+		}()
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	atomic.StoreInt32(&done, 1)
+	wg.Wait()
+}
+
+func TestLockFreeRingBuffer_RangeDoesNotBlockConsumers(t *testing.T) {
+	r := NewOf[int](4)
+	r.Push(1)
+	r.Push(2)
+
+	// A Range callback that never returns on its own must not stall the consumers
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	go r.Range(func(int) bool {
+		close(entered)
+		<-release
+		return false
+	})
+	<-entered
+
+	popped := make(chan int, 2)
+	go func() {
+		for i := 0; i < 2; i++ {
+			v, _ := r.Pop()
+			popped <- v
+		}
+	}()
+
+	for _, expected := range []int{1, 2} {
+		select {
+		case v := <-popped:
+			assert.Equal(t, expected, v, "Incorrect popped value")
+		case <-time.After(time.Second):
+			assert.Fail(t, "Pop was blocked by a Range callback")
+		}
+	}
+	close(release)
+}
+
+func TestLockFreeRingBuffer_Range(t *testing.T) {
+	r := New(4)
+
+	// Range and Snapshot on an empty ring buffer see nothing
+	r.Range(func(v interface{}) bool {
+		assert.Fail(t, "Range on an empty ring buffer should not call fn")
+		return true
+	})
+	assert.Empty(t, r.Snapshot(), "Snapshot of an empty ring buffer should be empty")
+
+	// Wrap around the end of the slots before taking the snapshot
+	for i := 0; i < 3; i++ {
+		r.Push(i)
+	}
+	r.Pop()
+	r.Pop()
+	for i := 3; i < 6; i++ {
+		r.Push(i)
+	}
+
+	// Snapshot returns the elements in FIFO order without removing them
+	assert.Equal(t, []interface{}{2, 3, 4, 5}, r.Snapshot(), "Snapshot is incorrect")
+	assert.Equal(t, int64(4), r.Count(), "Incorrect ring buffer length. Expected 4, got %d", r.Count())
+
+	// Range stops as soon as fn returns false
+	var seen []interface{}
+	r.Range(func(v interface{}) bool {
+		seen = append(seen, v)
+		return len(seen) < 2
+	})
+	assert.Equal(t, []interface{}{2, 3}, seen, "Range visited incorrect elements")
+}
+
+func TestLockFreeRingBuffer_SnapshotParallel(t *testing.T) {
+	for _, r := range []*LockFreeRingBufferOf[int]{NewOf[int](64), NewOf[int](64, WithOverwrite())} {
+		total := 20000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values and retries while the ring is full
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				for !r.Push(i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+
+		// Consumers pop the values until the ring has been drained
+		for c := 0; c < 2; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					if _, ok := r.Pop(); !ok {
+						runtime.Gosched()
+					}
+				}
+			}()
+		}
+
+		// Every snapshot must be strictly increasing, so no element is visited twice or out of order
+		for p := 0; p < 2; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					values := r.Snapshot()
+					for i := 1; i < len(values); i++ {
+						if values[i] <= values[i-1] {
+							assert.Failf(t, "Snapshot is out of order", "%d after %d", values[i], values[i-1])
+							return
+						}
+					}
+					runtime.Gosched()
+				}
+			}()
+		}
+
+		pwg.Wait()
+		for !r.IsEmpty() {
+			time.Sleep(time.Millisecond)
+		}
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		assert.Empty(t, r.Snapshot(), "Snapshot of an empty ring buffer should be empty")
+	}
+}
+
 func Benchmark_MOD(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = i % 265
@@ -311,3 +1096,62 @@ func Benchmark_BitOR(b *testing.B) {
 		_ = i & 127
 	}
 }
+
+func TestLockFreeRingBuffer_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	rb := New(4)
+	rb.Push(1)
+	rb.Pop()
+	assert.Equal(t, Stats{}, rb.Stats(), "Statistics should be disabled by default")
+
+	// Fill the buffer, the pushes that do not fit are rejected
+	rb = New(4, WithStats())
+	for i := 0; i < 5; i++ {
+		rb.Push(i)
+	}
+	assert.Equal(t, 0, rb.PushBatch([]interface{}{5, 6}), "Pushed values into a full buffer")
+
+	// Empty the buffer, the pops after it is empty find nothing
+	assert.Equal(t, 3, rb.PopBatch(make([]interface{}, 3)), "Incorrect number of values popped")
+	rb.Pop()
+	rb.Pop()
+	assert.Equal(t, 0, rb.PopBatch(make([]interface{}, 2)), "Popped values from an empty buffer")
+
+	s := rb.Stats()
+	assert.Equal(t, uint64(4), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(4), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(3), s.FullRejections, "Incorrect number of full rejections")
+	assert.Equal(t, uint64(2), s.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+}
+
+func TestLockFreeRingBuffer_WithBackoff(t *testing.T) {
+	var calls atomic.Int64
+	rb := NewOf[int](8, WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines, every CAS failure backs off once
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				for !rb.Push(j) {
+					runtime.Gosched()
+				}
+				for _, ok := rb.Pop(); !ok; _, ok = rb.Pop() {
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, rb.IsEmpty(), "Buffer should be empty")
+	assert.Equal(t, rb.Stats().CASFailures, uint64(calls.Load()), "Every CAS failure should back off once")
+}
@@ -1,5 +1,15 @@
 package ringbuffer
 
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是环形缓冲区统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a ring buffer, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
 // RingBuffer 是一个接口，定义了环形缓冲区的基本操作
 // RingBuffer is an interface that defines basic operations of a ring buffer
 type RingBuffer = interface {
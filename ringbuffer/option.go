@@ -0,0 +1,77 @@
+package ringbuffer
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// config 是环形缓冲区的配置
+// config is the configuration of the ring buffer
+type config struct {
+	// overwrite 表示缓冲区已满时，Push 是否覆盖最旧的元素
+	// overwrite indicates whether Push overwrites the oldest element when the buffer is full
+	overwrite bool
+
+	// powerOfTwo 表示是否将容量向上取整为 2 的幂
+	// powerOfTwo indicates whether the capacity is rounded up to a power of two
+	powerOfTwo bool
+
+	// stats 表示是否启用统计
+	// stats indicates whether statistics are enabled
+	stats bool
+
+	// backoff 是 CAS 失败之后的退避策略，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// Option 是一个函数类型，用于修改环形缓冲区的配置
+// Option is a function type used to modify the configuration of the ring buffer
+type Option func(*config)
+
+// newConfig 函数用于创建一个新的配置，并应用所有的选项
+// The newConfig function is used to create a new configuration and apply all the options
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithOverwrite 函数返回一个选项，启用后缓冲区已满时 Push 不会失败，而是淘汰最旧的元素。
+// 需要获取被淘汰的元素时，使用 PushOverwrite 方法。
+// The WithOverwrite function returns an option, when enabled Push never fails on a full buffer and evicts the oldest element instead.
+// Use the PushOverwrite method when the evicted element is needed.
+func WithOverwrite() Option {
+	return func(c *config) {
+		c.overwrite = true
+	}
+}
+
+// WithPowerOfTwo 函数返回一个选项，启用后容量会被向上取整为 2 的幂，槽位下标和轮次通过掩码和位移计算，不再需要取模和除法。
+// 容量本身已经是 2 的幂时，不需要这个选项也会使用位运算。
+// The WithPowerOfTwo function returns an option, when enabled the capacity is rounded up to a power of two, and the slot index and the round are computed with a mask and a shift instead of modulo and division.
+// Bitwise operations are also used without this option when the capacity already is a power of two.
+func WithPowerOfTwo() Option {
+	return func(c *config) {
+		c.powerOfTwo = true
+	}
+}
+
+// WithStats 函数返回一个选项，启用后缓冲区会统计推入、弹出、CAS 失败、因为已满被拒绝的推入以及空弹出，可以通过 Stats 方法读取。
+// 计数器按 goroutine 分片，开销很小，没有启用时只需要一次 nil 判断。NewSPSCOf 只使用这个选项和 WithPowerOfTwo
+// The WithStats function returns an option, when enabled the buffer counts pushes, pops, CAS failures, pushes rejected because it was full and empty pops, which can be read with the Stats method.
+// The counters are sharded by goroutine and cheap to update, when not enabled they only cost a nil check. NewSPSCOf only uses this option and WithPowerOfTwo
+func WithStats() Option {
+	return func(c *config) {
+		c.stats = true
+	}
+}
+
+// WithBackoff 函数返回一个选项，设置 CAS 失败之后的退避策略 b。竞争激烈时，退避可以减少无效的重试。
+// NewSPSCOf 创建的缓冲区没有 CAS 循环，不使用它
+// The WithBackoff function returns an option that sets the backoff strategy b after a failed CAS. Under heavy contention backing off reduces wasted retries.
+// The buffer created by NewSPSCOf has no CAS loop and does not use it
+func WithBackoff(b Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
@@ -0,0 +1,225 @@
+package ringbuffer
+
+import (
+	"sync/atomic"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeSPSCRingBufferOf 是一个泛型单生产者单消费者无锁环形缓冲区的结构体，T 为缓冲区中元素的类型。
+// 头部位置只由消费者修改，尾部位置只由生产者修改，因此不需要 CAS 操作，只需要原子读取和写入。
+// 双方还各自缓存了对方的位置，只有在缓存的位置显示缓冲区已满或者为空时才重新读取，减少了共享变量的访问。
+// 同一时刻最多只能有一个 goroutine 调用 Push，最多只能有一个 goroutine 调用 Pop
+// LockFreeSPSCRingBufferOf is a structure of a generic single-producer single-consumer lock-free ring buffer, T is the type of the elements in the buffer.
+// The head position is only modified by the consumer and the tail position only by the producer, so no CAS operation is needed, only atomic loads and stores.
+// Each side also caches the position of the other side and only reloads it when the cached position shows the buffer as full or empty, which reduces the accesses to shared variables.
+// At most one goroutine may call Push and at most one goroutine may call Pop at the same time
+type LockFreeSPSCRingBufferOf[T any] struct {
+	// tail 是环形缓冲区的尾部位置，单调递增，只由生产者修改。它是第一个字段，保证在 32 位平台上也按 8 字节对齐
+	// tail is the tail position of the ring buffer, it increases monotonically and is only modified by the producer. It is the first field so it is 8-byte aligned on 32-bit platforms as well
+	tail int64
+
+	// cachedHead 是生产者缓存的头部位置，只由生产者访问
+	// cachedHead is the head position cached by the producer, it is only accessed by the producer
+	cachedHead int64
+
+	// _ 用于把生产者的字段与消费者的字段分隔到不同的缓存行，避免伪共享
+	// _ separates the fields of the producer from the fields of the consumer onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
+	// head 是环形缓冲区的头部位置，单调递增，只由消费者修改
+	// head is the head position of the ring buffer, it increases monotonically and is only modified by the consumer
+	head int64
+
+	// cachedTail 是消费者缓存的尾部位置，只由消费者访问
+	// cachedTail is the tail position cached by the consumer, it is only accessed by the consumer
+	cachedTail int64
+
+	// _ 用于把消费者的字段与之后只读的字段分隔到不同的缓存行
+	// _ separates the fields of the consumer from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// capacity 是环形缓冲区的容量
+	// capacity is the capacity of the ring buffer
+	capacity int64
+
+	// mask 是容量为 2 的幂时用于计算槽位下标的掩码，为 -1 时使用取模
+	// mask is the mask used to compute the slot index when the capacity is a power of two, modulo is used when it is -1
+	mask int64
+
+	// data 是用于存储元素的切片
+	// data is a slice used to store elements
+	data []T
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+}
+
+// NewSPSCOf 是一个函数，用于创建一个新的泛型 LockFreeSPSCRingBufferOf 实例，只有 WithStats 和 WithPowerOfTwo 选项对它有效
+// NewSPSCOf is a function that creates a new instance of the generic LockFreeSPSCRingBufferOf, only the WithStats and WithPowerOfTwo options apply to it
+func NewSPSCOf[T any](capacity int, opts ...Option) *LockFreeSPSCRingBufferOf[T] {
+	// 如果传入的容量小于或等于 0，那么将容量设置为默认的环形缓冲区大小
+	// If the passed in capacity is less than or equal to 0, then set the capacity to the default ring buffer size
+	if capacity <= 0 {
+		capacity = DefaultCircleBufferSize
+	}
+
+	// 如果启用了 WithPowerOfTwo 选项，那么将容量向上取整为 2 的幂
+	// If the WithPowerOfTwo option is enabled, then round the capacity up to a power of two
+	conf := newConfig(opts)
+	if conf.powerOfTwo {
+		capacity = roundUpPowerOfTwo(capacity)
+	}
+
+	// 创建一个新的 LockFreeSPSCRingBufferOf 实例
+	// Create a new instance of LockFreeSPSCRingBufferOf
+	rb := &LockFreeSPSCRingBufferOf[T]{
+		capacity: int64(capacity),
+		mask:     -1,
+		data:     make([]T, capacity),
+	}
+
+	// 如果启用了统计，那么创建统计计数器
+	// If statistics are enabled, then create the statistics counters
+	if conf.stats {
+		rb.stats = shd.NewCounters()
+	}
+
+	// 如果容量是 2 的幂，那么使用掩码计算槽位下标，避免每次推入和弹出都做一次 64 位取模
+	// If the capacity is a power of two, then compute the slot index with a mask, which avoids a 64-bit modulo on every push and pop
+	if capacity&(capacity-1) == 0 {
+		rb.mask = int64(capacity - 1)
+	}
+
+	// 返回新创建的 LockFreeSPSCRingBufferOf 实例
+	// Return the newly created LockFreeSPSCRingBufferOf instance
+	return rb
+}
+
+// index 方法用于返回位置 pos 对应的槽位下标
+// The index method is used to return the slot index of position pos
+func (r *LockFreeSPSCRingBufferOf[T]) index(pos int64) int64 {
+	if r.mask >= 0 {
+		return pos & r.mask
+	}
+	return pos % r.capacity
+}
+
+// IsEmpty 是一个方法，用于检查环形缓冲区是否为空
+// IsEmpty is a method that checks whether the ring buffer is empty
+func (r *LockFreeSPSCRingBufferOf[T]) IsEmpty() bool {
+	return r.Count() == 0
+}
+
+// IsFull 是一个方法，用于检查环形缓冲区是否已满
+// IsFull is a method that checks whether the ring buffer is full
+func (r *LockFreeSPSCRingBufferOf[T]) IsFull() bool {
+	return r.Count() == r.capacity
+}
+
+// Capacity 是一个方法，返回环形缓冲区的容量
+// Capacity is a method that returns the capacity of the ring buffer
+func (r *LockFreeSPSCRingBufferOf[T]) Capacity() int64 {
+	return r.capacity
+}
+
+// Count 是一个方法，返回环形缓冲区中的元素数量
+// Count is a method that returns the number of elements in the ring buffer
+func (r *LockFreeSPSCRingBufferOf[T]) Count() int64 {
+	// 先读取头部位置再读取尾部位置，尾部位置不会小于头部位置
+	// Load the head position before the tail position, so the tail position is never less than the head position
+	head := atomic.LoadInt64(&r.head)
+	count := atomic.LoadInt64(&r.tail) - head
+
+	// 两次读取之间消费者可能已经推进了头部位置，数量不会超过容量
+	// The consumer may have advanced the head position between the two loads, the number never exceeds the capacity
+	if count > r.capacity {
+		count = r.capacity
+	}
+	return count
+}
+
+// Reset 是一个方法，用于重置环形缓冲区。它不能与 Push 和 Pop 并发调用
+// Reset is a method that resets the ring buffer. It must not be called concurrently with Push and Pop
+func (r *LockFreeSPSCRingBufferOf[T]) Reset() {
+	// 清空所有的元素，避免缓冲区继续引用它们
+	// Clear all the elements so the buffer no longer references them
+	var zero T
+	for i := range r.data {
+		r.data[i] = zero
+	}
+
+	// 将头部位置、尾部位置以及双方缓存的位置都设置为 0
+	// Set the head position, the tail position and the positions cached by both sides all to 0
+	atomic.StoreInt64(&r.head, 0)
+	atomic.StoreInt64(&r.tail, 0)
+	r.cachedHead = 0
+	r.cachedTail = 0
+}
+
+// Push 方法用于向环形缓冲区中推入一个元素，如果缓冲区已满，返回 false。只能由唯一的生产者调用
+// The Push method is used to push an element into the ring buffer, returns false if the buffer is full. It must only be called by the single producer
+func (r *LockFreeSPSCRingBufferOf[T]) Push(value T) bool {
+	// 尾部位置只由生产者修改，不需要原子读取
+	// The tail position is only modified by the producer, no atomic load is needed
+	tail := r.tail
+
+	// 根据缓存的头部位置判断缓冲区已满时，重新读取头部位置
+	// Reload the head position when the buffer is full according to the cached head position
+	if tail-r.cachedHead == r.capacity {
+		r.cachedHead = atomic.LoadInt64(&r.head)
+		if tail-r.cachedHead == r.capacity {
+			r.stats.Inc(shd.CounterFullRejections)
+			return false
+		}
+	}
+
+	// 写入值，然后通过推进尾部位置发布该值
+	// Write the value, then publish it by advancing the tail position
+	r.data[r.index(tail)] = value
+	atomic.StoreInt64(&r.tail, tail+1)
+	r.stats.Inc(shd.CounterPushes)
+
+	// 返回 true，表示成功推入元素
+	// Return true, indicating that the element was successfully pushed
+	return true
+}
+
+// Pop 方法用于从环形缓冲区中弹出一个元素，如果缓冲区为空，返回 T 的零值和 false。只能由唯一的消费者调用
+// The Pop method is used to pop an element from the ring buffer, returns the zero value of T and false if the buffer is empty. It must only be called by the single consumer
+func (r *LockFreeSPSCRingBufferOf[T]) Pop() (T, bool) {
+	// 头部位置只由消费者修改，不需要原子读取
+	// The head position is only modified by the consumer, no atomic load is needed
+	head := r.head
+
+	// 根据缓存的尾部位置判断缓冲区为空时，重新读取尾部位置
+	// Reload the tail position when the buffer is empty according to the cached tail position
+	if head == r.cachedTail {
+		r.cachedTail = atomic.LoadInt64(&r.tail)
+		if head == r.cachedTail {
+			r.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+	}
+
+	// 读取值并清空槽位，然后通过推进头部位置把槽位交还给生产者
+	// Read the value and clear the slot, then hand the slot back to the producer by advancing the head position
+	slot := &r.data[r.index(head)]
+	value := *slot
+	var zero T
+	*slot = zero
+	atomic.StoreInt64(&r.head, head+1)
+	r.stats.Inc(shd.CounterPops)
+
+	// 返回值和 true
+	// Return the value and true
+	return value, true
+}
+
+// Stats 方法用于返回环形缓冲区的统计信息快照，没有启用 WithStats 选项时返回零值。单生产者单消费者缓冲区不使用 CAS 操作，因此 CASFailures 始终为 0
+// The Stats method is used to return a snapshot of the statistics of the ring buffer, the zero value is returned when the WithStats option is not enabled. The single-producer single-consumer buffer uses no CAS operations, so CASFailures is always 0
+func (r *LockFreeSPSCRingBufferOf[T]) Stats() Stats {
+	return r.stats.Snapshot()
+}
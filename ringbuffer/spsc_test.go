@@ -0,0 +1,149 @@
+package ringbuffer
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeSPSCRingBuffer_Standard(t *testing.T) {
+	// The SPSC ring buffer is used through the same interface as the MPMC one
+	var r RingBuffer = NewSPSC(5)
+
+	// Push values into the ring buffer until it is full
+	for i := 0; i < 5; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+	}
+	assert.False(t, r.Push(5), "Pushed value when the ring buffer is full")
+	assert.True(t, r.IsFull(), "Ring buffer should be full")
+	assert.Equal(t, int64(5), r.Count(), "Incorrect count. Expected 5, got %d", r.Count())
+
+	// Pop values from the ring buffer in FIFO order
+	for i := 0; i < 5; i++ {
+		value, ok := r.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, value, "Incorrect value popped")
+	}
+	_, ok := r.Pop()
+	assert.False(t, ok, "Popped value when the ring buffer is empty")
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+}
+
+func TestLockFreeSPSCRingBuffer_WrapAround(t *testing.T) {
+	r := NewSPSC(3)
+
+	// Keep the buffer partly filled so the positions wrap around many times
+	next := 0
+	for i := 0; i < 1000; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+		if i%2 == 1 {
+			for j := 0; j < 2; j++ {
+				value, ok := r.Pop()
+				assert.True(t, ok, "Failed to pop value")
+				assert.Equal(t, next, value, "Incorrect value popped")
+				next++
+			}
+		}
+	}
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+}
+
+func TestLockFreeSPSCRingBuffer_PowerOfTwo(t *testing.T) {
+	// The capacity is rounded up to 8 and the slots are found with a mask
+	r := NewSPSCOf[int](5, WithPowerOfTwo())
+	assert.Equal(t, int64(8), r.Capacity(), "Incorrect capacity. Expected 8, got %d", r.Capacity())
+
+	// Keep the buffer partly filled so the positions wrap around many times
+	next := 0
+	for i := 0; i < 1000; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+		if i%4 == 3 {
+			for j := 0; j < 4; j++ {
+				value, ok := r.Pop()
+				assert.True(t, ok, "Failed to pop value")
+				assert.Equal(t, next, value, "Incorrect value popped")
+				next++
+			}
+		}
+	}
+
+	// Fill the buffer up to its rounded capacity
+	for i := 0; i < 8; i++ {
+		assert.True(t, r.Push(i), "Failed to push value: %d", i)
+	}
+	assert.False(t, r.Push(8), "Pushed value when the ring buffer is full")
+	assert.True(t, r.IsFull(), "Ring buffer should be full")
+}
+
+func TestLockFreeSPSCRingBuffer_Reset(t *testing.T) {
+	r := NewSPSC(4)
+
+	for i := 0; i < 4; i++ {
+		r.Push(i)
+	}
+	r.Pop()
+
+	// Reset the ring buffer and verify that it is usable again
+	r.Reset()
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty after reset")
+	for i := 0; i < 4; i++ {
+		assert.True(t, r.Push(i), "Failed to push value after reset: %d", i)
+	}
+	value, ok := r.Pop()
+	assert.True(t, ok, "Failed to pop value after reset")
+	assert.Equal(t, 0, value, "Incorrect value popped after reset")
+}
+
+func TestLockFreeSPSCRingBuffer_Pipeline(t *testing.T) {
+	count := 200000
+	r := NewSPSCOf[int](64)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	// The producer pushes every value in order, retrying while the buffer is full
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; {
+			if r.Push(i) {
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	// The consumer pops every value and checks that none is lost, duplicated or reordered
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; {
+			if value, ok := r.Pop(); ok {
+				assert.Equal(t, i, value, "Incorrect value popped")
+				i++
+			} else {
+				runtime.Gosched()
+			}
+		}
+	}()
+	wg.Wait()
+
+	assert.True(t, r.IsEmpty(), "Ring buffer should be empty")
+}
+
+func TestLockFreeSPSCRingBuffer_Stats(t *testing.T) {
+	rb := NewSPSC(2, WithStats())
+	for i := 0; i < 3; i++ {
+		rb.Push(i)
+	}
+	for i := 0; i < 3; i++ {
+		rb.Pop()
+	}
+
+	s := rb.Stats()
+	assert.Equal(t, uint64(2), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(2), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), s.FullRejections, "Incorrect number of full rejections")
+	assert.Equal(t, uint64(1), s.EmptyPops, "Incorrect number of empty pops")
+}
@@ -0,0 +1,192 @@
+package ringbuffer
+
+import (
+	"context"
+	"time"
+)
+
+// LockFreeRingBuffer 是一个无锁环形缓冲区的结构体，元素的类型为 interface{}
+// LockFreeRingBuffer is a structure of a lock-free ring buffer, the type of the elements is interface{}
+type LockFreeRingBuffer LockFreeRingBufferOf[interface{}]
+
+// New 是一个函数，用于创建一个新的 LockFreeRingBuffer 实例，可以通过选项修改缓冲区的行为
+// New is a function that creates a new instance of LockFreeRingBuffer, the behavior of the buffer can be modified with options
+func New(capacity int, opts ...Option) *LockFreeRingBuffer {
+	// 调用 NewOf 函数创建一个新的环形缓冲区，元素的类型为 interface{}
+	// Call the NewOf function to create a new ring buffer, the type of the elements is interface{}
+	return (*LockFreeRingBuffer)(NewOf[interface{}](capacity, opts...))
+}
+
+// of 方法用于将 LockFreeRingBuffer 转换为底层的 LockFreeRingBufferOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeRingBuffer to the underlying LockFreeRingBufferOf[interface{}] without any extra cost
+func (r *LockFreeRingBuffer) of() *LockFreeRingBufferOf[interface{}] {
+	return (*LockFreeRingBufferOf[interface{}])(r)
+}
+
+// IsEmpty 是一个方法，用于检查环形缓冲区是否为空
+// IsEmpty is a method that checks whether the ring buffer is empty
+func (r *LockFreeRingBuffer) IsEmpty() bool {
+	return r.of().IsEmpty()
+}
+
+// IsFull 是一个方法，用于检查环形缓冲区是否已满
+// IsFull is a method that checks whether the ring buffer is full
+func (r *LockFreeRingBuffer) IsFull() bool {
+	return r.of().IsFull()
+}
+
+// Capacity 是一个方法，返回环形缓冲区的容量
+// Capacity is a method that returns the capacity of the ring buffer
+func (r *LockFreeRingBuffer) Capacity() int64 {
+	return r.of().Capacity()
+}
+
+// Count 是一个方法，返回环形缓冲区中的元素数量
+// Count is a method that returns the number of elements in the ring buffer
+func (r *LockFreeRingBuffer) Count() int64 {
+	return r.of().Count()
+}
+
+// Reset 是一个方法，用于重置环形缓冲区
+// Reset is a method that resets the ring buffer
+func (r *LockFreeRingBuffer) Reset() {
+	r.of().Reset()
+}
+
+// Push 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，返回 false，启用 WithOverwrite 时会淘汰最旧的元素
+// The Push method is used to push an element into the lock-free ring buffer, returns false if the buffer is full, evicts the oldest element when WithOverwrite is enabled
+func (r *LockFreeRingBuffer) Push(value interface{}) bool {
+	return r.of().Push(value)
+}
+
+// PushOverwrite 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，淘汰最旧的元素，并返回被淘汰的元素和 true
+// The PushOverwrite method is used to push an element into the lock-free ring buffer, if the buffer is full the oldest element is evicted and returned together with true
+func (r *LockFreeRingBuffer) PushOverwrite(value interface{}) (interface{}, bool) {
+	return r.of().PushOverwrite(value)
+}
+
+// Pop 方法用于从无锁环形缓冲区中弹出一个元素，如果缓冲区为空，返回 nil 和 false
+// The Pop method is used to pop an element from the lock-free ring buffer, returns nil and false if the buffer is empty
+func (r *LockFreeRingBuffer) Pop() (interface{}, bool) {
+	return r.of().Pop()
+}
+
+// PushBatch 方法用于向无锁环形缓冲区中按顺序推入一组元素，返回成功推入的元素数量
+// The PushBatch method is used to push a group of elements into the lock-free ring buffer in order, returning the number of elements pushed
+func (r *LockFreeRingBuffer) PushBatch(values []interface{}) int {
+	return r.of().PushBatch(values)
+}
+
+// PopBatch 方法用于从无锁环形缓冲区中按顺序弹出最多 len(dst) 个元素并写入 dst，返回弹出的元素数量
+// The PopBatch method is used to pop up to len(dst) elements from the lock-free ring buffer in order and write them into dst, returning the number of elements popped
+func (r *LockFreeRingBuffer) PopBatch(dst []interface{}) int {
+	return r.of().PopBatch(dst)
+}
+
+// Peek 方法用于返回头部位置的元素但不移除它，如果缓冲区为空，返回 nil 和 false
+// The Peek method is used to return the element at the head position without removing it, returns nil and false if the buffer is empty
+func (r *LockFreeRingBuffer) Peek() (interface{}, bool) {
+	return r.of().Peek()
+}
+
+// Range 方法用于按 FIFO 顺序遍历环形缓冲区中的元素，但不移除它们，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Range method is used to walk the elements in the ring buffer in FIFO order without removing them, the walk stops when fn returns false. The walk is weakly consistent
+func (r *LockFreeRingBuffer) Range(fn func(value interface{}) bool) {
+	r.of().Range(fn)
+}
+
+// Snapshot 方法用于按 FIFO 顺序返回环形缓冲区中所有元素的副本，但不移除它们
+// The Snapshot method is used to return a copy of all the elements in the ring buffer in FIFO order without removing them
+func (r *LockFreeRingBuffer) Snapshot() []interface{} {
+	return r.of().Snapshot()
+}
+
+// PopWait 方法用于从无锁环形缓冲区中弹出一个元素，如果缓冲区为空，会等待直到有元素可用或者 ctx 结束
+// The PopWait method is used to pop an element from the lock-free ring buffer, if the buffer is empty it waits until an element is available or ctx is done
+func (r *LockFreeRingBuffer) PopWait(ctx context.Context) (interface{}, error) {
+	return r.of().PopWait(ctx)
+}
+
+// PushWait 方法用于向无锁环形缓冲区中推入一个元素，如果缓冲区已满，会等待直到有空闲槽位或者 ctx 结束
+// The PushWait method is used to push an element into the lock-free ring buffer, if the buffer is full it waits until a slot is free or ctx is done
+func (r *LockFreeRingBuffer) PushWait(ctx context.Context, value interface{}) error {
+	return r.of().PushWait(ctx, value)
+}
+
+// PushTimeout 方法用于向无锁环形缓冲区中推入一个元素，如果在 timeout 时间内没有空闲槽位，返回 context.DeadlineExceeded
+// The PushTimeout method is used to push an element into the lock-free ring buffer, returns context.DeadlineExceeded if no slot becomes free within timeout
+func (r *LockFreeRingBuffer) PushTimeout(value interface{}, timeout time.Duration) error {
+	return r.of().PushTimeout(value, timeout)
+}
+
+// Stats 是一个方法，返回环形缓冲区的统计信息快照，没有启用 WithStats 选项时返回零值
+// Stats is a method that returns a snapshot of the statistics of the ring buffer, the zero value is returned when the WithStats option is not enabled
+func (r *LockFreeRingBuffer) Stats() Stats {
+	return r.of().Stats()
+}
+
+// LockFreeSPSCRingBuffer 是一个单生产者单消费者无锁环形缓冲区的结构体，元素的类型为 interface{}
+// LockFreeSPSCRingBuffer is a structure of a single-producer single-consumer lock-free ring buffer, the type of the elements is interface{}
+type LockFreeSPSCRingBuffer LockFreeSPSCRingBufferOf[interface{}]
+
+// NewSPSC 是一个函数，用于创建一个新的 LockFreeSPSCRingBuffer 实例，同一时刻最多只能有一个生产者和一个消费者。只有 WithStats 和 WithPowerOfTwo 选项对它有效
+// NewSPSC is a function that creates a new instance of LockFreeSPSCRingBuffer, there may be at most one producer and one consumer at the same time. Only the WithStats and WithPowerOfTwo options apply to it
+func NewSPSC(capacity int, opts ...Option) *LockFreeSPSCRingBuffer {
+	// 调用 NewSPSCOf 函数创建一个新的环形缓冲区，元素的类型为 interface{}
+	// Call the NewSPSCOf function to create a new ring buffer, the type of the elements is interface{}
+	return (*LockFreeSPSCRingBuffer)(NewSPSCOf[interface{}](capacity, opts...))
+}
+
+// of 方法用于将 LockFreeSPSCRingBuffer 转换为底层的 LockFreeSPSCRingBufferOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeSPSCRingBuffer to the underlying LockFreeSPSCRingBufferOf[interface{}] without any extra cost
+func (r *LockFreeSPSCRingBuffer) of() *LockFreeSPSCRingBufferOf[interface{}] {
+	return (*LockFreeSPSCRingBufferOf[interface{}])(r)
+}
+
+// IsEmpty 是一个方法，用于检查环形缓冲区是否为空
+// IsEmpty is a method that checks whether the ring buffer is empty
+func (r *LockFreeSPSCRingBuffer) IsEmpty() bool {
+	return r.of().IsEmpty()
+}
+
+// IsFull 是一个方法，用于检查环形缓冲区是否已满
+// IsFull is a method that checks whether the ring buffer is full
+func (r *LockFreeSPSCRingBuffer) IsFull() bool {
+	return r.of().IsFull()
+}
+
+// Capacity 是一个方法，返回环形缓冲区的容量
+// Capacity is a method that returns the capacity of the ring buffer
+func (r *LockFreeSPSCRingBuffer) Capacity() int64 {
+	return r.of().Capacity()
+}
+
+// Count 是一个方法，返回环形缓冲区中的元素数量
+// Count is a method that returns the number of elements in the ring buffer
+func (r *LockFreeSPSCRingBuffer) Count() int64 {
+	return r.of().Count()
+}
+
+// Reset 是一个方法，用于重置环形缓冲区，它不能与 Push 和 Pop 并发调用
+// Reset is a method that resets the ring buffer, it must not be called concurrently with Push and Pop
+func (r *LockFreeSPSCRingBuffer) Reset() {
+	r.of().Reset()
+}
+
+// Push 方法用于向环形缓冲区中推入一个元素，如果缓冲区已满，返回 false。只能由唯一的生产者调用
+// The Push method is used to push an element into the ring buffer, returns false if the buffer is full. It must only be called by the single producer
+func (r *LockFreeSPSCRingBuffer) Push(value interface{}) bool {
+	return r.of().Push(value)
+}
+
+// Pop 方法用于从环形缓冲区中弹出一个元素，如果缓冲区为空，返回 nil 和 false。只能由唯一的消费者调用
+// The Pop method is used to pop an element from the ring buffer, returns nil and false if the buffer is empty. It must only be called by the single consumer
+func (r *LockFreeSPSCRingBuffer) Pop() (interface{}, bool) {
+	return r.of().Pop()
+}
+
+// Stats 是一个方法，返回环形缓冲区的统计信息快照，没有启用 WithStats 选项时返回零值
+// Stats is a method that returns a snapshot of the statistics of the ring buffer, the zero value is returned when the WithStats option is not enabled
+func (r *LockFreeSPSCRingBuffer) Stats() Stats {
+	return r.of().Stats()
+}
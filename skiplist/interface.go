@@ -0,0 +1,51 @@
+package skiplist
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是跳表统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a skiplist, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// SkipList 是一个接口，定义了有序集合的基本操作
+// SkipList is an interface that defines basic operations of an ordered set
+type SkipList = interface {
+	// Insert 方法用于插入一个键，键已经存在时返回 false
+	// The Insert method is used to insert a key, returns false if the key already exists
+	Insert(key interface{}) bool
+
+	// Delete 方法用于删除一个键，键不存在时返回 false
+	// The Delete method is used to delete a key, returns false if the key does not exist
+	Delete(key interface{}) bool
+
+	// Contains 方法用于判断一个键是否存在
+	// The Contains method is used to determine whether a key exists
+	Contains(key interface{}) bool
+
+	// Seek 方法用于返回大于或等于给定键的最小的键
+	// The Seek method is used to return the smallest key that is greater than or equal to the given key
+	Seek(key interface{}) interface{}
+
+	// Ascend 方法用于按升序遍历所有的键
+	// The Ascend method is used to walk all the keys in ascending order
+	Ascend(fn func(key interface{}) bool)
+
+	// Descend 方法用于按降序遍历所有的键
+	// The Descend method is used to walk all the keys in descending order
+	Descend(fn func(key interface{}) bool)
+
+	// Reset 方法用于重置/清空有序集合
+	// The Reset method is used to reset/clear the ordered set
+	Reset()
+
+	// Length 方法返回有序集合中键的数量
+	// The Length method returns the number of keys in the ordered set
+	Length() int64
+
+	// IsEmpty 方法用于检查有序集合是否为空
+	// The IsEmpty method is used to check if the ordered set is empty
+	IsEmpty() bool
+}
@@ -0,0 +1,150 @@
+package skiplist
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// levelOf 是节点在某一层的链接。next 指向下一个节点，节点在这一层被逻辑删除后，next 改为指向同一个链接中的 succ，
+// succ 保存删除时的下一个节点。删除标记和下一个节点因此可以通过一次 CAS 操作同时修改，并且不需要为每次 CAS 分配新的对象。
+// levelOf is the link of a node at one level. next points to the next node, once the node is logically deleted at this level next points to succ in the same link instead,
+// and succ holds the next node at the time of the deletion. The deletion mark and the next node can therefore be changed together with a single CAS operation, without allocating a new object for every CAS.
+type levelOf[K any] struct {
+	// next 是下一个节点，类型为 *nodeOf[K]，nil 表示这一层的末尾。指向 succ 时表示节点在这一层已经被删除
+	// next is the next node, the type is *nodeOf[K], nil means the end of this level. It means the node has been deleted at this level when it points to succ
+	next unsafe.Pointer
+
+	// succ 是节点在这一层被删除时的下一个节点，只由删除节点的 goroutine 写入
+	// succ is the next node at the time the node was deleted at this level, it is only written by the goroutine deleting the node
+	succ unsafe.Pointer
+}
+
+// nodeOf 是跳表中的节点，K 为键的类型
+// nodeOf is a node of the skiplist, K is the type of the key
+type nodeOf[K any] struct {
+	// key 是节点的键
+	// key is the key of the node
+	key K
+
+	// levels 是节点在每一层的链接，与节点一起分配，使用节点池时与节点一起复用
+	// levels holds the link of the node at every level, it is allocated with the node and reused with it when a node pool is used
+	levels []levelOf[K]
+
+	// removed 表示键是否已经被删除，只有把它从 0 改为 1 的 goroutine 删除了该键，并负责标记节点的链接
+	// removed indicates whether the key has been deleted, only the goroutine that changes it from 0 to 1 deleted the key and marks the links of the node
+	removed int32
+
+	// done 记录插入和删除中已经结束的数量，两者都结束之后节点才能被回收
+	// done counts how many of the insert and the delete have finished, the node can only be reclaimed after both of them have finished
+	done int32
+
+	// link 是节点回收器串联退休节点时使用的指针
+	// link is the pointer used by the node reclaimer to chain retired nodes
+	link unsafe.Pointer
+}
+
+// loadNext 方法用于加载第 level 层的下一个节点，marked 表示节点在这一层已经被删除，此时返回删除时的下一个节点
+// The loadNext method is used to load the next node at the given level, marked means the node has been deleted at this level, the next node at the time of the deletion is returned then
+func (n *nodeOf[K]) loadNext(level int) (next *nodeOf[K], marked bool) {
+	l := &n.levels[level]
+	p := atomic.LoadPointer(&l.next)
+	if p == unsafe.Pointer(&l.succ) {
+		return (*nodeOf[K])(atomic.LoadPointer(&l.succ)), true
+	}
+	return (*nodeOf[K])(p), false
+}
+
+// storeNext 方法用于设置第 level 层的下一个节点
+// The storeNext method is used to set the next node at the given level
+func (n *nodeOf[K]) storeNext(level int, next *nodeOf[K]) {
+	atomic.StorePointer(&n.levels[level].next, unsafe.Pointer(next))
+}
+
+// casNext 方法用于比较并交换第 level 层的下一个节点，节点在这一层已经被删除时总是失败
+// The casNext method is used to compare and swap the next node at the given level, it always fails once the node has been deleted at this level
+func (n *nodeOf[K]) casNext(level int, old, new *nodeOf[K]) bool {
+	return atomic.CompareAndSwapPointer(&n.levels[level].next, unsafe.Pointer(old), unsafe.Pointer(new))
+}
+
+// mark 方法用于在第 level 层逻辑删除节点，只能由删除节点的 goroutine 调用，因此 succ 只有一个写入者
+// The mark method is used to logically delete the node at the given level, it must only be called by the goroutine deleting the node, so succ has a single writer
+func (n *nodeOf[K]) mark(level int) {
+	l := &n.levels[level]
+	for {
+		p := atomic.LoadPointer(&l.next)
+		if p == unsafe.Pointer(&l.succ) {
+			return
+		}
+		atomic.StorePointer(&l.succ, p)
+		if atomic.CompareAndSwapPointer(&l.next, p, unsafe.Pointer(&l.succ)) {
+			return
+		}
+	}
+}
+
+// remove 方法用于占用节点的删除，只有第一个调用返回 true
+// The remove method is used to claim the deletion of the node, only the first call returns true
+func (n *nodeOf[K]) remove() bool {
+	return atomic.CompareAndSwapInt32(&n.removed, 0, 1)
+}
+
+// deleted 方法用于判断节点的键是否已经被删除，删除在 remove 成功时生效，节点的链接之后才会被标记
+// The deleted method is used to determine whether the key of the node has been deleted, the delete takes effect when remove succeeds and the links of the node are marked afterwards
+func (n *nodeOf[K]) deleted() bool {
+	return atomic.LoadInt32(&n.removed) != 0
+}
+
+// reset 方法用于重置节点的所有字段，保留 levels 切片的底层数组以便复用
+// The reset method is used to reset all the fields of the node, the backing array of the levels slice is kept for reuse
+func (n *nodeOf[K]) reset() {
+	var zero K
+	n.key = zero
+	for i := range n.levels {
+		n.levels[i] = levelOf[K]{}
+	}
+	n.levels = n.levels[:0]
+	n.removed = 0
+	n.done = 0
+	n.link = nil
+}
+
+// nodePoolOf 是跳表节点的节点池
+// nodePoolOf is the node pool of skiplist nodes
+type nodePoolOf[K any] struct {
+	// pool 是一个同步池，用于存储和获取节点
+	// pool is a sync pool, used to store and retrieve nodes
+	pool sync.Pool
+
+	// stats 是节点池的统计计数器，为 nil 时不统计，必须在使用节点池之前设置
+	// stats are the statistics counters of the node pool, nothing is counted when it is nil, they must be set before the node pool is used
+	stats *shd.Counters
+}
+
+// newNodePoolOf 函数用于创建一个新的节点池，节点的 levels 切片按最大层数分配，复用时不需要重新分配
+// The newNodePoolOf function is used to create a new node pool, the levels slice of a node is allocated with the maximum number of levels so it never needs to be reallocated on reuse
+func newNodePoolOf[K any]() *nodePoolOf[K] {
+	np := &nodePoolOf[K]{}
+	np.pool.New = func() interface{} {
+		np.stats.Inc(shd.CounterPoolMisses)
+		return &nodeOf[K]{levels: make([]levelOf[K], 0, maxLevel)}
+	}
+	return np
+}
+
+// get 方法用于从节点池中获取一个节点
+// The get method is used to get a node from the node pool
+func (np *nodePoolOf[K]) get() *nodeOf[K] {
+	np.stats.Inc(shd.CounterPoolGets)
+	return np.pool.Get().(*nodeOf[K])
+}
+
+// put 方法用于重置一个节点并将其放回节点池
+// The put method is used to reset a node and put it back into the node pool
+func (np *nodePoolOf[K]) put(n *nodeOf[K]) {
+	n.reset()
+	np.stats.Inc(shd.CounterPoolPuts)
+	np.pool.Put(n)
+}
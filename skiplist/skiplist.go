@@ -0,0 +1,530 @@
+package skiplist
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// maxLevel 是跳表的最大层数，每一层的节点数量约为下一层的 1/4，足以容纳 4^16 个键
+// maxLevel is the maximum number of levels of the skiplist, every level holds about 1/4 of the nodes of the level below, enough for 4^16 keys
+const maxLevel = 16
+
+// LockFreeSkipListOf 是一个泛型无锁跳表，保存一组按比较函数升序排列的不重复的键，K 为键的类型。
+// 删除一个键时，先通过一次 CAS 操作占用节点，这是删除生效的时刻，之后自顶向下逻辑删除节点的每一层，再把节点从每一层物理地移除。
+// LockFreeSkipListOf is a generic lock-free skiplist holding a set of distinct keys sorted in ascending order by the comparator, K is the type of the keys.
+// A key is deleted by claiming its node with a CAS operation, which is the moment the delete takes effect, after which every level of the node is logically deleted from the top down and the node is physically removed from every level.
+type LockFreeSkipListOf[K any] struct {
+	// length 是跳表中键的数量
+	// length is the number of keys in the skiplist
+	length int64
+
+	// _ 用于把每次插入和删除都会修改的长度与序号计数器分隔到不同的缓存行
+	// _ separates the length, modified by every insert and delete, from the sequence counter onto different cache lines
+	_ shd.CacheLinePad
+
+	// seq 是插入序号计数器，用于计算新节点的层数
+	// seq is the insert sequence counter, it is used to compute the number of levels of new nodes
+	seq uint64
+
+	// _ 用于把序号计数器与之后只读的字段分隔到不同的缓存行
+	// _ separates the sequence counter from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
+	// head 是跳表的头节点，它小于所有的键，并且永远不会被删除
+	// head is the head node of the skiplist, it is less than every key and it is never deleted
+	head *nodeOf[K]
+
+	// compare 是比较函数，a 小于、等于、大于 b 时分别返回负数、0、正数
+	// compare is the comparator, it returns a negative number, 0 or a positive number when a is less than, equal to or greater than b
+	compare func(a, b K) int
+
+	// pool 是一个节点池，用于存储和获取节点
+	// pool is a node pool used to store and retrieve nodes
+	pool *nodePoolOf[K]
+
+	// reclaimer 是节点回收器，仅在使用节点池时启用，保证节点在没有 goroutine 引用后才会被复用
+	// reclaimer is the node reclaimer, only enabled with a node pool, it ensures nodes are reused only after no goroutine references them
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// NewOf 函数用于创建一个新的泛型 LockFreeSkipListOf 跳表，compare 是键的比较函数，可以通过选项修改跳表的行为
+// The NewOf function is used to create a new generic LockFreeSkipListOf skiplist, compare is the comparator of the keys, the behavior of the skiplist can be modified with options
+func NewOf[K any](compare func(a, b K) int, opts ...Option) *LockFreeSkipListOf[K] {
+	// 调用 newLFSL 函数创建一个新的 LockFreeSkipListOf 跳表，是否使用节点池由选项决定
+	// Call the newLFSL function to create a new LockFreeSkipListOf skiplist, whether a node pool is used is decided by the options
+	return newLFSL(compare, opts)
+}
+
+// NewWithPoolOf 函数用于创建一个新的泛型 LockFreeSkipListOf 跳表，该跳表使用一个节点池，compare 是键的比较函数，可以通过选项修改跳表的行为。
+// 它是 NewOf 加上 WithPool 选项的简写
+// The NewWithPoolOf function is used to create a new generic LockFreeSkipListOf skiplist, this skiplist uses a node pool, compare is the comparator of the keys, the behavior of the skiplist can be modified with options.
+// It is a shortcut for NewOf with the WithPool option
+func NewWithPoolOf[K any](compare func(a, b K) int, opts ...Option) *LockFreeSkipListOf[K] {
+	return newLFSL(compare, append([]Option{WithPool()}, opts...))
+}
+
+// newLFSL 函数用于创建一个新的 LockFreeSkipListOf 跳表，参数为比较函数和所有的选项
+// The newLFSL function is used to create a new LockFreeSkipListOf skiplist, the parameters are the comparator and all the options
+func newLFSL[K any](compare func(a, b K) int, opts []Option) *LockFreeSkipListOf[K] {
+	// 应用所有的选项，如果启用了节点池，那么创建节点池
+	// Apply all the options, if the node pool is enabled, then create the node pool
+	conf := newConfig(opts)
+	var pool *nodePoolOf[K]
+	if conf.pool {
+		pool = newNodePoolOf[K]()
+	}
+
+	// 创建头节点，每一层都指向这一层的末尾
+	// Create the head node, every level points to the end of the level
+	head := &nodeOf[K]{levels: make([]levelOf[K], maxLevel)}
+
+	// 创建一个新的 LockFreeSkipListOf 跳表
+	// Create a new LockFreeSkipListOf skiplist
+	s := &LockFreeSkipListOf[K]{head: head, compare: compare, pool: pool}
+
+	// 如果使用节点池，那么创建节点回收器，宽限期结束后节点会被放回节点池
+	// If a node pool is used, then create the node reclaimer, nodes are put back into the node pool once their grace period is over
+	if pool != nil {
+		s.reclaimer = shd.NewReclaimer(
+			func(p unsafe.Pointer) *unsafe.Pointer { return &(*nodeOf[K])(p).link },
+			func(p unsafe.Pointer) { pool.put((*nodeOf[K])(p)) },
+		)
+	}
+
+	// 设置退避策略，如果启用了统计，那么创建统计计数器，节点池也使用同一组计数器
+	// Set the backoff strategy, if statistics are enabled, then create the statistics counters, the node pool uses the same counters
+	s.backoff = conf.backoff
+	if conf.stats {
+		s.stats = shd.NewCounters()
+		if pool != nil {
+			pool.stats = s.stats
+		}
+	}
+
+	// 返回新创建的跳表
+	// Return the newly created skiplist
+	return s
+}
+
+// randomLevel 函数用于根据插入序号计算节点的层数，第 k 层出现的概率为 1/4^(k-1)。序号经过 splitmix64 混合，不需要共享的随机数生成器
+// The randomLevel function is used to compute the number of levels of a node from its insert sequence, level k appears with probability 1/4^(k-1). The sequence is mixed with splitmix64, so no shared random generator is needed
+func randomLevel(seq uint64) int {
+	z := seq + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+
+	level := bits.TrailingZeros64(z)/2 + 1
+	if level > maxLevel {
+		level = maxLevel
+	}
+	return level
+}
+
+// newNode 方法用于创建一个有 level 层的新节点，如果使用节点池，那么从节点池中获取
+// The newNode method is used to create a new node with the given number of levels, the node is taken from the node pool if one is used
+func (s *LockFreeSkipListOf[K]) newNode(key K, level int) *nodeOf[K] {
+	var node *nodeOf[K]
+	if s.pool != nil {
+		node = s.pool.get()
+		node.levels = node.levels[:level]
+	} else {
+		node = &nodeOf[K]{levels: make([]levelOf[K], level)}
+	}
+	node.key = key
+	return node
+}
+
+// find 方法用于在每一层找到键小于 key (inclusive 为 true 时为小于或等于) 的最后一个节点，写入 preds，并把它的下一个节点写入 succs。
+// 查找过程中遇到的已经被逻辑删除的节点会被物理地移除。返回最底层的下一个节点的键是否等于 key 并且没有被删除。
+// The find method is used to find the last node whose key is less than key (less than or equal to when inclusive is true) at every level, writing it into preds and its next node into succs.
+// Logically deleted nodes met during the search are physically removed. It returns whether the key of the next node at the bottom level equals key and has not been deleted.
+func (s *LockFreeSkipListOf[K]) find(key K, inclusive bool, preds, succs []*nodeOf[K]) bool {
+retry:
+	pred := s.head
+	var predNext *nodeOf[K]
+	for level := maxLevel - 1; level >= 0; level-- {
+		// 如果前驱节点在这一层已经被删除，那么从头开始重新查找
+		// If the predecessor has been deleted at this level, then restart the search from the head
+		var marked bool
+		predNext, marked = pred.loadNext(level)
+		if marked {
+			goto retry
+		}
+
+		for curr := predNext; curr != nil; curr = predNext {
+			currNext, marked := curr.loadNext(level)
+
+			// 当前节点已经被删除，把它从这一层移除，失败说明前驱节点发生了变化，从头开始重新查找
+			// The current node has been deleted, remove it from this level, a failure means the predecessor has changed, restart the search from the head
+			if marked {
+				if !pred.casNext(level, curr, currNext) {
+					goto retry
+				}
+				predNext = currNext
+				continue
+			}
+
+			// 当前节点的键不小于 (inclusive 为 true 时为大于) 目标键，停止在这一层前进
+			// The key of the current node is not less than (greater than when inclusive is true) the target key, stop moving forward at this level
+			c := s.compare(curr.key, key)
+			if c > 0 || (c == 0 && !inclusive) {
+				break
+			}
+			pred, predNext = curr, currNext
+		}
+
+		if preds != nil {
+			preds[level] = pred
+			succs[level] = predNext
+		}
+	}
+
+	// 已经被删除但还没有被标记的节点不算存在，新插入的相同键的节点会排在它前面
+	// A node that has been deleted but not marked yet does not count, a newly inserted node with the same key goes in front of it
+	return predNext != nil && s.compare(predNext.key, key) == 0 && !predNext.deleted()
+}
+
+// lowerBound 方法用于只读地查找键大于或等于 key 的第一个没有被删除的节点，不存在时返回 nil
+// The lowerBound method is used to search read-only for the first node that has not been deleted whose key is greater than or equal to key, it returns nil if there is none
+func (s *LockFreeSkipListOf[K]) lowerBound(key K) *nodeOf[K] {
+	pred := s.head
+	var curr *nodeOf[K]
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr, _ = pred.loadNext(level)
+		for curr != nil {
+			// 越过已经被删除的节点
+			// Step over deleted nodes
+			next, marked := curr.loadNext(level)
+			if marked || curr.deleted() {
+				curr = next
+				continue
+			}
+			if s.compare(curr.key, key) >= 0 {
+				break
+			}
+			pred, curr = curr, next
+		}
+	}
+	return curr
+}
+
+// finish 方法用于在插入或者删除结束时调用，两者都结束之后，把节点从所有层中移除并退休
+// The finish method is called when the insert or the delete finishes, once both have finished the node is removed from every level and retired
+func (s *LockFreeSkipListOf[K]) finish(node *nodeOf[K]) {
+	if s.pool == nil {
+		return
+	}
+	if atomic.AddInt32(&node.done, 1) == 2 {
+		// 相同的键可能有一个新插入的节点排在它前面，所以查找需要越过所有相等的键
+		// A newly inserted node with the same key may sit in front of it, so the search has to step over every equal key
+		s.find(node.key, true, nil, nil)
+		s.reclaimer.Retire(unsafe.Pointer(node))
+	}
+}
+
+// Insert 方法用于向跳表中插入一个键，如果键已经存在，返回 false
+// The Insert method is used to insert a key into the skiplist, returns false if the key already exists
+func (s *LockFreeSkipListOf[K]) Insert(key K) bool {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	var preds, succs [maxLevel]*nodeOf[K]
+	var node *nodeOf[K]
+	var level int
+	attempt := 0
+
+	// 在最底层插入节点，插入成功后键就存在于跳表中
+	// Insert the node at the bottom level, the key exists in the skiplist once the insertion succeeds
+	for {
+		if s.find(key, false, preds[:], succs[:]) {
+			// 键已经存在，归还还没有发布的节点
+			// The key already exists, give back the node that has not been published
+			if node != nil && s.pool != nil {
+				s.pool.put(node)
+			}
+			return false
+		}
+		if node == nil {
+			level = randomLevel(atomic.AddUint64(&s.seq, 1))
+			node = s.newNode(key, level)
+		}
+		for i := 0; i < level; i++ {
+			node.storeNext(i, succs[i])
+		}
+		if preds[0].casNext(0, succs[0], node) {
+			break
+		}
+
+		// 前驱节点在最底层已经改变，记录一次 CAS 失败，退避之后重新查找
+		// The predecessor has changed at the bottom level, count a CAS failure, back off and search again
+		s.stats.Inc(shd.CounterCASFailures)
+		s.backoff.Wait(&attempt)
+	}
+
+	// 增加跳表的长度
+	// Increase the length of the skiplist
+	atomic.AddInt64(&s.length, 1)
+	s.stats.Inc(shd.CounterPushes)
+
+	// 自底向上把节点链接到上面的层，上面的层只用于加速查找。如果节点已经被删除，停止链接
+	// Link the node into the upper levels from the bottom up, the upper levels only speed up searches. Stop linking if the node has already been deleted
+	for i := 1; i < level; i++ {
+		for {
+			next, marked := node.loadNext(i)
+			if marked {
+				s.finish(node)
+				return true
+			}
+			if next != succs[i] && !node.casNext(i, next, succs[i]) {
+				continue
+			}
+			if preds[i].casNext(i, succs[i], node) {
+				break
+			}
+			s.stats.Inc(shd.CounterCASFailures)
+			s.backoff.Wait(&attempt)
+			s.find(key, false, preds[:], succs[:])
+		}
+	}
+
+	s.finish(node)
+	return true
+}
+
+// Delete 方法用于从跳表中删除一个键，如果键不存在，返回 false
+// The Delete method is used to delete a key from the skiplist, returns false if the key does not exist
+func (s *LockFreeSkipListOf[K]) Delete(key K) bool {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	var preds, succs [maxLevel]*nodeOf[K]
+	if !s.find(key, false, preds[:], succs[:]) {
+		return false
+	}
+	node := succs[0]
+
+	// 通过 CAS 操作占用节点，只有成功的 goroutine 删除了该键，失败说明它已经被其他 goroutine 删除
+	// Claim the node with a CAS operation, only the goroutine that succeeds deleted the key, a failure means it has been deleted by another goroutine
+	if !node.remove() {
+		s.stats.Inc(shd.CounterCASFailures)
+		return false
+	}
+
+	// 只有占用了节点的 goroutine 会自顶向下标记它的每一层
+	// Only the goroutine that claimed the node marks every level of it from the top down
+	for i := len(node.levels) - 1; i >= 0; i-- {
+		node.mark(i)
+	}
+
+	// 减少跳表的长度，然后物理地移除节点
+	// Decrease the length of the skiplist, then physically remove the node
+	atomic.AddInt64(&s.length, -1)
+	s.stats.Inc(shd.CounterPops)
+	s.find(key, true, nil, nil)
+	s.finish(node)
+	return true
+}
+
+// Contains 方法用于判断跳表中是否存在键 key，它不会修改跳表
+// The Contains method is used to determine whether key exists in the skiplist, it never modifies the skiplist
+func (s *LockFreeSkipListOf[K]) Contains(key K) bool {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	node := s.lowerBound(key)
+	return node != nil && s.compare(node.key, key) == 0
+}
+
+// Seek 方法用于返回大于或等于 key 的最小的键，如果不存在，返回 K 的零值和 false
+// The Seek method is used to return the smallest key that is greater than or equal to key, returns the zero value of K and false if there is none
+func (s *LockFreeSkipListOf[K]) Seek(key K) (K, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	if node := s.lowerBound(key); node != nil {
+		return node.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+// ascend 方法用于从 node 开始沿最底层按升序遍历没有被删除的键，fn 返回 false 时停止遍历
+// The ascend method is used to walk the keys that have not been deleted in ascending order along the bottom level starting from node, the walk stops when fn returns false
+func (s *LockFreeSkipListOf[K]) ascend(node *nodeOf[K], fn func(key K) bool) {
+	for node != nil {
+		next, _ := node.loadNext(0)
+		if !node.deleted() && !fn(node.key) {
+			return
+		}
+		node = next
+	}
+}
+
+// Ascend 方法用于按升序遍历跳表中的所有键，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Ascend method is used to walk all the keys in the skiplist in ascending order, the walk stops when fn returns false. The walk is weakly consistent
+func (s *LockFreeSkipListOf[K]) Ascend(fn func(key K) bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	first, _ := s.head.loadNext(0)
+	s.ascend(first, fn)
+}
+
+// AscendFrom 方法用于按升序遍历跳表中大于或等于 key 的键，fn 返回 false 时停止遍历。遍历是弱一致的
+// The AscendFrom method is used to walk the keys greater than or equal to key in the skiplist in ascending order, the walk stops when fn returns false. The walk is weakly consistent
+func (s *LockFreeSkipListOf[K]) AscendFrom(key K, fn func(key K) bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	s.ascend(s.lowerBound(key), fn)
+}
+
+// descend 方法用于按降序遍历 from 之后、键小于 bound (inclusive 为 true 时为小于或等于，unbounded 为 true 时不限制) 的没有被删除的键，fn 返回 false 时停止遍历并返回 false。
+// 跳表只有向前的指针，所以先在第 level 层把范围内的节点压入 stack，再从后往前处理：每个节点之后的区间交给下一层递归遍历，然后访问节点本身。
+// 每个区间只由它两端的节点界定，所以每一层的每个链接只会被访问一次，完整的遍历是 O(n) 的，而 stack 中只保存每一层少量的节点
+// The descend method is used to walk the keys that have not been deleted after from and less than bound (less than or equal to when inclusive is true, without a limit when unbounded is true) in descending order, the walk stops and returns false when fn returns false.
+// The skiplist only has forward pointers, so the nodes in range at the given level are pushed onto stack first and then handled from the back: the gap after every node is walked recursively at the level below, then the node itself is visited.
+// Every gap is bounded only by the nodes at its two ends, so every link of every level is visited once and a full walk is O(n), while stack only holds a few nodes per level
+func (s *LockFreeSkipListOf[K]) descend(level int, from *nodeOf[K], bound K, inclusive, unbounded bool, stack *[]*nodeOf[K], fn func(key K) bool) bool {
+	// 把这一层范围内的节点按升序压入 stack，越过已经被删除的节点
+	// Push the nodes in range at this level onto stack in ascending order, stepping over deleted nodes
+	base := len(*stack)
+	curr, _ := from.loadNext(level)
+	for curr != nil {
+		next, marked := curr.loadNext(level)
+		if !marked {
+			if !unbounded {
+				c := s.compare(curr.key, bound)
+				if c > 0 || (c == 0 && !inclusive) {
+					break
+				}
+			}
+			*stack = append(*stack, curr)
+		}
+		curr = next
+	}
+
+	// 从后往前处理节点，递归调用会在 stack 的末尾追加节点，所以每次都通过下标读取
+	// Handle the nodes from the back, the recursive calls append to the end of stack, so every node is read by its index
+	for i := len(*stack) - 1; i >= base; i-- {
+		node := (*stack)[i]
+		if level > 0 && !s.descend(level-1, node, bound, inclusive, unbounded, stack, fn) {
+			return false
+		}
+		if !node.deleted() && !fn(node.key) {
+			return false
+		}
+
+		// 下一个区间的上界是当前节点，不包含它本身
+		// The upper bound of the next gap is the current node, excluding the node itself
+		bound, inclusive, unbounded = node.key, false, false
+	}
+	*stack = (*stack)[:base]
+
+	// 最后遍历 from 与这一层第一个节点之间的区间
+	// Finally walk the gap between from and the first node at this level
+	if level > 0 {
+		return s.descend(level-1, from, bound, inclusive, unbounded, stack, fn)
+	}
+	return true
+}
+
+// Descend 方法用于按降序遍历跳表中的所有键，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Descend method is used to walk all the keys in the skiplist in descending order, the walk stops when fn returns false. The walk is weakly consistent
+func (s *LockFreeSkipListOf[K]) Descend(fn func(key K) bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	var zero K
+	stack := make([]*nodeOf[K], 0, 4*maxLevel)
+	s.descend(maxLevel-1, s.head, zero, false, true, &stack, fn)
+}
+
+// DescendFrom 方法用于按降序遍历跳表中小于或等于 key 的键，fn 返回 false 时停止遍历。遍历是弱一致的
+// The DescendFrom method is used to walk the keys less than or equal to key in the skiplist in descending order, the walk stops when fn returns false. The walk is weakly consistent
+func (s *LockFreeSkipListOf[K]) DescendFrom(key K, fn func(key K) bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	stack := make([]*nodeOf[K], 0, 4*maxLevel)
+	s.descend(maxLevel-1, s.head, key, true, false, &stack, fn)
+}
+
+// Length 方法用于获取跳表中键的数量
+// The Length method is used to get the number of keys in the skiplist
+func (s *LockFreeSkipListOf[K]) Length() int64 {
+	return atomic.LoadInt64(&s.length)
+}
+
+// IsEmpty 方法用于判断跳表是否为空
+// The IsEmpty method is used to determine whether the skiplist is empty
+func (s *LockFreeSkipListOf[K]) IsEmpty() bool {
+	return s.Length() == 0
+}
+
+// Reset 方法用于重置跳表，它会删除所有的键，可以与其他方法并发调用，并发插入的键可能不会被删除
+// The Reset method is used to reset the skiplist, it deletes every key, it can be called concurrently with the other methods, keys inserted concurrently may not be deleted
+func (s *LockFreeSkipListOf[K]) Reset() {
+	for {
+		var zero K
+		key, ok := zero, false
+		s.Ascend(func(k K) bool {
+			key, ok = k, true
+			return false
+		})
+		if !ok {
+			return
+		}
+		s.Delete(key)
+	}
+}
+
+// Stats 方法用于返回跳表的统计信息快照，没有启用 WithStats 选项时返回零值。插入的键计入 Pushes，删除的键计入 Pops
+// The Stats method is used to return a snapshot of the statistics of the skiplist, the zero value is returned when the WithStats option is not enabled. Inserted keys count as Pushes, deleted keys count as Pops
+func (s *LockFreeSkipListOf[K]) Stats() Stats {
+	return s.stats.Snapshot()
+}
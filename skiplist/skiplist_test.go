@@ -0,0 +1,452 @@
+package skiplist
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// compareInt compares two int keys stored as interface{}
+func compareInt(a, b interface{}) int {
+	return a.(int) - b.(int)
+}
+
+// compareIntOf compares two int keys
+func compareIntOf(a, b int) int {
+	return a - b
+}
+
+func TestLockFreeSkipList_Standard(t *testing.T) {
+	// Number of keys to test
+	count := 100000
+
+	// Create a new skiplist
+	s := New(compareInt)
+
+	// Test inserting keys in random order
+	for _, k := range rand.Perm(count) {
+		assert.True(t, s.Insert(k), "Failed to insert key %d", k)
+	}
+	assert.Equal(t, int64(count), s.Length(), "Incorrect skiplist length. Expected %d, got %d", count, s.Length())
+
+	// Test that duplicate keys are rejected
+	assert.False(t, s.Insert(0), "Inserting an existing key should fail")
+	assert.Equal(t, int64(count), s.Length(), "Incorrect skiplist length. Expected %d, got %d", count, s.Length())
+
+	// Verify the keys in the skiplist
+	for i := 0; i < count; i++ {
+		assert.True(t, s.Contains(i), "Key %d should exist", i)
+	}
+	assert.False(t, s.Contains(count), "Key %d should not exist", count)
+
+	// Test deleting every other key
+	for i := 0; i < count; i += 2 {
+		assert.True(t, s.Delete(i), "Failed to delete key %d", i)
+	}
+	assert.False(t, s.Delete(0), "Deleting a missing key should fail")
+	for i := 0; i < count; i++ {
+		assert.Equal(t, i%2 == 1, s.Contains(i), "Incorrect existence of key %d", i)
+	}
+	assert.Equal(t, int64(count/2), s.Length(), "Incorrect skiplist length. Expected %d, got %d", count/2, s.Length())
+}
+
+func TestLockFreeSkipList_Seek(t *testing.T) {
+	s := New(compareInt)
+
+	for i := 0; i < 100; i += 10 {
+		s.Insert(i)
+	}
+
+	// Test seeking existing keys, keys between two keys and keys beyond the last key
+	assert.Equal(t, 0, s.Seek(-5), "Incorrect seek result")
+	assert.Equal(t, 10, s.Seek(10), "Incorrect seek result")
+	assert.Equal(t, 20, s.Seek(11), "Incorrect seek result")
+	assert.Equal(t, 90, s.Seek(90), "Incorrect seek result")
+	assert.Nil(t, s.Seek(91), "Seeking beyond the last key should return nil")
+
+	// Test that deleted keys are skipped
+	s.Delete(20)
+	assert.Equal(t, 30, s.Seek(11), "Incorrect seek result")
+}
+
+func TestLockFreeSkipList_Ascend(t *testing.T) {
+	s := New(compareInt)
+
+	for _, k := range rand.Perm(100) {
+		s.Insert(k)
+	}
+
+	// Test walking every key in ascending order
+	var keys []int
+	s.Ascend(func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return true
+	})
+	assert.Equal(t, 100, len(keys), "Incorrect number of keys. Expected 100, got %d", len(keys))
+	for i, k := range keys {
+		assert.Equal(t, i, k, "Incorrect key. Expected %d, got %d", i, k)
+	}
+
+	// Test walking from a key and stopping early
+	keys = keys[:0]
+	s.AscendFrom(42, func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{42, 43, 44}, keys, "Incorrect keys from 42")
+}
+
+func TestLockFreeSkipList_Descend(t *testing.T) {
+	s := New(compareInt)
+
+	for _, k := range rand.Perm(100) {
+		s.Insert(k * 2)
+	}
+
+	// Test walking every key in descending order
+	var keys []int
+	s.Descend(func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return true
+	})
+	assert.Equal(t, 100, len(keys), "Incorrect number of keys. Expected 100, got %d", len(keys))
+	for i, k := range keys {
+		assert.Equal(t, (99-i)*2, k, "Incorrect key. Expected %d, got %d", (99-i)*2, k)
+	}
+
+	// Test walking from an existing key, from a missing key and stopping early
+	keys = keys[:0]
+	s.DescendFrom(42, func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return len(keys) < 3
+	})
+	assert.Equal(t, []int{42, 40, 38}, keys, "Incorrect keys from 42")
+
+	keys = keys[:0]
+	s.DescendFrom(5, func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return true
+	})
+	assert.Equal(t, []int{4, 2, 0}, keys, "Incorrect keys from 5")
+
+	keys = keys[:0]
+	s.DescendFrom(-1, func(key interface{}) bool {
+		keys = append(keys, key.(int))
+		return true
+	})
+	assert.Empty(t, keys, "No key should be less than -1")
+}
+
+func TestLockFreeSkipList_EmptyAndNil(t *testing.T) {
+	s := New(compareInt)
+
+	// Test operations on an empty skiplist
+	assert.False(t, s.Contains(1), "Empty skiplist should not contain any key")
+	assert.False(t, s.Delete(1), "Deleting from an empty skiplist should fail")
+	assert.Nil(t, s.Seek(1), "Seeking in an empty skiplist should return nil")
+	s.Ascend(func(key interface{}) bool {
+		assert.Fail(t, "Empty skiplist should not have any key")
+		return true
+	})
+	s.Descend(func(key interface{}) bool {
+		assert.Fail(t, "Empty skiplist should not have any key")
+		return true
+	})
+
+	// Test that nil keys are ignored
+	assert.False(t, s.Insert(nil), "Inserting a nil key should fail")
+	assert.True(t, s.IsEmpty(), "Skiplist should be empty")
+}
+
+func TestLockFreeSkipList_Parallel(t *testing.T) {
+	workers, perWorker := 8, 10000
+	s := New(compareInt)
+
+	wg := sync.WaitGroup{}
+
+	// Each worker inserts its own range of keys and deletes half of them
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				key := i*workers + w
+				assert.True(t, s.Insert(key), "Failed to insert key %d", key)
+				if i%2 == 0 {
+					assert.True(t, s.Delete(key), "Failed to delete key %d", key)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Verify the keys in the skiplist
+	total := workers * perWorker
+	for key := 0; key < total; key++ {
+		assert.Equal(t, (key/workers)%2 == 1, s.Contains(key), "Incorrect existence of key %d", key)
+	}
+	assert.Equal(t, int64(total/2), s.Length(), "Incorrect skiplist length. Expected %d, got %d", total/2, s.Length())
+
+	// Verify that the keys are still sorted
+	prev := -1
+	s.Ascend(func(key interface{}) bool {
+		assert.Greater(t, key.(int), prev, "Keys should be in ascending order")
+		prev = key.(int)
+		return true
+	})
+}
+
+func TestLockFreeSkipList_WithPool_ParallelSameKeys(t *testing.T) {
+	keys := 64
+	s := NewWithPool(compareInt)
+
+	// balance counts successful inserts minus successful deletes of each key, it must end as 0 or 1
+	balance := make([]int32, keys)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 20000; i++ {
+				key := r.Intn(keys)
+				if r.Intn(2) == 0 {
+					if s.Insert(key) {
+						atomic.AddInt32(&balance[key], 1)
+					}
+				} else {
+					if s.Delete(key) {
+						atomic.AddInt32(&balance[key], -1)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Verify that the content matches the balance of every key
+	n := int64(0)
+	for key := 0; key < keys; key++ {
+		assert.True(t, balance[key] == 0 || balance[key] == 1, "Key %d inserted or deleted twice, balance %d", key, balance[key])
+		assert.Equal(t, balance[key] == 1, s.Contains(key), "Incorrect existence of key %d", key)
+		n += int64(balance[key])
+	}
+	assert.Equal(t, n, s.Length(), "Incorrect skiplist length. Expected %d, got %d", n, s.Length())
+}
+
+func TestLockFreeSkipList_WithPool_RangeParallel(t *testing.T) {
+	s := NewWithPool(compareInt)
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+
+	// Writers keep inserting and deleting keys
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 20000; i++ {
+				key := (i*4 + w) % 1000
+				if !s.Insert(key) {
+					s.Delete(key)
+				}
+			}
+		}(w)
+	}
+
+	// Readers check that every walk is sorted while the writers run
+	readers := sync.WaitGroup{}
+	for r := 0; r < 2; r++ {
+		readers.Add(1)
+		go func(r int) {
+			defer readers.Done()
+			for atomic.LoadInt32(&done) == 0 {
+				prev := -1
+				s.Ascend(func(key interface{}) bool {
+					assert.Greater(t, key.(int), prev, "Keys should be in ascending order")
+					prev = key.(int)
+					return true
+				})
+				prev = 1000
+				s.DescendFrom(999, func(key interface{}) bool {
+					assert.Less(t, key.(int), prev, "Keys should be in descending order")
+					prev = key.(int)
+					return true
+				})
+			}
+		}(r)
+	}
+	wg.Wait()
+	atomic.StoreInt32(&done, 1)
+	readers.Wait()
+}
+
+func TestLockFreeSkipListOf_Standard(t *testing.T) {
+	s := NewOf(compareIntOf)
+
+	// Test that zero keys are stored and not mistaken for missing keys
+	assert.True(t, s.Insert(0), "Failed to insert key 0")
+	assert.True(t, s.Contains(0), "Key 0 should exist")
+
+	k, ok := s.Seek(-1)
+	assert.True(t, ok, "Seek should find key 0")
+	assert.Equal(t, 0, k, "Incorrect seek result. Expected 0, got %d", k)
+
+	_, ok = s.Seek(1)
+	assert.False(t, ok, "Seek beyond the last key should fail")
+}
+
+func TestLockFreeSkipList_ResetParallel(t *testing.T) {
+	s := NewWithPool(compareInt)
+
+	wg := sync.WaitGroup{}
+
+	// Insert, delete and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5000; j++ {
+				switch {
+				case i == 0 && j%100 == 0:
+					s.Reset()
+				case i%2 == 0:
+					s.Delete(j % 500)
+				default:
+					s.Insert(j % 500)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	s.Reset()
+	assert.Equal(t, int64(0), s.Length(), "Incorrect skiplist length. Expected 0, got %d", s.Length())
+	assert.Nil(t, s.Seek(0), "Seek on a reset skiplist should return nil")
+}
+
+func TestLockFreeSkipList_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	s := New(compareInt)
+	s.Insert(1)
+	assert.Equal(t, Stats{}, s.Stats(), "Statistics should be disabled by default")
+
+	// Only keys actually inserted or deleted are counted
+	s = NewWithPool(compareInt, WithStats())
+	for i := 0; i < 100; i++ {
+		s.Insert(i)
+	}
+	s.Insert(0)
+	for i := 0; i < 50; i++ {
+		s.Delete(i)
+	}
+	s.Delete(0)
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(100), stats.Pushes, "Incorrect number of inserted keys")
+	assert.Equal(t, uint64(50), stats.Pops, "Incorrect number of deleted keys")
+	assert.Equal(t, uint64(0), stats.CASFailures, "A single goroutine should never fail a CAS")
+	assert.Equal(t, uint64(100), stats.PoolGets, "A duplicate key should not take a node from the pool")
+	assert.LessOrEqual(t, stats.PoolMisses, stats.PoolGets, "Pool misses should not exceed pool gets")
+}
+
+func TestLockFreeSkipList_WithPoolAndBackoff(t *testing.T) {
+	var calls atomic.Int64
+	s := New(compareInt, WithPool(), WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Insert and delete disjoint keys from many goroutines
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5000; j++ {
+				s.Insert(j*8 + i)
+				runtime.Gosched()
+				s.Delete(j*8 + i)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// New with WithPool takes the nodes from the node pool like NewWithPool
+	stats := s.Stats()
+	assert.Equal(t, int64(0), s.Length(), "Skip list should be empty")
+	assert.Equal(t, uint64(40000), stats.PoolGets, "The nodes should come from the node pool")
+	assert.LessOrEqual(t, uint64(calls.Load()), stats.CASFailures, "Only CAS failures should back off")
+}
+
+func TestLockFreeSkipListOf_DescendLinear(t *testing.T) {
+	// Count the comparisons so the cost of a walk can be checked
+	compares := 0
+	s := NewOf(func(a, b int) int {
+		compares++
+		return a - b
+	})
+
+	const n = 10000
+	for _, k := range rand.Perm(n) {
+		s.Insert(k)
+	}
+	for k := 0; k < n; k += 3 {
+		s.Delete(k)
+	}
+
+	var want []int
+	for k := n - 1; k >= 0; k-- {
+		if k%3 != 0 {
+			want = append(want, k)
+		}
+	}
+
+	// A full walk visits every link once, so the number of comparisons grows linearly with the number of keys
+	var keys []int
+	compares = 0
+	s.Descend(func(key int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, want, keys, "Incorrect keys in descending order")
+	assert.Less(t, compares, 4*n, "A full descending walk should be linear, got %d comparisons for %d keys", compares, n)
+
+	// Walking from a key in the middle only returns the keys below it
+	for _, from := range []int{0, 1, 2, 4999, 5000, 5001, n - 1, n + 100} {
+		keys = keys[:0]
+		s.DescendFrom(from, func(key int) bool {
+			keys = append(keys, key)
+			return true
+		})
+		expected := []int{}
+		for _, k := range want {
+			if k <= from {
+				expected = append(expected, k)
+			}
+		}
+		assert.Equal(t, expected, append([]int{}, keys...), "Incorrect keys from %d", from)
+	}
+}
+
+func TestLockFreeSkipListOf_WithPool_ZeroAllocs(t *testing.T) {
+	s := NewWithPoolOf(compareIntOf)
+	for i := 0; i < 1000; i++ {
+		s.Insert(i * 2)
+	}
+
+	// Deletions are marked inside the links of the node, so once the pool is warm Insert and Delete do not allocate
+	allocs := testing.AllocsPerRun(10000, func() {
+		s.Insert(501)
+		s.Delete(501)
+	})
+	assert.Equal(t, float64(0), allocs, "Insert and Delete should not allocate, got %v allocs per run", allocs)
+}
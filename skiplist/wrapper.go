@@ -0,0 +1,120 @@
+package skiplist
+
+// LockFreeSkipList 是一个无锁跳表结构体，键的类型为 interface{}
+// LockFreeSkipList is a lock-free skiplist struct, the type of the keys is interface{}
+type LockFreeSkipList LockFreeSkipListOf[interface{}]
+
+// New 函数用于创建一个新的 LockFreeSkipList 跳表，compare 是键的比较函数，可以通过选项修改跳表的行为
+// The New function is used to create a new LockFreeSkipList skiplist, compare is the comparator of the keys, the behavior of the skiplist can be modified with options
+func New(compare func(a, b interface{}) int, opts ...Option) *LockFreeSkipList {
+	// 调用 newLFSL 函数创建一个新的 LockFreeSkipList 跳表，是否使用节点池由选项决定
+	// Call the newLFSL function to create a new LockFreeSkipList skiplist, whether a node pool is used is decided by the options
+	return (*LockFreeSkipList)(newLFSL[interface{}](compare, opts))
+}
+
+// NewWithPool 函数用于创建一个新的 LockFreeSkipList 跳表，该跳表使用一个节点池，compare 是键的比较函数，可以通过选项修改跳表的行为。
+// 它是 New 加上 WithPool 选项的简写
+// The NewWithPool function is used to create a new LockFreeSkipList skiplist, this skiplist uses a node pool, compare is the comparator of the keys, the behavior of the skiplist can be modified with options.
+// It is a shortcut for New with the WithPool option
+func NewWithPool(compare func(a, b interface{}) int, opts ...Option) *LockFreeSkipList {
+	return (*LockFreeSkipList)(NewWithPoolOf[interface{}](compare, opts...))
+}
+
+// of 方法用于将 LockFreeSkipList 转换为底层的 LockFreeSkipListOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeSkipList to the underlying LockFreeSkipListOf[interface{}] without any extra cost
+func (s *LockFreeSkipList) of() *LockFreeSkipListOf[interface{}] {
+	return (*LockFreeSkipListOf[interface{}])(s)
+}
+
+// Insert 方法用于向 LockFreeSkipList 跳表中插入一个键，如果键为 nil 或者已经存在，返回 false
+// The Insert method is used to insert a key into the LockFreeSkipList skiplist, returns false if the key is nil or already exists
+func (s *LockFreeSkipList) Insert(key interface{}) bool {
+	// 检查键是否为空, 如果为空则直接返回
+	// Check if the key is nil, if it is, return directly
+	if key == nil {
+		return false
+	}
+	return s.of().Insert(key)
+}
+
+// Delete 方法用于从 LockFreeSkipList 跳表中删除一个键，如果键不存在，返回 false
+// The Delete method is used to delete a key from the LockFreeSkipList skiplist, returns false if the key does not exist
+func (s *LockFreeSkipList) Delete(key interface{}) bool {
+	if key == nil {
+		return false
+	}
+	return s.of().Delete(key)
+}
+
+// Contains 方法用于判断 LockFreeSkipList 跳表中是否存在一个键
+// The Contains method is used to determine whether a key exists in the LockFreeSkipList skiplist
+func (s *LockFreeSkipList) Contains(key interface{}) bool {
+	if key == nil {
+		return false
+	}
+	return s.of().Contains(key)
+}
+
+// Seek 方法用于返回 LockFreeSkipList 跳表中大于或等于 key 的最小的键，如果不存在，返回 nil
+// The Seek method is used to return the smallest key in the LockFreeSkipList skiplist that is greater than or equal to key, returns nil if there is none
+func (s *LockFreeSkipList) Seek(key interface{}) interface{} {
+	if key == nil {
+		return nil
+	}
+	value, _ := s.of().Seek(key)
+	return value
+}
+
+// Ascend 方法用于按升序遍历 LockFreeSkipList 跳表中的所有键，fn 返回 false 时停止遍历
+// The Ascend method is used to walk all the keys in the LockFreeSkipList skiplist in ascending order, the walk stops when fn returns false
+func (s *LockFreeSkipList) Ascend(fn func(key interface{}) bool) {
+	s.of().Ascend(fn)
+}
+
+// AscendFrom 方法用于按升序遍历 LockFreeSkipList 跳表中大于或等于 key 的键，fn 返回 false 时停止遍历
+// The AscendFrom method is used to walk the keys greater than or equal to key in the LockFreeSkipList skiplist in ascending order, the walk stops when fn returns false
+func (s *LockFreeSkipList) AscendFrom(key interface{}, fn func(key interface{}) bool) {
+	if key == nil {
+		return
+	}
+	s.of().AscendFrom(key, fn)
+}
+
+// Descend 方法用于按降序遍历 LockFreeSkipList 跳表中的所有键，fn 返回 false 时停止遍历
+// The Descend method is used to walk all the keys in the LockFreeSkipList skiplist in descending order, the walk stops when fn returns false
+func (s *LockFreeSkipList) Descend(fn func(key interface{}) bool) {
+	s.of().Descend(fn)
+}
+
+// DescendFrom 方法用于按降序遍历 LockFreeSkipList 跳表中小于或等于 key 的键，fn 返回 false 时停止遍历
+// The DescendFrom method is used to walk the keys less than or equal to key in the LockFreeSkipList skiplist in descending order, the walk stops when fn returns false
+func (s *LockFreeSkipList) DescendFrom(key interface{}, fn func(key interface{}) bool) {
+	if key == nil {
+		return
+	}
+	s.of().DescendFrom(key, fn)
+}
+
+// Length 方法用于获取 LockFreeSkipList 跳表中键的数量
+// The Length method is used to get the number of keys in the LockFreeSkipList skiplist
+func (s *LockFreeSkipList) Length() int64 {
+	return s.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeSkipList 跳表是否为空
+// The IsEmpty method is used to determine whether the LockFreeSkipList skiplist is empty
+func (s *LockFreeSkipList) IsEmpty() bool {
+	return s.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeSkipList 跳表，可以与其他方法并发调用
+// The Reset method is used to reset the LockFreeSkipList skiplist, it can be called concurrently with the other methods
+func (s *LockFreeSkipList) Reset() {
+	s.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeSkipList 跳表的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeSkipList skiplist, the zero value is returned when the WithStats option is not enabled
+func (s *LockFreeSkipList) Stats() Stats {
+	return s.of().Stats()
+}
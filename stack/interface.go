@@ -1,5 +1,41 @@
 package stack
 
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是栈统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a stack, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// NodeOf 是值的类型为 T 的栈节点，自定义的节点分配器分配和回收它。它的字段由栈管理，分配器只需要创建零值节点，例如 new(NodeOf[T])
+// NodeOf is a node of the stack whose value is of type T, it is allocated and recycled by a custom node allocator. Its fields are managed by the stack, the allocator only needs to create zero nodes, for example new(NodeOf[T])
+type NodeOf[T any] shd.NodeOf[T]
+
+// Node 是值的类型为 interface{} 的栈节点
+// Node is a node of the stack whose value is of type interface{}
+type Node = NodeOf[interface{}]
+
+// NodeAllocatorOf 是一个接口，定义了自定义的节点分配器，通过 WithNodeAllocatorOf 选项设置。Get 返回的节点所有字段必须为零值，
+// Put 只会收到 Get 返回过的节点，这些节点已经被重置，并且已经没有 goroutine 引用它们，可以直接复用
+// NodeAllocatorOf is an interface that defines a custom node allocator, it is set with the WithNodeAllocatorOf option. All the fields of a node returned by Get must be zero,
+// Put only receives nodes returned by Get, they have already been reset and are no longer referenced by any goroutine, so they can be reused directly
+type NodeAllocatorOf[T any] interface {
+	// Get 方法用于分配一个节点
+	// The Get method is used to allocate a node
+	Get() *NodeOf[T]
+
+	// Put 方法用于归还一个节点
+	// The Put method is used to give a node back
+	Put(n *NodeOf[T])
+}
+
+// NodeAllocator 是节点的值的类型为 interface{} 的节点分配器，通过 WithNodeAllocator 选项设置
+// NodeAllocator is a node allocator whose nodes hold values of type interface{}, it is set with the WithNodeAllocator option
+type NodeAllocator = NodeAllocatorOf[interface{}]
+
 // Stack 是一个接口，定义了堆栈的基本操作
 // Stack is an interface that defines basic operations of a stack
 type Stack = interface {
@@ -11,6 +47,10 @@ type Stack = interface {
 	// The Pop method is used to remove and return an element from the stack
 	Pop() interface{}
 
+	// TryPop 方法用于从堆栈中移除并返回一个元素，第二个返回值表示是否移除了元素
+	// The TryPop method is used to remove and return an element from the stack, the second return value reports whether an element was removed
+	TryPop() (interface{}, bool)
+
 	// Reset 方法用于重置/清空堆栈
 	// The Reset method is used to reset/clear the stack
 	Reset()
@@ -0,0 +1,133 @@
+package stack
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// config 是栈的配置
+// config is the configuration of the stack
+type config struct {
+	// acceptNil 表示 Push 是否接受 nil 值
+	// acceptNil indicates whether Push accepts nil values
+	acceptNil bool
+
+	// stats 表示是否启用统计
+	// stats indicates whether statistics are enabled
+	stats bool
+
+	// pool 表示是否使用节点池复用节点
+	// pool indicates whether a node pool is used to reuse nodes
+	pool bool
+
+	// allocator 是自定义的节点分配器，保存一个 NodeAllocatorOf[T]，为 nil 时使用默认的分配方式
+	// allocator is the custom node allocator, it holds a NodeAllocatorOf[T], the default allocation is used when it is nil
+	allocator interface{}
+
+	// backoff 是 CAS 失败之后的退避策略，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// Option 是一个函数类型，用于修改栈的配置
+// Option is a function type used to modify the configuration of the stack
+type Option func(*config)
+
+// newConfig 函数用于创建一个新的配置，并应用所有的选项
+// The newConfig function is used to create a new configuration and apply all the options
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithAcceptNil 函数返回一个选项，启用后 Push 不再丢弃 nil 值，而是像其他值一样保存它。
+// 此时 Pop 返回的 nil 无法区分空栈和 nil 值，需要使用 TryPop 方法判断是否弹出了元素。
+// The WithAcceptNil function returns an option, when enabled Push no longer drops nil values and stores them like any other value.
+// A nil returned by Pop then cannot tell an empty stack from a nil value, use the TryPop method to know whether an element was popped.
+func WithAcceptNil() Option {
+	return func(c *config) {
+		c.acceptNil = true
+	}
+}
+
+// WithStats 函数返回一个选项，启用后栈会统计推入、弹出、CAS 失败、空弹出以及节点池的使用情况，可以通过 Stats 方法读取。
+// 计数器按 goroutine 分片，开销很小，没有启用时只需要一次 nil 判断
+// The WithStats function returns an option, when enabled the stack counts pushes, pops, CAS failures, empty pops and the use of the node pool, which can be read with the Stats method.
+// The counters are sharded by goroutine and cheap to update, when not enabled they only cost a nil check
+func WithStats() Option {
+	return func(c *config) {
+		c.stats = true
+	}
+}
+
+// newNodePool 函数用于根据配置创建节点池，设置了自定义的节点分配器时，节点池通过它分配和回收节点。
+// 节点分配器的元素类型与栈不同时会 panic。既没有自定义的节点分配器也没有启用节点池时返回 nil
+// The newNodePool function is used to create the node pool according to the configuration, when a custom node allocator is set the node pool allocates and recycles nodes through it.
+// It panics when the element type of the node allocator differs from the one of the stack. nil is returned when there is neither a custom node allocator nor an enabled node pool
+func newNodePool[T any](c *config) *shd.NodePoolOf[T] {
+	if c.allocator != nil {
+		a, ok := c.allocator.(NodeAllocatorOf[T])
+		if !ok {
+			panic("stack: the node allocator does not allocate nodes of the element type of the stack")
+		}
+		return shd.NewNodePoolWithAllocatorOf[T](nodeAllocatorOf[T]{a})
+	}
+	if c.pool {
+		return shd.NewNodePoolOf[T]()
+	}
+	return nil
+}
+
+// nodeAllocatorOf 结构体把 NodeAllocatorOf 适配为节点池使用的分配器，两种节点的结构相同，只需要转换指针的类型
+// The nodeAllocatorOf struct adapts a NodeAllocatorOf to the allocator used by the node pool, both nodes have the same structure so only the pointer type is converted
+type nodeAllocatorOf[T any] struct {
+	a NodeAllocatorOf[T]
+}
+
+// Get 方法用于从自定义的节点分配器中分配一个节点
+// The Get method is used to allocate a node from the custom node allocator
+func (n nodeAllocatorOf[T]) Get() *shd.NodeOf[T] {
+	return (*shd.NodeOf[T])(n.a.Get())
+}
+
+// Put 方法用于把一个节点还给自定义的节点分配器
+// The Put method is used to give a node back to the custom node allocator
+func (n nodeAllocatorOf[T]) Put(node *shd.NodeOf[T]) {
+	n.a.Put((*NodeOf[T])(node))
+}
+
+// WithPool 函数返回一个选项，启用后栈通过节点池复用弹出的节点，与 NewWithPool 相同。
+// 节点通过基于 epoch 的回收器复用，只有在没有 goroutine 引用之后才会被复用，避免 ABA 问题
+// The WithPool function returns an option, when enabled the stack reuses popped nodes through a node pool, the same as NewWithPool.
+// Nodes are recycled through an epoch-based reclaimer and only reused once no goroutine references them, which avoids the ABA problem
+func WithPool() Option {
+	return func(c *config) {
+		c.pool = true
+	}
+}
+
+// WithNodeAllocator 函数返回一个选项，栈通过自定义的分配器 a 分配和回收节点，包括哨兵节点，并且像使用节点池一样回收节点。
+// 它只能用于元素类型为 interface{} 的栈，其他元素类型使用 WithNodeAllocatorOf
+// The WithNodeAllocator function returns an option, the stack allocates and recycles nodes, including the sentinel nodes, through the custom allocator a, and recycle them as with a node pool.
+// It can only be used by a stack whose element type is interface{}, use WithNodeAllocatorOf for other element types
+func WithNodeAllocator(a NodeAllocator) Option {
+	return WithNodeAllocatorOf[interface{}](a)
+}
+
+// WithNodeAllocatorOf 函数返回一个选项，泛型栈通过自定义的分配器 a 分配和回收节点，包括哨兵节点，并且像使用节点池一样回收节点。
+// T 必须与栈的元素类型相同，否则创建栈时会 panic
+// The WithNodeAllocatorOf function returns an option, the generic stack allocates and recycles nodes, including the sentinel nodes, through the custom allocator a, and recycle them as with a node pool.
+// T must be the element type of the stack, otherwise creating the stack panics
+func WithNodeAllocatorOf[T any](a NodeAllocatorOf[T]) Option {
+	return func(c *config) {
+		c.allocator = a
+	}
+}
+
+// WithBackoff 函数返回一个选项，设置 CAS 失败之后的退避策略 b。竞争激烈时，退避可以减少无效的重试
+// The WithBackoff function returns an option that sets the backoff strategy b after a failed CAS. Under heavy contention backing off reduces wasted retries
+func WithBackoff(b Backoff) Option {
+	return func(c *config) {
+		c.backoff = b
+	}
+}
@@ -1,71 +1,134 @@
 package stack
 
 import (
+	"context"
 	"sync/atomic"
 	"unsafe"
 
 	shd "github.com/shengyanli1982/lockfree/internal/shared"
 )
 
-// LockFreeStack 是一个无锁栈的结构体
-// LockFreeStack is a structure of a lock-free stack
-type LockFreeStack struct {
+// LockFreeStackOf 是一个泛型无锁栈的结构体，T 为栈中元素的类型
+// LockFreeStackOf is a structure of a generic lock-free stack, T is the type of the elements in the stack
+type LockFreeStackOf[T any] struct {
 	// length 是栈的长度
 	// length is the length of the stack
 	length int64
 
+	// _ 用于把长度与栈顶指针分隔到不同的缓存行
+	// _ separates the length from the top pointer onto different cache lines
+	_ shd.CacheLinePad
+
 	// top 是栈顶元素的指针
 	// top is a pointer to the top element of the stack
 	top unsafe.Pointer
 
+	// _ 用于把争用的栈顶指针与之后只读的字段分隔到不同的缓存行
+	// _ separates the contended top pointer from the read-only fields after it onto different cache lines
+	_ shd.CacheLinePad
+
 	// pool 是一个节点池，用于存储和获取节点
 	// pool is a node pool used to store and retrieve nodes
-	pool *shd.NodePool
+	pool *shd.NodePoolOf[T]
+
+	// reclaimer 是节点回收器，仅在使用节点池时启用，保证节点在没有 goroutine 引用后才会被复用
+	// reclaimer is the node reclaimer, only enabled with a node pool, it ensures nodes are reused only after no goroutine references them
+	reclaimer *shd.Reclaimer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+
+	// notEmpty 用于挂起等待数据的 goroutine，在推入数据后唤醒它们
+	// notEmpty is used to park goroutines waiting for data, they are woken up after data is pushed
+	notEmpty shd.Waiter
+
+	// acceptNil 表示 Push 是否接受 nil 值，只在元素类型为 interface{} 的 LockFreeStack 中使用
+	// acceptNil indicates whether Push accepts nil values, it is only used by LockFreeStack whose element type is interface{}
+	acceptNil bool
 }
 
-// New 函数用于创建一个新的无锁栈
-// The New function is used to create a new lock-free stack
-func New() *LockFreeStack {
-	// 调用 newLFS 函数创建一个新的 LockFreeStack 栈，参数为 nil
-	// Call the newLFS function to create a new LockFreeStack stack, the parameter is nil
-	return newLFS(nil)
+// NewOf 函数用于创建一个新的泛型无锁栈
+// The NewOf function is used to create a new generic lock-free stack
+func NewOf[T any](opts ...Option) *LockFreeStackOf[T] {
+	// 调用 newLFS 函数创建一个新的 LockFreeStackOf 栈，是否使用节点池由选项决定
+	// Call the newLFS function to create a new LockFreeStackOf stack, whether a node pool is used is decided by the options
+	return newLFS[T](opts)
 }
 
-// NewWithPool 函数用于创建一个新的 LockFreeStack 栈，该栈使用一个节点池
-// The NewWithPool function is used to create a new LockFreeStack stack, this stack uses a node pool
-func NewWithPool() *LockFreeStack {
-	// 调用 newLFS 函数创建一个新的 LockFreeStack 栈，参数为一个新的节点池
-	// Call the newLFS function to create a new LockFreeStack stack, the parameter is a new node pool
-	return newLFS(shd.NewNodePool())
+// NewWithPoolOf 函数用于创建一个新的泛型 LockFreeStackOf 栈，该栈使用一个节点池。它是 NewOf 加上 WithPool 选项的简写
+// The NewWithPoolOf function is used to create a new generic LockFreeStackOf stack, this stack uses a node pool. It is a shortcut for NewOf with the WithPool option
+func NewWithPoolOf[T any](opts ...Option) *LockFreeStackOf[T] {
+	return newLFS[T](append([]Option{WithPool()}, opts...))
 }
 
-// newLFS 函数用于创建一个新的 LockFreeStack 栈，参数为一个节点池
-// The newLFS function is used to create a new LockFreeStack stack, the parameter is a node pool
-func newLFS(pool *shd.NodePool) *LockFreeStack {
-	// 创建一个新的 Node 结构体实例，值为 nil
-	// Create a new Node struct instance, the value is nil
-	firstNode := shd.NewNode(nil)
+// newLFS 函数用于创建一个新的 LockFreeStackOf 栈，参数为所有的选项
+// The newLFS function is used to create a new LockFreeStackOf stack, the parameter is all the options
+func newLFS[T any](opts []Option) *LockFreeStackOf[T] {
+	// 应用所有的选项，设置了自定义的节点分配器或者启用了节点池时，创建节点池
+	// Apply all the options, create the node pool when a custom node allocator is set or the node pool is enabled
+	conf := newConfig(opts)
+	pool := newNodePool[T](conf)
+
+	// 创建哨兵节点，值为 T 的零值。如果使用节点池，那么从节点池中获取，之后它会像其他节点一样被退休并放回节点池
+	// Create the sentinel node, the value is the zero value of T. If a node pool is used, it is taken from the node pool, later it is retired and put back into the node pool like any other node
+	var firstNode *shd.NodeOf[T]
+	if pool != nil {
+		firstNode = pool.Get()
+	} else {
+		var zero T
+		firstNode = shd.NewNodeOf(zero)
+	}
 
-	// 返回一个新的 LockFreeStack 栈，该栈的顶部节点是刚刚创建的节点，节点池为传入的参数
-	// Return a new LockFreeStack stack, the top node of this stack is the node just created, and the node pool is the passed in parameter
-	return &LockFreeStack{
+	// 创建一个新的 LockFreeStackOf 栈，该栈的顶部节点是刚刚创建的节点，节点池为传入的参数
+	// Create a new LockFreeStackOf stack, the top node of this stack is the node just created, and the node pool is the passed in parameter
+	s := &LockFreeStackOf[T]{
 		pool: pool,
 		top:  unsafe.Pointer(firstNode),
 	}
+
+	// 如果使用节点池，那么创建节点回收器
+	// If a node pool is used, then create the node reclaimer
+	if pool != nil {
+		s.reclaimer = shd.NewNodeReclaimerOf(pool)
+	}
+
+	// 设置是否接受 nil 值以及退避策略
+	// Set whether nil values are accepted and the backoff strategy
+	s.acceptNil = conf.acceptNil
+	s.backoff = conf.backoff
+
+	// 如果启用了统计，那么创建统计计数器，节点池也使用同一组计数器
+	// If statistics are enabled, then create the statistics counters, the node pool uses the same counters
+	if conf.stats {
+		s.stats = shd.NewCounters()
+		if pool != nil {
+			pool.SetStats(s.stats)
+		}
+	}
+
+	// 返回新创建的栈
+	// Return the newly created stack
+	return s
 }
 
 // Push 方法用于向无锁栈中推入一个元素
 // The Push method is used to push an element into the lock-free stack
-func (s *LockFreeStack) Push(value interface{}) {
-	// 检查值是否为空, 如果为空则直接返回
-	// Check if the value is nil, if it is, return directly
-	if value == nil {
-		return
+func (s *LockFreeStackOf[T]) Push(value T) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
 	}
 
 	// 创建一个新的 Node 结构体实例
 	// Create a new Node struct instance
-	var node *shd.Node
+	var node *shd.NodeOf[T]
 	if s.pool != nil {
 		// 如果节点池不为空，那么从节点池中获取一个节点
 		// If the node pool is not nil, then get a node from the node pool
@@ -74,15 +137,17 @@ func (s *LockFreeStack) Push(value interface{}) {
 	} else {
 		// 如果节点池为空，那么创建一个新的节点
 		// If the node pool is nil, then create a new node
-		node = shd.NewNode(value)
+		node = shd.NewNodeOf(value)
 	}
 
+	attempt := 0
+
 	// 使用无限循环，直到成功推入元素
 	// Use an infinite loop until an element is successfully pushed
 	for {
 		// 获取栈顶元素
 		// Get the top element of the stack
-		top := shd.LoadNode(&s.top)
+		top := shd.LoadNodeOf[T](&s.top)
 
 		// 设置新节点的下一个元素为当前的栈顶元素
 		// Set the next element of the new node to the current top element
@@ -94,35 +159,56 @@ func (s *LockFreeStack) Push(value interface{}) {
 			// 如果成功修改，栈的长度加 1
 			// If the modification is successful, the length of the stack is increased by 1
 			atomic.AddInt64(&s.length, 1)
+			s.stats.Inc(shd.CounterPushes)
+
+			// 唤醒等待数据的 goroutine
+			// Wake up the goroutines waiting for data
+			s.notEmpty.Notify()
 
 			// 结束循环
 			// End the loop
 			return
 		}
+
+		// 其他 goroutine 抢先修改了栈顶，记录一次 CAS 失败，退避之后重试
+		// Another goroutine changed the top first, count a CAS failure, back off and try again
+		s.stats.Inc(shd.CounterCASFailures)
+		s.backoff.Wait(&attempt)
 	}
 }
 
-// Pop 方法用于从无锁栈中弹出一个元素
-// The Pop method is used to pop an element from the lock-free stack
-func (s *LockFreeStack) Pop() interface{} {
+// Pop 方法用于从无锁栈中弹出一个元素，如果栈为空，返回 T 的零值和 false
+// The Pop method is used to pop an element from the lock-free stack, returns the zero value of T and false if the stack is empty
+func (s *LockFreeStackOf[T]) Pop() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+
 	// 使用无限循环，直到成功弹出元素
 	// Use an infinite loop until an element is successfully popped
 	for {
 		// 获取栈顶元素
 		// Get the top element of the stack
-		top := shd.LoadNode(&s.top)
+		top := shd.LoadNodeOf[T](&s.top)
 
 		// 获取栈顶元素的下一个元素
 		// Get the next element of the top element
-		next := shd.LoadNode(&top.Next)
+		next := shd.LoadNodeOf[T](&top.Next)
 
 		// 检查栈顶元素是否被其他线程修改
 		// Check if the top element has been modified by other threads
-		if top == shd.LoadNode(&s.top) {
-			// 如果栈为空，返回 nil
-			// If the stack is empty, return nil
+		if top == shd.LoadNodeOf[T](&s.top) {
+			// 如果栈为空，返回 T 的零值和 false
+			// If the stack is empty, return the zero value of T and false
 			if next == nil {
-				return nil
+				s.stats.Inc(shd.CounterEmptyPops)
+				var zero T
+				return zero, false
 			}
 
 			// 获取要返回的结果
@@ -135,28 +221,287 @@ func (s *LockFreeStack) Pop() interface{} {
 				// 如果成功修改，栈的长度减 1
 				// If the modification is successful, the length of the stack is reduced by 1
 				atomic.AddInt64(&s.length, -1)
+				s.stats.Inc(shd.CounterPops)
 
-				// 如果节点池不为空，那么将栈顶元素放回节点池
-				// If the node pool is not nil, then put the top element back into the node pool
+				// 如果节点池不为空，那么退休栈顶元素，等到没有 goroutine 引用它之后再放回节点池。
+				// 否则栈顶元素交给 GC 回收，不能重置它，因为其他 goroutine 可能仍在读取它
+				// If the node pool is not nil, then retire the top element, it is put back into the node pool once no goroutine references it.
+				// Otherwise the top element is left to the GC, it must not be reset because other goroutines may still be reading it
 				if s.pool != nil {
-					s.pool.Put(top)
-				} else {
-					// 如果节点池为空，那么重置栈顶元素
-					// If the node pool is nil, then reset the top element
-					top.ResetAll()
+					s.reclaimer.Retire(unsafe.Pointer(top))
 				}
 
 				// 如果结果不是空值，返回结果
 				// If the result is not an empty value, return the result
-				return result
+				return result, true
+			}
+
+			// 其他 goroutine 抢先修改了栈顶，记录一次 CAS 失败，退避之后重试
+			// Another goroutine changed the top first, count a CAS failure, back off and try again
+			s.stats.Inc(shd.CounterCASFailures)
+			s.backoff.Wait(&attempt)
+		}
+	}
+}
+
+// newNode 方法用于创建一个保存 value 的新节点，如果使用节点池，那么从节点池中获取
+// The newNode method is used to create a new node holding value, the node is taken from the node pool if one is used
+func (s *LockFreeStackOf[T]) newNode(value T) *shd.NodeOf[T] {
+	if s.pool != nil {
+		node := s.pool.Get()
+		node.Value = value
+		return node
+	}
+	return shd.NewNodeOf(value)
+}
+
+// PushBatch 方法用于向无锁栈中按顺序推入一组元素，结果与逐个调用 Push 相同，最后一个元素位于栈顶。
+// 这些元素会先被串成一条节点链，然后通过一次 CAS 操作推入栈中，并且只更新一次栈的长度
+// The PushBatch method is used to push a group of elements into the lock-free stack in order, the result is the same as calling Push one by one, the last element is on the top.
+// The elements are first built into a chain of nodes, which is then pushed onto the stack with a single CAS operation, and the length of the stack is updated only once
+func (s *LockFreeStackOf[T]) PushBatch(values []T) {
+	// 如果没有元素，直接返回
+	// If there are no elements, return directly
+	if len(values) == 0 {
+		return
+	}
+
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	// 从第一个元素开始构建节点链，first 是链的顶部节点，保存最后一个元素，bottom 是链的底部节点，保存第一个元素
+	// Build the chain of nodes starting from the first element, first is the top node of the chain holding the last element, bottom is the bottom node of the chain holding the first element
+	bottom := s.newNode(values[0])
+	first := bottom
+	for _, value := range values[1:] {
+		node := s.newNode(value)
+		node.Next = unsafe.Pointer(first)
+		first = node
+	}
+
+	attempt := 0
+
+	// 使用无限循环，直到成功推入节点链
+	// Use an infinite loop until the chain of nodes is successfully pushed
+	for {
+		// 获取栈顶元素，并把它设置为节点链底部节点的下一个元素
+		// Get the top element of the stack and set it as the next element of the bottom node of the chain
+		top := shd.LoadNodeOf[T](&s.top)
+		bottom.Next = unsafe.Pointer(top)
+
+		// 使用 CAS 操作尝试把栈顶元素修改为节点链的顶部节点
+		// Use CAS operation to try to change the top element to the top node of the chain
+		if shd.CompareAndSwapNode(&s.top, top, first) {
+			// 一次性增加栈的长度
+			// Increase the length of the stack at once
+			atomic.AddInt64(&s.length, int64(len(values)))
+			s.stats.Add(shd.CounterPushes, len(values))
+
+			// 唤醒等待数据的 goroutine
+			// Wake up the goroutines waiting for data
+			s.notEmpty.Notify()
+			return
+		}
+
+		// 其他 goroutine 抢先修改了栈顶，记录一次 CAS 失败，退避之后重试
+		// Another goroutine changed the top first, count a CAS failure, back off and try again
+		s.stats.Inc(shd.CounterCASFailures)
+		s.backoff.Wait(&attempt)
+	}
+}
+
+// PopBatch 方法用于从无锁栈中弹出最多 len(dst) 个元素，并按弹出的顺序写入 dst，返回弹出的元素数量。
+// 这些元素通过一次 CAS 操作从栈中摘下，并且只更新一次栈的长度，栈为空时返回 0
+// The PopBatch method is used to pop up to len(dst) elements from the lock-free stack and write them into dst in pop order, returning the number of elements popped.
+// The elements are detached from the stack with a single CAS operation and the length of the stack is updated only once, 0 is returned when the stack is empty
+func (s *LockFreeStackOf[T]) PopBatch(dst []T) int {
+	// 如果 dst 没有空间，直接返回
+	// If dst has no room, return directly
+	if len(dst) == 0 {
+		return 0
+	}
+
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	attempt := 0
+
+	// 使用无限循环，直到成功弹出元素或者栈为空
+	// Use an infinite loop until elements are successfully popped or the stack is empty
+	for {
+		// 获取栈顶元素
+		// Get the top element of the stack
+		top := shd.LoadNodeOf[T](&s.top)
+
+		// 从栈顶开始向下读取元素，最多读取到栈底的哨兵节点为止，rest 是剩余部分的新栈顶
+		// Read the elements downwards starting from the top, at most down to the sentinel node at the bottom, rest is the new top of the remaining part
+		n := 0
+		rest := top
+		for n < len(dst) {
+			next := shd.LoadNodeOf[T](&rest.Next)
+			if next == nil {
+				break
+			}
+			dst[n] = rest.Value
+			rest = next
+			n++
+		}
+
+		// 检查栈顶元素是否被其他线程修改
+		// Check if the top element has been modified by other threads
+		if top != shd.LoadNodeOf[T](&s.top) {
+			continue
+		}
+
+		// 如果栈为空，返回 0
+		// If the stack is empty, return 0
+		if n == 0 {
+			s.stats.Inc(shd.CounterEmptyPops)
+			return 0
+		}
+
+		// 使用 CAS 操作尝试把栈顶元素修改为剩余部分的新栈顶
+		// Use CAS operation to try to change the top element to the new top of the remaining part
+		if shd.CompareAndSwapNode(&s.top, top, rest) {
+			// 一次性减少栈的长度
+			// Decrease the length of the stack at once
+			atomic.AddInt64(&s.length, -int64(n))
+			s.stats.Add(shd.CounterPops, n)
+
+			// 如果节点池不为空，那么退休被摘下的节点，否则交给 GC 回收，其他 goroutine 可能仍在读取它们
+			// If the node pool is not nil, then retire the detached nodes, otherwise leave them to the GC, other goroutines may still be reading them
+			if s.pool != nil {
+				for node := top; node != rest; {
+					following := shd.LoadNodeOf[T](&node.Next)
+					s.reclaimer.Retire(unsafe.Pointer(node))
+					node = following
+				}
+			}
+
+			// 返回弹出的元素数量
+			// Return the number of elements popped
+			return n
+		}
+
+		// 其他 goroutine 抢先修改了栈顶，记录一次 CAS 失败，退避之后重试
+		// Another goroutine changed the top first, count a CAS failure, back off and try again
+		s.stats.Inc(shd.CounterCASFailures)
+		s.backoff.Wait(&attempt)
+	}
+}
+
+// PopWait 方法用于从无锁栈中弹出一个元素，如果栈为空，会先自旋再挂起等待，直到有元素可用或者 ctx 结束
+// The PopWait method is used to pop an element from the lock-free stack, if the stack is empty it spins and then parks until an element is available or ctx is done
+func (s *LockFreeStackOf[T]) PopWait(ctx context.Context) (T, error) {
+	// 快速路径，栈不为空时直接返回
+	// Fast path, return directly when the stack is not empty
+	if value, ok := s.Pop(); ok {
+		return value, nil
+	}
+
+	// 慢速路径，等待直到弹出一个元素或者 ctx 结束
+	// Slow path, wait until an element is popped or ctx is done
+	var value T
+	err := s.notEmpty.Wait(ctx, func() bool {
+		var ok bool
+		value, ok = s.Pop()
+		return ok
+	})
+	return value, err
+}
+
+// Peek 方法用于返回栈顶元素但不移除它，如果栈为空，返回 T 的零值和 false。
+// Peek 是可线性化的：返回的元素在重新确认栈顶的那一刻位于栈顶，返回 false 时，读取到栈顶是哨兵节点的那一刻栈为空。
+// 在并发的 Push 或 Pop 之后，该元素可能已经不在栈顶
+// The Peek method is used to return the top element of the stack without removing it, returns the zero value of T and false if the stack is empty.
+// Peek is linearizable: the returned element was on the top of the stack at the moment the top was confirmed again, and when false is returned the stack was empty at the moment the top was seen to be the sentinel node.
+// After a concurrent Push or Pop the element may no longer be on the top
+func (s *LockFreeStackOf[T]) Peek() (T, bool) {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	for {
+		// 获取栈顶元素以及它的下一个元素
+		// Get the top element of the stack and its next element
+		top := shd.LoadNodeOf[T](&s.top)
+		next := shd.LoadNodeOf[T](&top.Next)
+
+		// 读取栈顶元素的值，然后确认栈顶没有变化
+		// Read the value of the top element, then confirm the top has not changed
+		value := top.Value
+		if top == shd.LoadNodeOf[T](&s.top) {
+			// 栈顶是哨兵节点，说明栈为空
+			// The top is the sentinel node, the stack is empty
+			if next == nil {
+				var zero T
+				return zero, false
 			}
+			return value, true
 		}
 	}
 }
 
+// Range 方法用于从栈顶开始按 LIFO 顺序遍历栈中的元素，但不移除它们，fn 返回 false 时停止遍历。
+// 遍历是弱一致的：它不会阻塞 Push 和 Pop，不会看到遍历开始之后推入的元素，但可能看到遍历期间已经被弹出的元素，每个元素最多只会出现一次。
+// 使用节点池时，遍历期间被弹出的节点不会被复用，因此 fn 不应该长时间阻塞
+// The Range method is used to walk the elements in the stack in LIFO order starting from the top without removing them, the walk stops when fn returns false.
+// The walk is weakly consistent: it never blocks Push and Pop, it never sees elements pushed after the walk started, but it may see elements popped during the walk, each element appears at most once.
+// With a node pool, nodes popped during the walk are not reused, so fn should not block for a long time
+func (s *LockFreeStackOf[T]) Range(fn func(value T) bool) {
+	// 如果启用了节点回收器，进入临界区，保证遍历到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes walked are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	// 从栈顶开始沿着节点链向下遍历，直到栈底的哨兵节点。被弹出的节点仍然指向它的下一个节点，所以遍历可以继续
+	// Start from the top and follow the chain of nodes downwards until the sentinel node at the bottom. A popped node still points to its next node, so the walk can go on
+	node := shd.LoadNodeOf[T](&s.top)
+	for {
+		next := shd.LoadNodeOf[T](&node.Next)
+		if next == nil || !fn(node.Value) {
+			return
+		}
+		node = next
+	}
+}
+
+// Snapshot 方法用于按 LIFO 顺序返回栈中所有元素的副本，但不移除它们。它与 Range 一样是弱一致的
+// The Snapshot method is used to return a copy of all the elements in the stack in LIFO order without removing them. It is weakly consistent just like Range
+func (s *LockFreeStackOf[T]) Snapshot() []T {
+	values := make([]T, 0, snapshotCap(s.Length()))
+	s.Range(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// snapshotCap 函数用于根据长度计算快照的初始容量，并发修改时长度可能短暂为负数
+// The snapshotCap function is used to compute the initial capacity of a snapshot from the length, the length may briefly be negative under concurrent modification
+func snapshotCap(length int64) int {
+	if length < 0 {
+		return 0
+	}
+	return int(length)
+}
+
 // Length 方法用于获取 LockFreeQueue 队列的长度
 // The Length method is used to get the length of the LockFreeQueue queue
-func (s *LockFreeStack) Length() int64 {
+func (s *LockFreeStackOf[T]) Length() int64 {
 	// 使用 atomic.Loadint64 函数获取队列的长度
 	// Use the atomic.Loadint64 function to get the length of the queue
 	return atomic.LoadInt64(&s.length)
@@ -164,20 +509,102 @@ func (s *LockFreeStack) Length() int64 {
 
 // IsEmpty 方法用于判断 LockFreeStack 栈是否为空
 // The IsEmpty method is used to determine whether the LockFreeStack stack is empty
-func (s *LockFreeStack) IsEmpty() bool {
+func (s *LockFreeStackOf[T]) IsEmpty() bool {
 	// 使用 Length 方法获取栈的长度，如果长度为 0，那么栈为空
 	// Use the Length method to get the length of the stack, if the length is 0, then the stack is empty
 	return s.Length() == 0
 }
 
-// Reset 方法用于重置 LockFreeQueue 队列
-// The Reset method is used to reset the LockFreeQueue queue
-func (s *LockFreeStack) Reset() {
-	// 将队列的头节点和尾节点都设置为新创建的节点
-	// Set both the head node and the tail node of the queue to the newly created node
-	s.top = unsafe.Pointer(shd.NewNode(nil))
+// Reset 方法用于重置 LockFreeStack 栈，它会原子地摘下栈中的所有元素并丢弃，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeStack stack, it atomically detaches and discards all the elements in the stack, and can be called concurrently with Push and Pop
+func (s *LockFreeStackOf[T]) Reset() {
+	s.detach(false)
+}
+
+// Drain 方法用于原子地摘下栈中的所有元素，并按弹出的顺序 (LIFO) 返回，栈为空时返回 nil。
+// 它可以与 Push 和 Pop 并发调用，摘下之后推入的元素会留在栈中
+// The Drain method is used to atomically detach all the elements in the stack and return them in pop order (LIFO), nil is returned when the stack is empty.
+// It can be called concurrently with Push and Pop, elements pushed after the detach stay in the stack
+func (s *LockFreeStackOf[T]) Drain() []T {
+	return s.detach(true)
+}
+
+// detach 方法用于通过一次 CAS 操作把栈顶替换为新的哨兵节点，摘下整个节点链，collect 为 true 时按弹出的顺序返回这些节点的值
+// The detach method is used to replace the top of the stack with a new sentinel node with a single CAS operation, detaching the whole chain of nodes, the values of these nodes are returned in pop order when collect is true
+func (s *LockFreeStackOf[T]) detach(collect bool) []T {
+	// 如果启用了节点回收器，进入临界区，保证读取到的节点不会被复用
+	// If the node reclaimer is enabled, enter the critical section to ensure the nodes read are not reused
+	if s.reclaimer != nil {
+		guard := s.reclaimer.Enter()
+		defer s.reclaimer.Exit(guard)
+	}
+
+	// 新的哨兵节点只在栈不为空时创建
+	// The new sentinel node is only created when the stack is not empty
+	var sentinel *shd.NodeOf[T]
+	attempt := 0
+	for {
+		// 获取栈顶元素，如果栈为空，直接返回。之前的尝试创建的哨兵节点从未被其他 goroutine 看到，可以直接放回节点池
+		// Get the top element of the stack, return directly if the stack is empty. A sentinel node created by an earlier attempt was never seen by another goroutine, so it can be put back into the node pool directly
+		top := shd.LoadNodeOf[T](&s.top)
+		if shd.LoadNodeOf[T](&top.Next) == nil {
+			if sentinel != nil && s.pool != nil {
+				s.pool.Put(sentinel)
+			}
+			return nil
+		}
+
+		// 创建新的哨兵节点，如果使用节点池，那么从节点池中获取
+		// Create the new sentinel node, it is taken from the node pool if one is used
+		if sentinel == nil {
+			var zero T
+			sentinel = s.newNode(zero)
+		}
+
+		// 使用 CAS 操作尝试把栈顶替换为新的哨兵节点，成功后整个节点链都被摘下，不会再有其他 goroutine 修改它
+		// Use CAS operation to try to replace the top of the stack with the new sentinel node, the whole chain of nodes is detached once it succeeds and no other goroutine modifies it anymore
+		if shd.CompareAndSwapNode(&s.top, top, sentinel) {
+			var values []T
+			n := int64(0)
+
+			// 遍历被摘下的节点链，需要时读取值，旧的哨兵节点没有值
+			// Walk the detached chain of nodes and read the values if needed, the old sentinel node holds no value
+			for node := top; node != nil; {
+				following := shd.LoadNodeOf[T](&node.Next)
+				if following != nil {
+					if collect {
+						values = append(values, node.Value)
+					}
+					n++
+				}
+
+				// 如果节点池不为空，那么退休节点，否则交给 GC 回收，其他 goroutine 可能仍在读取它们
+				// If the node pool is not nil, then retire the node, otherwise leave it to the GC, other goroutines may still be reading it
+				if s.pool != nil {
+					s.reclaimer.Retire(unsafe.Pointer(node))
+				}
+				node = following
+			}
+
+			// 一次性减少栈的长度，只有 Drain 取走的值计入弹出的数量
+			// Decrease the length of the stack at once, only the values taken by Drain count as popped
+			atomic.AddInt64(&s.length, -n)
+			s.stats.Add(shd.CounterPops, len(values))
+
+			// 返回摘下的值
+			// Return the detached values
+			return values
+		}
+
+		// 其他 goroutine 抢先修改了栈顶，记录一次 CAS 失败，退避之后重试
+		// Another goroutine changed the top first, count a CAS failure, back off and try again
+		s.stats.Inc(shd.CounterCASFailures)
+		s.backoff.Wait(&attempt)
+	}
+}
 
-	// 使用 atomic.Storeint64 函数将队列的长度设置为 0
-	// Use the atomic.Storeint64 function to set the length of the queue to 0
-	atomic.StoreInt64(&s.length, 0)
+// Stats 方法用于返回栈的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the stack, the zero value is returned when the WithStats option is not enabled
+func (s *LockFreeStackOf[T]) Stats() Stats {
+	return s.stats.Snapshot()
 }
@@ -1,8 +1,12 @@
 package stack
 
 import (
+	"context"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -298,3 +302,775 @@ func TestLockFreeStack_WithPool_ParallelDevilMode(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestLockFreeStackOf_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 1000000
+
+	// Create a new generic stack
+	q := NewOf[int]()
+
+	// Test enstacking elements into the stack
+	for i := 0; i < count; i++ {
+		q.Push(i)
+	}
+
+	// Verify the elements in the stack
+	for i := count - 1; i >= 0; i-- {
+		v, ok := q.Pop()
+		assert.True(t, ok, "Failed to pop value")
+		assert.Equal(t, i, v, "Incorrect value in the stack. Expected %d, got %d", i, v)
+	}
+
+	// Verify the stack length
+	assert.Equal(t, int64(0), q.Length(), "Incorrect stack length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeStackOf_ZeroValue(t *testing.T) {
+	q := NewOf[string]()
+
+	// Test that zero values are stored and not mistaken for an empty stack
+	q.Push("")
+	assert.Equal(t, int64(1), q.Length(), "Incorrect stack length. Expected 1, got %d", q.Length())
+
+	v, ok := q.Pop()
+	assert.True(t, ok, "Failed to pop zero value")
+	assert.Equal(t, "", v, "Incorrect value in the stack. Expected empty string, got %s", v)
+
+	// Test popping elements from an empty stack
+	_, ok = q.Pop()
+	assert.False(t, ok, "Popped value from an empty stack")
+}
+
+func TestLockFreeStackOf_WithPool_ParallelDevilMode(t *testing.T) {
+	q := NewWithPoolOf[int]()
+
+	// Test enstacking elements into the stack
+	wg := sync.WaitGroup{}
+	for j := 0; j < 10000; j++ {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				q.Push(i)
+			}(i)
+		}
+	}
+
+	// Verify the elements in the stack
+	for j := 0; j < 10000; j++ {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if v, ok := q.Pop(); ok {
+					assert.True(t, v >= 0 && v < 10, "Incorrect value in the stack. Got %d", v)
+				}
+			}(i)
+		}
+	}
+	wg.Wait()
+}
+
+func TestLockFreeStack_WithPool_StressNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer := 8, 8, 50000
+	total := producers * perProducer
+
+	q := NewWithPool()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				q.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	// Start the consumers, they pop until every value has been seen
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if v := q.Pop(); v != nil {
+					atomic.AddInt32(&seen[v.(int)], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), q.Length(), "Incorrect stack length. Expected 0, got %d", q.Length())
+}
+
+func TestLockFreeStack_PopWait(t *testing.T) {
+	q := New()
+
+	// Push a value after the consumer has started waiting
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.Push(1)
+	}()
+
+	v, err := q.PopWait(context.Background())
+	assert.NoError(t, err, "PopWait returned an error")
+	assert.Equal(t, 1, v, "Incorrect value from PopWait. Expected 1, got %v", v)
+}
+
+func TestLockFreeStack_PopWaitCanceled(t *testing.T) {
+	q := New()
+
+	// Waiting on an empty stack must return once the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	v, err := q.PopWait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Expected a deadline exceeded error")
+	assert.Nil(t, v, "Expected nil value from an empty stack")
+}
+
+func TestLockFreeStack_PopWaitParallel(t *testing.T) {
+	q := New()
+	count := 1000
+
+	// Start the consumers before anything is pushed
+	wg := sync.WaitGroup{}
+	received := int64(0)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count/10; j++ {
+				if _, err := q.PopWait(context.Background()); err == nil {
+					atomic.AddInt64(&received, 1)
+				}
+			}
+		}()
+	}
+
+	// Push the values from several producers
+	for i := 0; i < count; i++ {
+		go q.Push(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(count), received, "Incorrect number of values received. Expected %d, got %d", count, received)
+}
+
+func TestLockFreeStack_PushBatchPopBatch(t *testing.T) {
+	s := New()
+
+	// Push a batch, nil values are ignored and the last value ends up on the top
+	s.PushBatch([]interface{}{1, nil, 2, 3, nil, 4, 5})
+	assert.Equal(t, int64(5), s.Length(), "Incorrect stack length. Expected 5, got %d", s.Length())
+
+	// A batch can be mixed with single pushes
+	s.Push(6)
+
+	// Pop a partial batch, values come out in LIFO order
+	dst := make([]interface{}, 4)
+	n := s.PopBatch(dst)
+	assert.Equal(t, 4, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{6, 5, 4, 3}, dst[:n], "Popped values are incorrect")
+	assert.Equal(t, int64(2), s.Length(), "Incorrect stack length. Expected 2, got %d", s.Length())
+
+	// Pop the rest, the batch is cut short by the bottom of the stack
+	n = s.PopBatch(dst)
+	assert.Equal(t, 2, n, "Incorrect number of popped values")
+	assert.Equal(t, []interface{}{2, 1}, dst[:n], "Popped values are incorrect")
+
+	// The stack is empty now
+	assert.Equal(t, 0, s.PopBatch(dst), "PopBatch on an empty stack should return 0")
+	assert.Nil(t, s.Pop(), "Pop on an empty stack should return nil")
+	assert.True(t, s.IsEmpty(), "Stack should be empty")
+
+	// The stack keeps working normally after the batches
+	s.PushBatch(nil)
+	s.Push(7)
+	assert.Equal(t, 7, s.Pop(), "Popped value is incorrect")
+}
+
+func TestLockFreeStack_WithPool_BatchNoLossNoDuplicate(t *testing.T) {
+	producers, consumers, perProducer, batch := 8, 8, 50000, 16
+	total := producers * perProducer
+
+	s := NewWithPoolOf[int]()
+
+	// seen records how many times each value has been popped
+	seen := make([]int32, total)
+	popped := int64(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values in batches
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			values := make([]int, 0, batch)
+			for i := 0; i < perProducer; i++ {
+				values = append(values, p*perProducer+i)
+				if len(values) == batch || i == perProducer-1 {
+					s.PushBatch(values)
+					values = values[:0]
+				}
+			}
+		}(p)
+	}
+
+	// Start the consumers, half of them pop in batches and the others pop one by one
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			dst := make([]int, batch)
+			for atomic.LoadInt64(&popped) < int64(total) {
+				if c%2 == 0 {
+					n := s.PopBatch(dst)
+					for _, v := range dst[:n] {
+						atomic.AddInt32(&seen[v], 1)
+					}
+					atomic.AddInt64(&popped, int64(n))
+				} else if v, ok := s.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+					atomic.AddInt64(&popped, 1)
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value popped an unexpected number of times", "value %d popped %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), s.Length(), "Incorrect stack length. Expected 0, got %d", s.Length())
+}
+func TestLockFreeStack_Drain(t *testing.T) {
+	s := New()
+
+	// Draining an empty stack returns nil
+	assert.Nil(t, s.Drain(), "Drain on an empty stack should return nil")
+
+	for i := 0; i < 10; i++ {
+		s.Push(i)
+	}
+
+	// All the values are returned in LIFO order
+	assert.Equal(t, []interface{}{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, s.Drain(), "Drained values are incorrect")
+	assert.Equal(t, int64(0), s.Length(), "Incorrect stack length. Expected 0, got %d", s.Length())
+	assert.Nil(t, s.Pop(), "Pop on a drained stack should return nil")
+
+	// The stack keeps working normally after a drain and a reset
+	s.Push(10)
+	s.Reset()
+	assert.True(t, s.IsEmpty(), "Stack should be empty")
+	s.Push(11)
+	assert.Equal(t, 11, s.Pop(), "Popped value is incorrect")
+}
+
+func TestLockFreeStack_WithPool_DrainParallel(t *testing.T) {
+	producers, consumers, perProducer := 4, 4, 50000
+	total := producers * perProducer
+
+	s := NewWithPoolOf[int]()
+
+	// seen records how many times each value has been popped or drained
+	seen := make([]int32, total)
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+	cwg := sync.WaitGroup{}
+
+	// Start the producers, each pushes a distinct range of values
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				s.Push(p*perProducer + i)
+			}
+		}(p)
+	}
+
+	// Start the consumers, one of them keeps draining while the others pop
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			for {
+				// Read the flag first, so an empty result after it means the stack is drained
+				finished := atomic.LoadInt32(&done) == 1
+				got := 0
+				if c == 0 {
+					for _, v := range s.Drain() {
+						atomic.AddInt32(&seen[v], 1)
+						got++
+					}
+				} else if v, ok := s.Pop(); ok {
+					atomic.AddInt32(&seen[v], 1)
+					got++
+				}
+				if got == 0 && finished {
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	atomic.StoreInt32(&done, 1)
+	cwg.Wait()
+
+	// Verify that every value was either popped or drained exactly once
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value handled an unexpected number of times", "value %d handled %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), s.Length(), "Incorrect stack length. Expected 0, got %d", s.Length())
+}
+
+func TestLockFreeStack_ResetParallel(t *testing.T) {
+	s := New()
+
+	wg := sync.WaitGroup{}
+
+	// Push, pop and reset at the same time
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				switch {
+				case i == 0 && j%100 == 0:
+					s.Reset()
+				case i%2 == 0:
+					s.Pop()
+				default:
+					s.Push(j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// After a final reset the length matches the content again
+	s.Reset()
+	assert.Equal(t, int64(0), s.Length(), "Incorrect stack length. Expected 0, got %d", s.Length())
+	assert.Nil(t, s.Pop(), "Pop on a reset stack should return nil")
+}
+
+func TestLockFreeStack_TryPop(t *testing.T) {
+	var s Stack = New()
+
+	// TryPop on an empty stack reports that nothing was popped
+	v, ok := s.TryPop()
+	assert.False(t, ok, "TryPop on an empty stack should return false")
+	assert.Nil(t, v, "TryPop on an empty stack should return nil")
+
+	// nil is dropped by default, but a typed nil pointer is a regular value
+	var p *int
+	s.Push(nil)
+	s.Push(p)
+	s.Push(0)
+	assert.Equal(t, int64(2), s.Length(), "Incorrect stack length. Expected 2, got %d", s.Length())
+
+	v, ok = s.TryPop()
+	assert.True(t, ok, "TryPop should return true")
+	assert.Equal(t, 0, v, "Popped value is incorrect")
+	v, ok = s.TryPop()
+	assert.True(t, ok, "TryPop should return true")
+	assert.Equal(t, p, v, "Popped value is incorrect")
+	_, ok = s.TryPop()
+	assert.False(t, ok, "TryPop on an empty stack should return false")
+}
+
+func TestLockFreeStack_WithAcceptNil(t *testing.T) {
+	for _, s := range []*LockFreeStack{New(WithAcceptNil()), NewWithPool(WithAcceptNil())} {
+		// nil values are stored like any other value
+		s.Push(nil)
+		s.PushBatch([]interface{}{1, nil})
+		assert.Equal(t, int64(3), s.Length(), "Incorrect stack length. Expected 3, got %d", s.Length())
+
+		// TryPop tells a nil value apart from an empty stack
+		for _, expected := range []interface{}{nil, 1, nil} {
+			v, ok := s.TryPop()
+			assert.True(t, ok, "TryPop should return true")
+			assert.Equal(t, expected, v, "Popped value is incorrect")
+		}
+		v, ok := s.TryPop()
+		assert.False(t, ok, "TryPop on an empty stack should return false")
+		assert.Nil(t, v, "TryPop on an empty stack should return nil")
+		assert.True(t, s.IsEmpty(), "Stack should be empty")
+	}
+}
+
+func TestLockFreeStack_Peek(t *testing.T) {
+	s := New()
+
+	// Peek on an empty stack reports that there is no element
+	v, ok := s.Peek()
+	assert.False(t, ok, "Peek on an empty stack should return false")
+	assert.Nil(t, v, "Peek on an empty stack should return nil")
+
+	s.Push(1)
+	s.Push(2)
+
+	// Peek returns the top element without removing it
+	for i := 0; i < 3; i++ {
+		v, ok = s.Peek()
+		assert.True(t, ok, "Peek should return true")
+		assert.Equal(t, 2, v, "Peeked value is incorrect")
+	}
+	assert.Equal(t, int64(2), s.Length(), "Incorrect stack length. Expected 2, got %d", s.Length())
+
+	// After a pop the next element is on the top
+	assert.Equal(t, 2, s.Pop(), "Popped value is incorrect")
+	v, _ = s.Peek()
+	assert.Equal(t, 1, v, "Peeked value is incorrect")
+}
+
+func TestLockFreeStack_PeekParallel(t *testing.T) {
+	for _, s := range []*LockFreeStackOf[int]{NewOf[int](), NewWithPoolOf[int]()} {
+		total := 100000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values, so while nothing is popped the top only grows
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				s.Push(i)
+			}
+		}()
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				last := -1
+				for atomic.LoadInt32(&done) == 0 {
+					if v, ok := s.Peek(); ok {
+						if v < last || v >= total {
+							assert.Failf(t, "Peeked value is out of order", "peeked %d after %d", v, last)
+							return
+						}
+						last = v
+					}
+				}
+			}()
+		}
+		pwg.Wait()
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		// Consumers pop everything while peekers keep reading, every peeked value must be a pushed one
+		done = 0
+		for c := 0; c < 2; c++ {
+			pwg.Add(1)
+			go func() {
+				defer pwg.Done()
+				for {
+					if _, ok := s.Pop(); !ok {
+						return
+					}
+				}
+			}()
+		}
+		for p := 0; p < 4; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					if v, ok := s.Peek(); ok && (v < 0 || v >= total) {
+						assert.Failf(t, "Peeked value is unknown", "peeked %d", v)
+						return
+					}
+				}
+			}()
+		}
+		pwg.Wait()
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		_, ok := s.Peek()
+		assert.False(t, ok, "Peek on an empty stack should return false")
+	}
+}
+
+func TestLockFreeStack_Range(t *testing.T) {
+	s := New()
+
+	// Range and Snapshot on an empty stack see nothing
+	s.Range(func(v interface{}) bool {
+		assert.Fail(t, "Range on an empty stack should not call fn")
+		return true
+	})
+	assert.Empty(t, s.Snapshot(), "Snapshot of an empty stack should be empty")
+
+	for i := 0; i < 5; i++ {
+		s.Push(i)
+	}
+
+	// Snapshot returns the elements in LIFO order without removing them
+	assert.Equal(t, []interface{}{4, 3, 2, 1, 0}, s.Snapshot(), "Snapshot is incorrect")
+	assert.Equal(t, int64(5), s.Length(), "Incorrect stack length. Expected 5, got %d", s.Length())
+
+	// Range stops as soon as fn returns false
+	var seen []interface{}
+	s.Range(func(v interface{}) bool {
+		seen = append(seen, v)
+		return len(seen) < 3
+	})
+	assert.Equal(t, []interface{}{4, 3, 2}, seen, "Range visited incorrect elements")
+
+	// Popped elements are no longer visited
+	s.Pop()
+	assert.Equal(t, []interface{}{3, 2, 1, 0}, s.Snapshot(), "Snapshot is incorrect")
+}
+
+func TestLockFreeStack_SnapshotParallel(t *testing.T) {
+	for _, s := range []*LockFreeStackOf[int]{NewOf[int](), NewWithPoolOf[int]()} {
+		total := 20000
+		done := int32(0)
+
+		pwg := sync.WaitGroup{}
+		wg := sync.WaitGroup{}
+
+		// A single producer pushes increasing values while consumers pop them
+		pwg.Add(1)
+		go func() {
+			defer pwg.Done()
+			for i := 0; i < total; i++ {
+				s.Push(i)
+			}
+		}()
+		for c := 0; c < 2; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					s.Pop()
+				}
+			}()
+		}
+
+		// Every snapshot must be strictly decreasing, so no element is visited twice or out of order
+		for p := 0; p < 2; p++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for atomic.LoadInt32(&done) == 0 {
+					values := s.Snapshot()
+					for i := 1; i < len(values); i++ {
+						if values[i] >= values[i-1] {
+							assert.Failf(t, "Snapshot is out of order", "%d after %d", values[i], values[i-1])
+							return
+						}
+					}
+				}
+			}()
+		}
+
+		pwg.Wait()
+		for !s.IsEmpty() {
+			time.Sleep(time.Millisecond)
+		}
+		atomic.StoreInt32(&done, 1)
+		wg.Wait()
+
+		assert.Empty(t, s.Snapshot(), "Snapshot of an empty stack should be empty")
+	}
+}
+
+func TestLockFreeStack_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	s := New()
+	s.Push(1)
+	s.Pop()
+	assert.Equal(t, Stats{}, s.Stats(), "Statistics should be disabled by default")
+
+	// Count single, batch and drained operations of a stack with a node pool
+	s = NewWithPool(WithStats())
+	for i := 0; i < 100; i++ {
+		s.Push(i)
+	}
+	for i := 0; i < 90; i++ {
+		s.Pop()
+	}
+	s.PushBatch([]interface{}{1, 2, 3})
+	assert.Equal(t, 2, s.PopBatch(make([]interface{}, 2)), "Incorrect number of values popped")
+	assert.Equal(t, 11, len(s.Drain()), "Incorrect number of values drained")
+	assert.Nil(t, s.Pop(), "Popped value from an empty stack")
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(103), stats.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(103), stats.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(1), stats.EmptyPops, "Incorrect number of empty pops")
+	assert.Equal(t, uint64(0), stats.CASFailures, "A single goroutine should never fail a CAS")
+	assert.Equal(t, uint64(104), stats.PoolGets, "Every push and the sentinel node installed by Drain should take a node from the pool")
+	assert.LessOrEqual(t, stats.PoolMisses, stats.PoolGets, "Pool misses should not exceed pool gets")
+}
+
+// countingAllocatorOf is a node allocator that keeps freed nodes in a free list, counts its calls and checks that Put only receives nodes returned by Get
+type countingAllocatorOf[T any] struct {
+	mu         sync.Mutex
+	free       []*NodeOf[T]
+	issued     map[*NodeOf[T]]bool
+	gets, puts int
+	foreign    int
+}
+
+func newCountingAllocatorOf[T any]() *countingAllocatorOf[T] {
+	return &countingAllocatorOf[T]{issued: map[*NodeOf[T]]bool{}}
+}
+
+func (a *countingAllocatorOf[T]) Get() *NodeOf[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.gets++
+	node := new(NodeOf[T])
+	if n := len(a.free); n > 0 {
+		node = a.free[n-1]
+		a.free = a.free[:n-1]
+	}
+	a.issued[node] = true
+	return node
+}
+
+func (a *countingAllocatorOf[T]) Put(n *NodeOf[T]) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.puts++
+	if !a.issued[n] {
+		a.foreign++
+		return
+	}
+	delete(a.issued, n)
+	a.free = append(a.free, n)
+}
+
+func TestLockFreeStack_WithPool(t *testing.T) {
+	// New with WithPool behaves like NewWithPool
+	s := New(WithPool(), WithStats())
+	for i := 0; i < 100; i++ {
+		s.Push(i)
+	}
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, s.Pop(), "Failed to pop a value")
+	}
+	assert.True(t, s.IsEmpty(), "Stack should be empty")
+	assert.Equal(t, uint64(100), s.Stats().PoolGets, "The nodes should come from the node pool")
+}
+
+func TestLockFreeStack_WithNodeAllocator(t *testing.T) {
+	a := newCountingAllocatorOf[interface{}]()
+	s := New(WithNodeAllocator(a))
+
+	// The sentinel node and every pushed value take a node from the allocator
+	for i := 0; i < 100; i++ {
+		s.Push(i)
+	}
+	for i := 0; i < 100; i++ {
+		assert.NotNil(t, s.Pop(), "Failed to pop a value")
+	}
+	assert.Equal(t, 101, a.gets, "The sentinel node and every push should allocate a node")
+
+	// Nodes are only given back once no goroutine references them, which takes a number of retired nodes.
+	// Drain replaces the sentinel node, and the replaced sentinel node must also come from the allocator
+	for i := 0; i < 10000; i++ {
+		s.Push(i)
+		if i%100 == 0 {
+			s.Drain()
+		} else {
+			s.Pop()
+		}
+	}
+	assert.Greater(t, a.puts, 0, "Popped nodes should be given back to the allocator")
+	assert.LessOrEqual(t, a.puts, a.gets, "More nodes were given back than allocated")
+	assert.Equal(t, 0, a.foreign, "Only nodes returned by Get should be given back")
+
+	// The allocator must allocate nodes of the element type
+	assert.Panics(t, func() { NewOf[int](WithNodeAllocator(a)) }, "An allocator of another element type should be rejected")
+}
+
+func TestLockFreeStack_WithNodeAllocatorOf(t *testing.T) {
+	a := newCountingAllocatorOf[int]()
+	s := NewOf[int](WithNodeAllocatorOf[int](a))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines through the typed allocator
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				s.Push(j)
+				s.Pop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, s.IsEmpty(), "Stack should be empty")
+	assert.Equal(t, 80001, a.gets, "The sentinel node and every push should allocate a node")
+	assert.Greater(t, a.puts, 0, "Popped nodes should be given back to the allocator")
+	assert.Equal(t, 0, a.foreign, "Only nodes returned by Get should be given back")
+}
+
+func TestLockFreeStack_WithBackoff(t *testing.T) {
+	var calls atomic.Int64
+	s := NewOf[int](WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	wg := sync.WaitGroup{}
+
+	// Push and pop from many goroutines, single and batched pops both back off once on every CAS failure
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			buf := make([]int, 1)
+			for j := 0; j < 10000; j++ {
+				s.Push(j)
+				runtime.Gosched()
+				if i%2 == 0 {
+					s.Pop()
+				} else {
+					s.PopBatch(buf)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, s.IsEmpty(), "Stack should be empty")
+	assert.Equal(t, s.Stats().CASFailures, uint64(calls.Load()), "Every CAS failure should back off once")
+}
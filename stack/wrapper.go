@@ -0,0 +1,133 @@
+package stack
+
+import (
+	"context"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// LockFreeStack 是一个无锁栈的结构体，元素的类型为 interface{}
+// LockFreeStack is a structure of a lock-free stack, the type of the elements is interface{}
+type LockFreeStack LockFreeStackOf[interface{}]
+
+// New 函数用于创建一个新的无锁栈，可以通过选项修改栈的行为
+// The New function is used to create a new lock-free stack, the behavior of the stack can be modified with options
+func New(opts ...Option) *LockFreeStack {
+	// 调用 newLFS 函数创建一个新的 LockFreeStack 栈，是否使用节点池由选项决定
+	// Call the newLFS function to create a new LockFreeStack stack, whether a node pool is used is decided by the options
+	return (*LockFreeStack)(newLFS[interface{}](opts))
+}
+
+// NewWithPool 函数用于创建一个新的 LockFreeStack 栈，该栈使用一个节点池，可以通过选项修改栈的行为。
+// 它是 New 加上 WithPool 选项的简写
+// The NewWithPool function is used to create a new LockFreeStack stack, this stack uses a node pool, the behavior of the stack can be modified with options.
+// It is a shortcut for New with the WithPool option
+func NewWithPool(opts ...Option) *LockFreeStack {
+	return (*LockFreeStack)(NewWithPoolOf[interface{}](opts...))
+}
+
+// of 方法用于将 LockFreeStack 转换为底层的 LockFreeStackOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeStack to the underlying LockFreeStackOf[interface{}] without any extra cost
+func (s *LockFreeStack) of() *LockFreeStackOf[interface{}] {
+	return (*LockFreeStackOf[interface{}])(s)
+}
+
+// Push 方法用于向无锁栈中推入一个元素
+// The Push method is used to push an element into the lock-free stack
+func (s *LockFreeStack) Push(value interface{}) {
+	// 检查值是否为空, 如果为空并且没有启用 WithAcceptNil 选项，则直接返回
+	// Check if the value is nil, if it is and the WithAcceptNil option is not enabled, return directly
+	if value == nil && !s.acceptNil {
+		return
+	}
+
+	// 将值推入底层的泛型栈中
+	// Push the value into the underlying generic stack
+	s.of().Push(value)
+}
+
+// Pop 方法用于从无锁栈中弹出一个元素，如果栈为空，返回 nil。启用 WithAcceptNil 选项时请使用 TryPop
+// The Pop method is used to pop an element from the lock-free stack, returns nil if the stack is empty. Use TryPop when the WithAcceptNil option is enabled
+func (s *LockFreeStack) Pop() interface{} {
+	// 从底层的泛型栈中弹出一个值，栈为空时该值为 nil
+	// Pop a value from the underlying generic stack, the value is nil when the stack is empty
+	value, _ := s.of().Pop()
+	return value
+}
+
+// TryPop 方法用于从无锁栈中弹出一个元素，第二个返回值表示是否弹出了元素，因此可以区分空栈和 nil 值
+// The TryPop method is used to pop an element from the lock-free stack, the second return value reports whether an element was popped, so an empty stack can be told apart from a nil value
+func (s *LockFreeStack) TryPop() (interface{}, bool) {
+	return s.of().Pop()
+}
+
+// PushBatch 方法用于向无锁栈中按顺序推入一组元素，最后一个元素位于栈顶，其中的 nil 值会被忽略
+// The PushBatch method is used to push a group of elements into the lock-free stack in order, the last element is on the top, nil values are ignored
+func (s *LockFreeStack) PushBatch(values []interface{}) {
+	// 没有启用 WithAcceptNil 选项时，去掉其中的 nil 值
+	// Remove the nil values when the WithAcceptNil option is not enabled
+	if !s.acceptNil {
+		values = shd.CompactNil(values)
+	}
+	s.of().PushBatch(values)
+}
+
+// PopBatch 方法用于从无锁栈中弹出最多 len(dst) 个元素，并按弹出的顺序写入 dst，返回弹出的元素数量
+// The PopBatch method is used to pop up to len(dst) elements from the lock-free stack and write them into dst in pop order, returning the number of elements popped
+func (s *LockFreeStack) PopBatch(dst []interface{}) int {
+	return s.of().PopBatch(dst)
+}
+
+// PopWait 方法用于从无锁栈中弹出一个元素，如果栈为空，会等待直到有元素可用或者 ctx 结束
+// The PopWait method is used to pop an element from the lock-free stack, if the stack is empty it waits until an element is available or ctx is done
+func (s *LockFreeStack) PopWait(ctx context.Context) (interface{}, error) {
+	return s.of().PopWait(ctx)
+}
+
+// Peek 方法用于返回 LockFreeStack 栈的栈顶元素但不移除它，如果栈为空，返回 nil 和 false
+// The Peek method is used to return the top element of the LockFreeStack stack without removing it, returns nil and false if the stack is empty
+func (s *LockFreeStack) Peek() (interface{}, bool) {
+	return s.of().Peek()
+}
+
+// Range 方法用于按 LIFO 顺序遍历 LockFreeStack 栈中的元素，但不移除它们，fn 返回 false 时停止遍历。遍历是弱一致的
+// The Range method is used to walk the elements in the LockFreeStack stack in LIFO order without removing them, the walk stops when fn returns false. The walk is weakly consistent
+func (s *LockFreeStack) Range(fn func(value interface{}) bool) {
+	s.of().Range(fn)
+}
+
+// Snapshot 方法用于按 LIFO 顺序返回 LockFreeStack 栈中所有元素的副本，但不移除它们
+// The Snapshot method is used to return a copy of all the elements in the LockFreeStack stack in LIFO order without removing them
+func (s *LockFreeStack) Snapshot() []interface{} {
+	return s.of().Snapshot()
+}
+
+// Length 方法用于获取 LockFreeStack 栈的长度
+// The Length method is used to get the length of the LockFreeStack stack
+func (s *LockFreeStack) Length() int64 {
+	return s.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeStack 栈是否为空
+// The IsEmpty method is used to determine whether the LockFreeStack stack is empty
+func (s *LockFreeStack) IsEmpty() bool {
+	return s.of().IsEmpty()
+}
+
+// Reset 方法用于重置 LockFreeStack 栈，可以与 Push 和 Pop 并发调用
+// The Reset method is used to reset the LockFreeStack stack, it can be called concurrently with Push and Pop
+func (s *LockFreeStack) Reset() {
+	s.of().Reset()
+}
+
+// Drain 方法用于原子地摘下 LockFreeStack 栈中的所有元素，并按弹出的顺序返回，栈为空时返回 nil
+// The Drain method is used to atomically detach all the elements in the LockFreeStack stack and return them in pop order, nil is returned when the stack is empty
+func (s *LockFreeStack) Drain() []interface{} {
+	return s.of().Drain()
+}
+
+// Stats 方法用于返回 LockFreeStack 栈的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeStack stack, the zero value is returned when the WithStats option is not enabled
+func (s *LockFreeStack) Stats() Stats {
+	return s.of().Stats()
+}
@@ -0,0 +1,59 @@
+package workstealing
+
+// arrayOf 是工作窃取双端队列底层的环形数组，容量总是 2 的幂，位置 i 对应的槽位为 i & mask。
+// 值直接保存在槽位中，推入和弹出不需要分配节点，值通过底部位置和顶部位置的原子操作发布和占用
+// arrayOf is the circular array under the work-stealing deque, its capacity is always a power of two, position i maps to slot i & mask.
+// Values are stored directly in the slots, so pushing and popping allocate no nodes, values are published and claimed through atomic operations on the bottom and top positions
+type arrayOf[T any] struct {
+	// mask 是容量减 1，用于把位置映射到槽位
+	// mask is the capacity minus 1, it is used to map a position to a slot
+	mask int64
+
+	// data 是存储值的槽位切片，只有所有者会写入，窃取者在占用顶部位置之前乐观地读取
+	// data is a slice of slots holding the values, only the owner writes them, stealers read them optimistically before claiming the top position
+	data []T
+}
+
+// newArrayOf 函数用于创建一个容量为 capacity 的环形数组，capacity 必须是 2 的幂
+// The newArrayOf function is used to create a circular array with the given capacity, capacity must be a power of two
+func newArrayOf[T any](capacity int64) *arrayOf[T] {
+	return &arrayOf[T]{
+		mask: capacity - 1,
+		data: make([]T, capacity),
+	}
+}
+
+// capacity 方法用于获取环形数组的容量
+// The capacity method is used to get the capacity of the circular array
+func (a *arrayOf[T]) capacity() int64 {
+	return a.mask + 1
+}
+
+// load 方法用于读取位置 i 的值
+// The load method is used to read the value at position i
+func (a *arrayOf[T]) load(i int64) T {
+	return a.data[i&a.mask]
+}
+
+// store 方法用于把值写入位置 i，只能由所有者调用
+// The store method is used to write the value to position i, it must only be called by the owner
+func (a *arrayOf[T]) store(i int64, value T) {
+	a.data[i&a.mask] = value
+}
+
+// clear 方法用于清空位置 i，让其中的值可以被 GC 回收，只能由所有者调用
+// The clear method is used to clear position i so its value can be collected by the GC, it must only be called by the owner
+func (a *arrayOf[T]) clear(i int64) {
+	var zero T
+	a.data[i&a.mask] = zero
+}
+
+// grow 方法用于创建一个容量翻倍的新环形数组，并把位置 [top, bottom) 的值复制过去，只能由所有者调用
+// The grow method is used to create a new circular array with double the capacity and copy the values in positions [top, bottom) into it, it must only be called by the owner
+func (a *arrayOf[T]) grow(top, bottom int64) *arrayOf[T] {
+	next := newArrayOf[T](a.capacity() * 2)
+	for i := top; i < bottom; i++ {
+		next.store(i, a.load(i))
+	}
+	return next
+}
@@ -0,0 +1,39 @@
+package workstealing
+
+import shd "github.com/shengyanli1982/lockfree/internal/shared"
+
+// Stats 是工作窃取双端队列统计信息的快照，通过 WithStats 选项启用统计
+// Stats is a snapshot of the statistics of a work-stealing deque, statistics are enabled with the WithStats option
+type Stats = shd.Stats
+
+// Backoff 是 CAS 失败之后的退避策略，参数是当前操作连续失败的次数，从 1 开始，通过 WithBackoff 选项设置
+// Backoff is the backoff strategy after a failed CAS, the argument is the number of consecutive failures of the current operation, starting from 1, it is set with the WithBackoff option
+type Backoff = shd.Backoff
+
+// Deque 是一个接口，定义了工作窃取双端队列的基本操作
+// Deque is an interface that defines basic operations of a work-stealing deque
+type Deque = interface {
+	// Push 方法用于在底部推入一个元素，只能由所有者调用
+	// The Push method is used to push an element at the bottom, it must only be called by the owner
+	Push(value interface{})
+
+	// Pop 方法用于从底部移除并返回一个元素，只能由所有者调用
+	// The Pop method is used to remove and return an element from the bottom, it must only be called by the owner
+	Pop() interface{}
+
+	// Steal 方法用于从顶部移除并返回一个元素，可以被任意 goroutine 并发调用
+	// The Steal method is used to remove and return an element from the top, it can be called concurrently by any goroutine
+	Steal() interface{}
+
+	// Reset 方法用于重置/清空队列，只能由所有者调用
+	// The Reset method is used to reset/clear the deque, it must only be called by the owner
+	Reset()
+
+	// Length 方法返回队列的长度（即队列中元素的数量）
+	// The Length method returns the length of the deque (i.e., the number of elements in the deque)
+	Length() int64
+
+	// IsEmpty 方法用于检查队列是否为空
+	// The IsEmpty method is used to check if the deque is empty
+	IsEmpty() bool
+}
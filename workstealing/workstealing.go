@@ -0,0 +1,233 @@
+package workstealing
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	shd "github.com/shengyanli1982/lockfree/internal/shared"
+)
+
+// DefaultCapacity 是环形数组默认的初始容量
+// DefaultCapacity is the default initial capacity of the circular array
+const DefaultCapacity = 64
+
+// LockFreeWorkStealingDequeOf 是一个泛型无锁工作窃取双端队列，T 为队列中元素的类型。
+// 它基于 Chase-Lev 算法：只有所有者可以在底部调用 Push 和 Pop (LIFO)，其他 goroutine 可以并发地在顶部调用 Steal (FIFO)。
+// 底层是一个可以增长的环形数组，值直接保存在数组中，推入和弹出不分配内存，所有者的操作只有在争抢最后一个元素时才需要 CAS 操作。
+// LockFreeWorkStealingDequeOf is a generic lock-free work-stealing deque, T is the type of the elements in the deque.
+// It is based on the Chase-Lev algorithm: only the owner may call Push and Pop at the bottom (LIFO), other goroutines may call Steal at the top concurrently (FIFO).
+// It is backed by a growable circular array that stores the values directly, so pushing and popping do not allocate, and the owner only needs a CAS operation when it races for the last element.
+type LockFreeWorkStealingDequeOf[T any] struct {
+	// top 是顶部位置，窃取者从这里取走元素，单调递增
+	// top is the top position, stealers take elements from here, it increases monotonically
+	top int64
+
+	// _ 用于把窃取者争用的 top 与所有者修改的 bottom 分隔到不同的缓存行，避免伪共享
+	// _ separates top, contended by the stealers, from bottom, modified by the owner, onto different cache lines to avoid false sharing
+	_ shd.CacheLinePad
+
+	// bottom 是底部位置，只有所有者会修改它
+	// bottom is the bottom position, only the owner modifies it
+	bottom int64
+
+	// _ 用于把 bottom 与很少修改的数组指针分隔到不同的缓存行
+	// _ separates bottom from the rarely modified array pointer onto different cache lines
+	_ shd.CacheLinePad
+
+	// array 是指向当前环形数组的指针，增长时会被替换为新的数组，旧数组由 GC 回收
+	// array is a pointer to the current circular array, it is replaced with a new array when growing and the old array is collected by the GC
+	array unsafe.Pointer
+
+	// stats 是统计计数器，仅在启用 WithStats 选项时创建，为 nil 时不统计
+	// stats are the statistics counters, only created when the WithStats option is enabled, nothing is counted when it is nil
+	stats *shd.Counters
+
+	// backoff 是 CAS 失败之后的退避策略，通过 WithBackoff 选项设置，为 nil 时立即重试
+	// backoff is the backoff strategy after a failed CAS, it is set with the WithBackoff option, a failure is retried immediately when it is nil
+	backoff shd.Backoff
+}
+
+// NewOf 函数用于创建一个新的泛型 LockFreeWorkStealingDequeOf 队列，capacity 是环形数组的初始容量，会向上取整为 2 的幂，可以通过选项修改队列的行为
+// The NewOf function is used to create a new generic LockFreeWorkStealingDequeOf deque, capacity is the initial capacity of the circular array and is rounded up to a power of two, the behavior of the deque can be modified with options
+func NewOf[T any](capacity int, opts ...Option) *LockFreeWorkStealingDequeOf[T] {
+	// 如果传入的容量小于或等于 0，那么将容量设置为默认的初始容量
+	// If the passed in capacity is less than or equal to 0, then set the capacity to the default initial capacity
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+
+	// 创建一个新的 LockFreeWorkStealingDequeOf 队列
+	// Create a new LockFreeWorkStealingDequeOf deque
+	d := &LockFreeWorkStealingDequeOf[T]{
+		array: unsafe.Pointer(newArrayOf[T](roundUpPowerOfTwo(int64(capacity)))),
+	}
+
+	// 应用所有的选项，设置退避策略，如果启用了统计，那么创建统计计数器
+	// Apply all the options, set the backoff strategy, if statistics are enabled, then create the statistics counters
+	conf := newConfig(opts)
+	d.backoff = conf.backoff
+	if conf.stats {
+		d.stats = shd.NewCounters()
+	}
+
+	// 返回新创建的队列
+	// Return the newly created deque
+	return d
+}
+
+// roundUpPowerOfTwo 函数用于把 n 向上取整为 2 的幂
+// The roundUpPowerOfTwo function is used to round n up to a power of two
+func roundUpPowerOfTwo(n int64) int64 {
+	c := int64(1)
+	for c < n {
+		c <<= 1
+	}
+	return c
+}
+
+// loadArray 方法用于加载当前的环形数组
+// The loadArray method is used to load the current circular array
+func (d *LockFreeWorkStealingDequeOf[T]) loadArray() *arrayOf[T] {
+	return (*arrayOf[T])(atomic.LoadPointer(&d.array))
+}
+
+// Push 方法用于在底部推入一个元素，只能由所有者调用。环形数组已满时容量会翻倍
+// The Push method is used to push an element at the bottom, it must only be called by the owner. The capacity of the circular array doubles when it is full
+func (d *LockFreeWorkStealingDequeOf[T]) Push(value T) {
+	b := atomic.LoadInt64(&d.bottom)
+	t := atomic.LoadInt64(&d.top)
+	a := d.loadArray()
+
+	// 环形数组已满，复制到一个容量翻倍的新数组中。窃取者可能仍在读取旧数组，旧数组之后不会再被写入，所以其中 [t, b) 的值仍然有效
+	// The circular array is full, copy it into a new array with double the capacity. Stealers may still read the old array, it is never written again so the values in [t, b) stay valid
+	if b-t >= a.capacity() {
+		a = a.grow(t, b)
+		atomic.StorePointer(&d.array, unsafe.Pointer(a))
+	}
+
+	// 先写入值，再增加底部位置发布它
+	// Write the value first, then publish it by increasing the bottom position
+	a.store(b, value)
+	atomic.StoreInt64(&d.bottom, b+1)
+	d.stats.Inc(shd.CounterPushes)
+}
+
+// Pop 方法用于从底部弹出一个元素 (LIFO)，只能由所有者调用，如果队列为空，返回 T 的零值和 false
+// The Pop method is used to pop an element from the bottom (LIFO), it must only be called by the owner, returns the zero value of T and false if the deque is empty
+func (d *LockFreeWorkStealingDequeOf[T]) Pop() (T, bool) {
+	var zero T
+
+	// 先减少底部位置，让窃取者看到这个位置已经被所有者占用
+	// Decrease the bottom position first, so stealers see that the position is taken by the owner
+	b := atomic.LoadInt64(&d.bottom) - 1
+	a := d.loadArray()
+	atomic.StoreInt64(&d.bottom, b)
+	t := atomic.LoadInt64(&d.top)
+
+	// 队列为空，恢复底部位置
+	// The deque is empty, restore the bottom position
+	if t > b {
+		atomic.StoreInt64(&d.bottom, b+1)
+		d.stats.Inc(shd.CounterEmptyPops)
+		return zero, false
+	}
+
+	value := a.load(b)
+
+	// 这是最后一个元素，需要通过 CAS 操作与窃取者争抢，失败说明它已经被窃取
+	// This is the last element, race the stealers for it with a CAS operation, a failure means it has been stolen
+	if t == b {
+		won := atomic.CompareAndSwapInt64(&d.top, t, t+1)
+		atomic.StoreInt64(&d.bottom, b+1)
+		if !won {
+			// 窃取者已经取走了值，槽位只会再被所有者写入，可以直接清空
+			// The stealer has taken the value, the slot is only written by the owner again, so it can be cleared right away
+			a.clear(b)
+			d.stats.Inc(shd.CounterCASFailures)
+			d.stats.Inc(shd.CounterEmptyPops)
+			return zero, false
+		}
+	}
+
+	// 清空槽位，让值可以被 GC 回收
+	// Clear the slot so the value can be collected by the GC
+	a.clear(b)
+	d.stats.Inc(shd.CounterPops)
+	return value, true
+}
+
+// Steal 方法用于从顶部窃取一个元素 (FIFO)，可以被任意 goroutine 并发调用，如果队列为空，返回 T 的零值和 false
+// The Steal method is used to steal an element from the top (FIFO), it can be called concurrently by any goroutine, returns the zero value of T and false if the deque is empty
+func (d *LockFreeWorkStealingDequeOf[T]) Steal() (T, bool) {
+	attempt := 0
+	for {
+		t := atomic.LoadInt64(&d.top)
+		b := atomic.LoadInt64(&d.bottom)
+
+		// 队列为空，返回 T 的零值和 false
+		// The deque is empty, return the zero value of T and false
+		if t >= b {
+			d.stats.Inc(shd.CounterEmptyPops)
+			var zero T
+			return zero, false
+		}
+
+		// 先乐观地复制值，再通过 CAS 操作占用顶部位置。如果 t 已经过期，所有者可能正在这个槽位写入新的值，
+		// 复制的结果可能不完整，但此时 CAS 一定失败，复制的结果会被丢弃，所以 CAS 成功之前不能使用它
+		// Copy the value optimistically first, then claim the top position with a CAS operation. If t is stale, the owner may be writing a new value into the slot,
+		// so the copy may be torn, but then the CAS always fails and the copy is discarded, which is why it must not be used before the CAS succeeds
+		a := d.loadArray()
+		shd.RaceDisable()
+		value := a.load(t)
+		shd.RaceEnable()
+		if atomic.CompareAndSwapInt64(&d.top, t, t+1) {
+			// 窃取者不能清空槽位，因为所有者可能已经在这个槽位写入了新的值。槽位中的旧值会保留到所有者再次写入或者弹出这个槽位
+			// A stealer must not clear the slot, as the owner may already be writing a new value into it. The old value stays in the slot until the owner writes or pops the slot again
+			d.stats.Inc(shd.CounterPops)
+			return value, true
+		}
+
+		// 其他窃取者或者所有者抢先占用了顶部位置，记录一次 CAS 失败，退避之后重试
+		// Another stealer or the owner claimed the top position first, count a CAS failure, back off and try again
+		d.stats.Inc(shd.CounterCASFailures)
+		d.backoff.Wait(&attempt)
+	}
+}
+
+// Length 方法用于获取队列的长度，并发调用时只是一个近似值
+// The Length method is used to get the length of the deque, it is only an approximation under concurrent calls
+func (d *LockFreeWorkStealingDequeOf[T]) Length() int64 {
+	n := atomic.LoadInt64(&d.bottom) - atomic.LoadInt64(&d.top)
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// IsEmpty 方法用于判断队列是否为空
+// The IsEmpty method is used to determine whether the deque is empty
+func (d *LockFreeWorkStealingDequeOf[T]) IsEmpty() bool {
+	return d.Length() == 0
+}
+
+// Capacity 方法用于获取当前环形数组的容量
+// The Capacity method is used to get the capacity of the current circular array
+func (d *LockFreeWorkStealingDequeOf[T]) Capacity() int64 {
+	return d.loadArray().capacity()
+}
+
+// Reset 方法用于清空队列，只能由所有者调用，可以与 Steal 并发调用
+// The Reset method is used to clear the deque, it must only be called by the owner, and can be called concurrently with Steal
+func (d *LockFreeWorkStealingDequeOf[T]) Reset() {
+	for {
+		if _, ok := d.Pop(); !ok {
+			return
+		}
+	}
+}
+
+// Stats 方法用于返回队列的统计信息快照，没有启用 WithStats 选项时返回零值。成功的窃取计入 Pops，队列为空时的窃取计入 EmptyPops
+// The Stats method is used to return a snapshot of the statistics of the deque, the zero value is returned when the WithStats option is not enabled. Successful steals count as Pops, steals that find the deque empty count as EmptyPops
+func (d *LockFreeWorkStealingDequeOf[T]) Stats() Stats {
+	return d.stats.Snapshot()
+}
@@ -0,0 +1,301 @@
+package workstealing
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeWorkStealingDeque_Standard(t *testing.T) {
+	// Number of elements to test
+	count := 1000000
+
+	// Create a new deque with a small initial capacity so it has to grow
+	d := New(4)
+
+	// Test pushing elements at the bottom
+	for i := 0; i < count; i++ {
+		d.Push(i)
+	}
+	assert.Equal(t, int64(count), d.Length(), "Incorrect deque length. Expected %d, got %d", count, d.Length())
+	assert.GreaterOrEqual(t, d.Capacity(), int64(count), "Capacity should have grown to hold all the elements")
+
+	// Verify that the owner pops the elements in LIFO order
+	for i := count - 1; i >= 0; i-- {
+		v := d.Pop()
+		assert.Equal(t, i, v, "Incorrect value in the deque. Expected %d, got %d", i, v)
+	}
+
+	// Verify the deque length
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+}
+
+func TestLockFreeWorkStealingDeque_Steal(t *testing.T) {
+	d := New(4)
+
+	for i := 0; i < 100; i++ {
+		d.Push(i)
+	}
+
+	// Verify that stealers take the elements in FIFO order
+	for i := 0; i < 50; i++ {
+		v := d.Steal()
+		assert.Equal(t, i, v, "Incorrect stolen value. Expected %d, got %d", i, v)
+	}
+
+	// Verify that the owner still pops the newest elements
+	for i := 99; i >= 50; i-- {
+		v := d.Pop()
+		assert.Equal(t, i, v, "Incorrect value in the deque. Expected %d, got %d", i, v)
+	}
+
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+}
+
+func TestLockFreeWorkStealingDeque_EmptyPop(t *testing.T) {
+	d := New(0)
+
+	// Test popping and stealing from an empty deque
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, d.Pop(), "Expected nil value from an empty deque")
+		assert.Nil(t, d.Steal(), "Expected nil value from an empty deque")
+	}
+	assert.Equal(t, int64(DefaultCapacity), d.Capacity(), "Incorrect capacity. Expected %d, got %d", DefaultCapacity, d.Capacity())
+
+	// Test that nil values are ignored
+	d.Push(nil)
+	assert.True(t, d.IsEmpty(), "Deque should be empty")
+}
+
+func TestLockFreeWorkStealingDeque_Capacity(t *testing.T) {
+	// Test that the capacity is rounded up to a power of two
+	assert.Equal(t, int64(8), New(5).Capacity(), "Capacity should be rounded up to 8")
+	assert.Equal(t, int64(8), New(8).Capacity(), "Capacity should stay 8")
+	assert.Equal(t, int64(1), New(1).Capacity(), "Capacity should stay 1")
+}
+
+func TestLockFreeWorkStealingDequeOf_ZeroValue(t *testing.T) {
+	d := NewOf[int](0)
+
+	// Test that zero values are stored and not mistaken for an empty deque
+	d.Push(0)
+	v, ok := d.Steal()
+	assert.True(t, ok, "Failed to steal zero value")
+	assert.Equal(t, 0, v, "Incorrect stolen value. Expected 0, got %d", v)
+
+	d.Push(0)
+	v, ok = d.Pop()
+	assert.True(t, ok, "Failed to pop zero value")
+	assert.Equal(t, 0, v, "Incorrect value in the deque. Expected 0, got %d", v)
+
+	// Test popping elements from an empty deque
+	_, ok = d.Pop()
+	assert.False(t, ok, "Popped value from an empty deque")
+	_, ok = d.Steal()
+	assert.False(t, ok, "Stole value from an empty deque")
+}
+
+func TestLockFreeWorkStealingDeque_StealParallelNoLossNoDuplicate(t *testing.T) {
+	stealers, total := 8, 400000
+
+	d := NewOf[int](2)
+
+	// seen records how many times each value has been taken
+	seen := make([]int32, total)
+	taken := int64(0)
+	take := func(v int) {
+		atomic.AddInt32(&seen[v], 1)
+		atomic.AddInt64(&taken, 1)
+	}
+
+	wg := sync.WaitGroup{}
+
+	// Start the stealers, they steal until every value has been taken
+	for s := 0; s < stealers; s++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt64(&taken) < int64(total) {
+				if v, ok := d.Steal(); ok {
+					take(v)
+				}
+			}
+		}()
+	}
+
+	// The owner pushes every value and pops some of them back, so it races the stealers for the last elements
+	for i := 0; i < total; i++ {
+		d.Push(i)
+		if i%3 == 0 {
+			if v, ok := d.Pop(); ok {
+				take(v)
+			}
+		}
+	}
+	for {
+		v, ok := d.Pop()
+		if !ok {
+			break
+		}
+		take(v)
+	}
+	wg.Wait()
+
+	// Verify that no value was lost or duplicated
+	for v, n := range seen {
+		if n != 1 {
+			assert.Failf(t, "Value taken an unexpected number of times", "value %d taken %d times", v, n)
+			break
+		}
+	}
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+}
+
+func TestLockFreeWorkStealingDeque_ResetParallel(t *testing.T) {
+	d := New(2)
+	done := int32(0)
+
+	wg := sync.WaitGroup{}
+
+	// Start the stealers
+	for s := 0; s < 4; s++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&done) == 0 {
+				d.Steal()
+			}
+		}()
+	}
+
+	// The owner pushes and resets at the same time as the stealers
+	for i := 0; i < 100000; i++ {
+		d.Push(i)
+		if i%100 == 0 {
+			d.Reset()
+		}
+	}
+	d.Reset()
+	atomic.StoreInt32(&done, 1)
+	wg.Wait()
+
+	assert.Equal(t, int64(0), d.Length(), "Incorrect deque length. Expected 0, got %d", d.Length())
+	assert.Nil(t, d.Steal(), "Steal on a reset deque should return nil")
+}
+
+func TestLockFreeWorkStealingDeque_Stats(t *testing.T) {
+	// Statistics are disabled by default and the snapshot is the zero value
+	d := New(4)
+	d.Push(1)
+	d.Pop()
+	assert.Equal(t, Stats{}, d.Stats(), "Statistics should be disabled by default")
+
+	// Pops by the owner and successful steals both count as pops
+	d = New(4, WithStats())
+	for i := 0; i < 10; i++ {
+		d.Push(i)
+	}
+	for i := 0; i < 5; i++ {
+		d.Pop()
+		d.Steal()
+	}
+	d.Pop()
+	d.Steal()
+
+	s := d.Stats()
+	assert.Equal(t, uint64(10), s.Pushes, "Incorrect number of pushes")
+	assert.Equal(t, uint64(10), s.Pops, "Incorrect number of pops")
+	assert.Equal(t, uint64(2), s.EmptyPops, "Incorrect number of empty pops and steals")
+	assert.Equal(t, uint64(0), s.CASFailures, "A single goroutine should never fail a CAS")
+}
+
+func TestLockFreeWorkStealingDeque_WithBackoff(t *testing.T) {
+	var calls atomic.Int64
+	d := NewOf[int](1024, WithStats(), WithBackoff(func(attempt int) {
+		assert.GreaterOrEqual(t, attempt, 1, "Attempts should start from 1")
+		calls.Add(1)
+		runtime.Gosched()
+	}))
+
+	// The owner fills the deque before the thieves start
+	for i := 0; i < 10000; i++ {
+		d.Push(i)
+	}
+
+	wg := sync.WaitGroup{}
+	stolen := atomic.Int64{}
+
+	// Many thieves steal until the deque is empty
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, ok := d.Steal(); !ok {
+					return
+				}
+				stolen.Add(1)
+				runtime.Gosched()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(10000), stolen.Load(), "Every value should be stolen exactly once")
+	assert.LessOrEqual(t, uint64(calls.Load()), d.Stats().CASFailures, "Only CAS failures between thieves should back off")
+}
+
+func TestLockFreeWorkStealingDeque_ZeroAllocs(t *testing.T) {
+	d := NewOf[int](16)
+
+	// Values are stored directly in the circular array, so Push, Pop and Steal do not allocate
+	allocs := testing.AllocsPerRun(1000, func() {
+		d.Push(1)
+		d.Pop()
+	})
+	assert.Equal(t, float64(0), allocs, "Push and Pop should not allocate, got %v allocs per run", allocs)
+
+	allocs = testing.AllocsPerRun(1000, func() {
+		d.Push(1)
+		d.Steal()
+	})
+	assert.Equal(t, float64(0), allocs, "Push and Steal should not allocate, got %v allocs per run", allocs)
+}
+
+func TestLockFreeWorkStealingDeque_StealTornParallel(t *testing.T) {
+	// Every word of a value is the same, a torn copy would mix words of different values
+	d := NewOf[[4]int](2)
+	done := atomic.Bool{}
+	wg := sync.WaitGroup{}
+
+	// The thieves check every value they steal while the owner keeps wrapping around the small array
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() {
+				if v, ok := d.Steal(); ok {
+					assert.Equal(t, [4]int{v[0], v[0], v[0], v[0]}, v, "Stolen value is torn")
+				}
+				runtime.Gosched()
+			}
+		}()
+	}
+
+	for i := 0; i < 100000; i++ {
+		d.Push([4]int{i, i, i, i})
+		if i%3 == 0 {
+			if v, ok := d.Pop(); ok {
+				assert.Equal(t, [4]int{v[0], v[0], v[0], v[0]}, v, "Popped value is torn")
+			}
+		}
+		if d.Length() > 2 {
+			runtime.Gosched()
+		}
+	}
+	done.Store(true)
+	wg.Wait()
+}
@@ -0,0 +1,77 @@
+package workstealing
+
+// LockFreeWorkStealingDeque 是一个无锁工作窃取双端队列结构体，元素的类型为 interface{}
+// LockFreeWorkStealingDeque is a lock-free work-stealing deque struct, the type of the elements is interface{}
+type LockFreeWorkStealingDeque LockFreeWorkStealingDequeOf[interface{}]
+
+// New 函数用于创建一个新的 LockFreeWorkStealingDeque 队列，capacity 是环形数组的初始容量，可以通过选项修改队列的行为
+// The New function is used to create a new LockFreeWorkStealingDeque deque, capacity is the initial capacity of the circular array, the behavior of the deque can be modified with options
+func New(capacity int, opts ...Option) *LockFreeWorkStealingDeque {
+	// 调用 NewOf 函数创建一个新的队列，元素的类型为 interface{}
+	// Call the NewOf function to create a new deque, the type of the elements is interface{}
+	return (*LockFreeWorkStealingDeque)(NewOf[interface{}](capacity, opts...))
+}
+
+// of 方法用于将 LockFreeWorkStealingDeque 转换为底层的 LockFreeWorkStealingDequeOf[interface{}]，不会产生额外的开销
+// The of method converts LockFreeWorkStealingDeque to the underlying LockFreeWorkStealingDequeOf[interface{}] without any extra cost
+func (d *LockFreeWorkStealingDeque) of() *LockFreeWorkStealingDequeOf[interface{}] {
+	return (*LockFreeWorkStealingDequeOf[interface{}])(d)
+}
+
+// Push 方法用于在 LockFreeWorkStealingDeque 队列的底部推入一个值，只能由所有者调用
+// The Push method is used to push a value at the bottom of the LockFreeWorkStealingDeque deque, it must only be called by the owner
+func (d *LockFreeWorkStealingDeque) Push(value interface{}) {
+	// 检查值是否为空, 如果为空则直接返回
+	// Check if the value is nil, if it is, return directly
+	if value == nil {
+		return
+	}
+
+	// 将值添加到底层的泛型队列中
+	// Add the value to the underlying generic deque
+	d.of().Push(value)
+}
+
+// Pop 方法用于从 LockFreeWorkStealingDeque 队列的底部移除并返回一个值，只能由所有者调用，如果队列为空，返回 nil
+// The Pop method is used to remove and return a value from the bottom of the LockFreeWorkStealingDeque deque, it must only be called by the owner, returns nil if the deque is empty
+func (d *LockFreeWorkStealingDeque) Pop() interface{} {
+	value, _ := d.of().Pop()
+	return value
+}
+
+// Steal 方法用于从 LockFreeWorkStealingDeque 队列的顶部移除并返回一个值，可以被任意 goroutine 并发调用，如果队列为空，返回 nil
+// The Steal method is used to remove and return a value from the top of the LockFreeWorkStealingDeque deque, it can be called concurrently by any goroutine, returns nil if the deque is empty
+func (d *LockFreeWorkStealingDeque) Steal() interface{} {
+	value, _ := d.of().Steal()
+	return value
+}
+
+// Length 方法用于获取 LockFreeWorkStealingDeque 队列的长度
+// The Length method is used to get the length of the LockFreeWorkStealingDeque deque
+func (d *LockFreeWorkStealingDeque) Length() int64 {
+	return d.of().Length()
+}
+
+// IsEmpty 方法用于判断 LockFreeWorkStealingDeque 队列是否为空
+// The IsEmpty method is used to determine whether the LockFreeWorkStealingDeque deque is empty
+func (d *LockFreeWorkStealingDeque) IsEmpty() bool {
+	return d.of().IsEmpty()
+}
+
+// Capacity 方法用于获取 LockFreeWorkStealingDeque 队列当前环形数组的容量
+// The Capacity method is used to get the capacity of the current circular array of the LockFreeWorkStealingDeque deque
+func (d *LockFreeWorkStealingDeque) Capacity() int64 {
+	return d.of().Capacity()
+}
+
+// Reset 方法用于清空 LockFreeWorkStealingDeque 队列，只能由所有者调用
+// The Reset method is used to clear the LockFreeWorkStealingDeque deque, it must only be called by the owner
+func (d *LockFreeWorkStealingDeque) Reset() {
+	d.of().Reset()
+}
+
+// Stats 方法用于返回 LockFreeWorkStealingDeque 队列的统计信息快照，没有启用 WithStats 选项时返回零值
+// The Stats method is used to return a snapshot of the statistics of the LockFreeWorkStealingDeque deque, the zero value is returned when the WithStats option is not enabled
+func (d *LockFreeWorkStealingDeque) Stats() Stats {
+	return d.of().Stats()
+}